@@ -0,0 +1,124 @@
+// kafka-dlq - просмотр и переотправка сообщений из DLQ топиков.
+//
+//	kafka-dlq list   -topic user-registered.dlq [-partition N] [-from OFFSET] [-limit N]
+//	kafka-dlq replay -topic user-registered.dlq [-partition N] [-from OFFSET] [-limit N]
+//
+// Брокеры берутся из -brokers или KAFKA_BROKERS.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/dlq"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+)
+
+const usage = "usage: kafka-dlq list|replay -topic <topic>.dlq [-brokers host:port,...] [-partition N] [-from OFFSET] [-limit N]"
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "kafka-dlq:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+
+	cmd := args[0]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	brokers := fs.String("brokers", os.Getenv("KAFKA_BROKERS"), "kafka brokers, comma separated")
+	topic := fs.String("topic", "", "dlq topic")
+	partition := fs.Int("partition", -1, "only this partition, -1 for all")
+	from := fs.Int64("from", 0, "start offset in each partition")
+	limit := fs.Int("limit", 0, "max messages, 0 for all")
+	timeout := fs.Duration("timeout", time.Minute, "overall timeout")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *brokers == "" || *topic == "" {
+		return errors.New(usage)
+	}
+
+	filter := dlq.Filter{FromOffset: *from, Limit: *limit}
+	if *partition >= 0 {
+		filter.Partitions = []int32{int32(*partition)} //nolint:gosec // номер партиции
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	// replay отправляет с исходным ключом: партиция должна совпасть с той, куда его писал продюсер сервиса
+	cfg := producer.NewSyncConfig(producer.WithHashPartitioner())
+	client, err := sarama.NewClient(strings.Split(*brokers, ","), cfg)
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close() //nolint:errcheck // CLI завершается
+
+	switch cmd {
+	case "list":
+		return list(ctx, client, *topic, filter)
+	case "replay":
+		return replay(ctx, client, *topic, filter)
+	default:
+		return errors.New(usage)
+	}
+}
+
+func list(ctx context.Context, client sarama.Client, topic string, filter dlq.Filter) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PARTITION\tOFFSET\tKEY\tATTEMPTS\tORIGINAL\tFAILED AT\tERROR")
+
+	err := dlq.Scan(ctx, client, topic, filter, func(r dlq.Record) error {
+		_, err := fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%s/%d@%d\t%s\t%s\n",
+			r.Message.Partition, r.Message.Offset, r.Message.Key, r.Attempts,
+			r.OriginalTopic, r.OriginalPartition, r.OriginalOffset,
+			r.FailedAt.Format(time.RFC3339), r.Error,
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func replay(ctx context.Context, client sarama.Client, topic string, filter dlq.Filter) error {
+	sp, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("create producer: %w", err)
+	}
+	// топик каждого сообщения задаётся из x-original-topic
+	p := producer.NewSync(sp, "")
+	defer p.Close() //nolint:errcheck // CLI завершается
+
+	replayed := 0
+	err = dlq.Scan(ctx, client, topic, filter, func(r dlq.Record) error {
+		if err := dlq.Replay(ctx, p, r); err != nil {
+			return fmt.Errorf("replay %d@%d: %w", r.Message.Partition, r.Message.Offset, err)
+		}
+		replayed++
+		return nil
+	})
+
+	fmt.Printf("replayed %d messages from %s\n", replayed, topic)
+
+	return err
+}
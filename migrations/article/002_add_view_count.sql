@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE articles ADD COLUMN view_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_articles_view_count_id ON articles (view_count DESC, id DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_articles_view_count_id;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS view_count;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE articles ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('russian', title || ' ' || content)) STORED;
+
+CREATE INDEX idx_articles_title_trgm ON articles USING GIN (title gin_trgm_ops);
+CREATE INDEX idx_articles_search_vector ON articles USING GIN (search_vector);
+CREATE INDEX idx_articles_author_id ON articles (author_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_articles_author_id;
+DROP INDEX IF EXISTS idx_articles_search_vector;
+DROP INDEX IF EXISTS idx_articles_title_trgm;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
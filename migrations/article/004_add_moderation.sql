@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE articles ADD COLUMN hidden_at TIMESTAMPTZ;
+
+CREATE TABLE article_reports (
+    id          UUID PRIMARY KEY,
+    article_id  UUID NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    reporter_id UUID NOT NULL,
+    reason      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ
+);
+
+-- одна открытая жалоба от пользователя на статью
+CREATE UNIQUE INDEX idx_article_reports_open_reporter ON article_reports (article_id, reporter_id) WHERE resolved_at IS NULL;
+
+CREATE INDEX idx_article_reports_open ON article_reports (article_id) WHERE resolved_at IS NULL;
+
+-- журнал действий модераторов; без внешнего ключа, чтобы записи об удалении переживали статью
+CREATE TABLE moderation_log (
+    id           UUID PRIMARY KEY,
+    article_id   UUID NOT NULL,
+    moderator_id UUID NOT NULL,
+    action       TEXT NOT NULL,
+    reason       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_moderation_log_article ON moderation_log (article_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS moderation_log;
+
+DROP TABLE IF EXISTS article_reports;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS hidden_at;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN content_type TEXT NOT NULL DEFAULT 'application/json';
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS content_type;
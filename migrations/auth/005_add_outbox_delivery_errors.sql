@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE outbox ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE outbox ADD COLUMN last_error TEXT;
+
+-- +goose Down
+ALTER TABLE outbox DROP COLUMN IF EXISTS last_error;
+ALTER TABLE outbox DROP COLUMN IF EXISTS attempts;
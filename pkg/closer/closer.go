@@ -22,19 +22,23 @@ const shutdownTimeout = 5 * time.Second
 // если ресурс завис, shutdown завершится по таймауту
 // Panic recovery
 // второй Cmd+C принудительно завершает процесс
+// пауза между Draining и закрытием ресурсов, чтобы балансировщик успел снять инстанс
 // логирует имя ресурса и время закрытия
 type Closer struct {
-	mu    sync.Mutex
-	once  sync.Once
-	done  chan struct{}
-	funcs []func(context.Context) error
+	mu         sync.Mutex
+	once       sync.Once
+	draining   chan struct{}
+	done       chan struct{}
+	drainDelay time.Duration
+	funcs      []func(context.Context) error
 }
 
 var globalCloser = NewCloser()
 
 func NewCloser() *Closer {
 	return &Closer{
-		done: make(chan struct{}),
+		draining: make(chan struct{}),
+		done:     make(chan struct{}),
 	}
 }
 
@@ -46,6 +50,17 @@ func Wait() {
 	<-globalCloser.done
 }
 
+// Draining закрывается в начале shutdown, до закрытия первого ресурса. По нему readiness перестаёт пускать трафик.
+func Draining() <-chan struct{} {
+	return globalCloser.Draining()
+}
+
+// SetDrainDelay задаёт паузу между Draining и закрытием первого ресурса: readiness уже отвечает 503,
+// а серверы ещё принимают запросы, пока балансировщик не перестал их слать. Таймаут shutdown растёт на ту же паузу.
+func SetDrainDelay(d time.Duration) {
+	globalCloser.SetDrainDelay(d)
+}
+
 func Listen(signals ...os.Signal) {
 	go globalCloser.listen(signals...)
 }
@@ -87,14 +102,34 @@ func (c *Closer) AddNamed(name string, f func(context.Context) error) {
 	})
 }
 
+func (c *Closer) Draining() <-chan struct{} {
+	return c.draining
+}
+
+func (c *Closer) SetDrainDelay(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.drainDelay = d
+}
+
+func (c *Closer) getDrainDelay() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.drainDelay
+}
+
 func (c *Closer) CloseAll(ctx context.Context) error {
 	var result error
 
 	c.once.Do(func() {
+		close(c.draining)
 		defer close(c.done)
 
 		c.mu.Lock()
 		funcs := c.funcs
+		drainDelay := c.drainDelay
 		c.funcs = nil
 		c.mu.Unlock()
 
@@ -103,6 +138,18 @@ func (c *Closer) CloseAll(ctx context.Context) error {
 		}
 
 		log := logger.Logger()
+
+		if drainDelay > 0 {
+			log.Info().Dur("delay", drainDelay).Msg("draining before shutdown")
+
+			timer := time.NewTimer(drainDelay)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+			}
+		}
+
 		log.Info().Msg("starting graceful shutdown")
 
 		for i := len(funcs) - 1; i >= 0; i-- {
@@ -143,7 +190,7 @@ func (c *Closer) listen(signals ...os.Signal) {
 		log := logger.Logger()
 		log.Info().Str("signal", sig.String()).Msg("received signal, shutting down")
 
-		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout+c.getDrainDelay())
 		defer cancel()
 
 		if err := c.CloseAll(ctx); err != nil {
@@ -0,0 +1,55 @@
+package closer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCloseAll_DrainDelay(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	c := NewCloser()
+	c.SetDrainDelay(delay)
+
+	var closedAfter time.Duration
+	start := time.Now()
+	c.Add(func(context.Context) error {
+		select {
+		case <-c.Draining():
+		default:
+			t.Error("resource closed before draining started")
+		}
+		closedAfter = time.Since(start)
+		return nil
+	})
+
+	if err := c.CloseAll(context.Background()); err != nil {
+		t.Fatalf("CloseAll() error = %v", err)
+	}
+	if closedAfter < delay {
+		t.Errorf("resource closed after %v, want at least %v", closedAfter, delay)
+	}
+}
+
+func TestCloseAll_DrainDelayRespectsContext(t *testing.T) {
+	c := NewCloser()
+	c.SetDrainDelay(time.Minute)
+
+	c.Add(func(context.Context) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.CloseAll(ctx) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("CloseAll() error = nil, want context deadline")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CloseAll() waited for the full drain delay after context expired")
+	}
+}
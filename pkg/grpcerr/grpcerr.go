@@ -0,0 +1,112 @@
+// Package grpcerr - ошибки gRPC с машиночитаемыми причинами. Причина кладётся в google.rpc.ErrorInfo,
+// ошибки полей - в google.rpc.BadRequest. Gateway отдаёт причину клиенту как code в problem+json,
+// поэтому значения Reason* - часть публичного контракта и не меняются.
+package grpcerr
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// Domain - ErrorInfo.domain для всех сервисов проекта.
+const Domain = "habr"
+
+const (
+	ReasonValidationFailed = "VALIDATION_FAILED"
+	ReasonInternal         = "INTERNAL"
+
+	ReasonInvalidEmail            = "INVALID_EMAIL"
+	ReasonInvalidPassword         = "INVALID_PASSWORD"
+	ReasonEmailTaken              = "EMAIL_TAKEN"
+	ReasonInvalidCredentials      = "INVALID_CREDENTIALS"
+	ReasonInvalidRefreshToken     = "INVALID_REFRESH_TOKEN"
+	ReasonInvalidVerificationCode = "INVALID_VERIFICATION_CODE"
+	ReasonUserNotFound            = "USER_NOT_FOUND"
+	ReasonInvalidRole             = "INVALID_ROLE"
+
+	ReasonArticleNotFound         = "ARTICLE_NOT_FOUND"
+	ReasonNotAuthor               = "NOT_AUTHOR"
+	ReasonInvalidTitle            = "INVALID_TITLE"
+	ReasonInvalidContent          = "INVALID_CONTENT"
+	ReasonInvalidCursor           = "INVALID_CURSOR"
+	ReasonInvalidReportReason     = "INVALID_REPORT_REASON"
+	ReasonInvalidModerationAction = "INVALID_MODERATION_ACTION"
+)
+
+// FieldViolation - ошибка конкретного поля запроса. Field - путь через точку, как в proto.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Details - разобранная ошибка gRPC. Reason пустой, если сервис не приложил ErrorInfo.
+type Details struct {
+	Code     codes.Code
+	Message  string
+	Reason   string
+	Metadata map[string]string
+	Fields   []FieldViolation
+}
+
+// New - ошибка с причиной в ErrorInfo.
+func New(code codes.Code, reason, msg string) error {
+	return withDetails(status.New(code, msg), &errdetails.ErrorInfo{Reason: reason, Domain: Domain})
+}
+
+// Field - InvalidArgument с причиной и нарушением одного поля.
+func Field(reason, field, msg string) error {
+	return Fields(reason, msg, FieldViolation{Field: field, Description: msg})
+}
+
+// Fields - InvalidArgument с причиной и списком нарушений полей.
+func Fields(reason, msg string, violations ...FieldViolation) error {
+	br := &errdetails.BadRequest{FieldViolations: make([]*errdetails.BadRequest_FieldViolation, 0, len(violations))}
+	for _, v := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, msg), &errdetails.ErrorInfo{Reason: reason, Domain: Domain}, br)
+}
+
+// Internal - внутренняя ошибка без подробностей: причина уже записана в лог сервиса.
+func Internal() error {
+	return New(codes.Internal, ReasonInternal, "internal error")
+}
+
+// FromError разбирает ошибку gRPC. false - ошибка не gRPC статус.
+func FromError(err error) (Details, bool) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return Details{}, false
+	}
+
+	d := Details{Code: st.Code(), Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch v := detail.(type) {
+		case *errdetails.ErrorInfo:
+			d.Reason = v.GetReason()
+			d.Metadata = v.GetMetadata()
+		case *errdetails.BadRequest:
+			for _, fv := range v.GetFieldViolations() {
+				d.Fields = append(d.Fields, FieldViolation{Field: fv.GetField(), Description: fv.GetDescription()})
+			}
+		}
+	}
+
+	return d, true
+}
+
+// withDetails не теряет ошибку, если детали не сериализовались: клиент получит хотя бы код и сообщение.
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
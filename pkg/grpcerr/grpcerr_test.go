@@ -0,0 +1,55 @@
+package grpcerr
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestNew(t *testing.T) {
+	err := New(codes.AlreadyExists, ReasonEmailTaken, "user with this email already exists")
+
+	d, ok := FromError(err)
+	if !ok {
+		t.Fatal("expected grpc status")
+	}
+	if d.Code != codes.AlreadyExists {
+		t.Errorf("code = %v, want %v", d.Code, codes.AlreadyExists)
+	}
+	if d.Reason != ReasonEmailTaken {
+		t.Errorf("reason = %q, want %q", d.Reason, ReasonEmailTaken)
+	}
+	if d.Message != "user with this email already exists" {
+		t.Errorf("message = %q", d.Message)
+	}
+}
+
+func TestFields(t *testing.T) {
+	err := Fields(ReasonValidationFailed, "invalid request",
+		FieldViolation{Field: "title", Description: "too long"},
+		FieldViolation{Field: "content", Description: "empty"},
+	)
+
+	d, ok := FromError(err)
+	if !ok {
+		t.Fatal("expected grpc status")
+	}
+	if d.Code != codes.InvalidArgument {
+		t.Errorf("code = %v, want %v", d.Code, codes.InvalidArgument)
+	}
+	if len(d.Fields) != 2 || d.Fields[0].Field != "title" || d.Fields[1].Description != "empty" {
+		t.Errorf("fields = %+v", d.Fields)
+	}
+}
+
+func TestFromError_NoDetails(t *testing.T) {
+	if _, ok := FromError(errors.New("plain")); ok {
+		t.Error("plain error must not parse as status")
+	}
+
+	d, ok := FromError(Internal())
+	if !ok || d.Code != codes.Internal || d.Reason != ReasonInternal {
+		t.Errorf("details = %+v", d)
+	}
+}
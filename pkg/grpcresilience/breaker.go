@@ -0,0 +1,172 @@
+package grpcresilience
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+type BreakerConfig struct {
+	// Name - имя upstream в метриках и ошибках.
+	Name string
+	// FailureThreshold - сколько отказов подряд открывают breaker.
+	FailureThreshold int
+	// OpenTimeout - сколько breaker открыт до пробного запроса.
+	OpenTimeout time.Duration
+}
+
+// Breaker - circuit breaker на один upstream. Открывается после FailureThreshold отказов подряд,
+// через OpenTimeout пропускает один пробный запрос: успех закрывает breaker, отказ открывает снова.
+type Breaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	b := &Breaker{cfg: cfg, now: time.Now}
+	metrics.RecordCircuitBreakerState(context.Background(), cfg.Name, int64(StateClosed))
+
+	return b
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// allow решает, пропустить ли вызов. В half-open одновременно идёт только один пробный запрос.
+func (b *Breaker) allow(ctx context.Context) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.setState(ctx, StateHalfOpen)
+		b.probing = true
+		return true
+	case StateHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+type outcome int
+
+const (
+	outcomeSuccess outcome = iota
+	outcomeFailure
+	// outcomeIgnored - вызов ничего не говорит о здоровье upstream, например клиент отменил запрос.
+	outcomeIgnored
+)
+
+func (b *Breaker) record(ctx context.Context, o outcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateHalfOpen {
+		b.probing = false
+	}
+
+	switch o {
+	case outcomeSuccess:
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.setState(ctx, StateClosed)
+		}
+	case outcomeFailure:
+		b.failures++
+		if b.state == StateHalfOpen || b.failures >= b.cfg.FailureThreshold {
+			b.openedAt = b.now()
+			b.setState(ctx, StateOpen)
+		}
+	}
+}
+
+func (b *Breaker) setState(ctx context.Context, state State) {
+	if b.state == state {
+		return
+	}
+
+	b.state = state
+	metrics.RecordCircuitBreakerState(ctx, b.cfg.Name, int64(state))
+	metrics.RecordCircuitBreakerTransition(ctx, b.cfg.Name, state.String())
+}
+
+// classify - ошибки клиента (InvalidArgument, NotFound и т.п.) означают, что upstream жив и ответил.
+func classify(err error) outcome {
+	switch status.Code(err) {
+	case codes.OK:
+		return outcomeSuccess
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return outcomeFailure
+	case codes.Canceled:
+		return outcomeIgnored
+	default:
+		return outcomeSuccess
+	}
+}
+
+// BreakerUnaryClientInterceptor сразу отвечает Unavailable, пока breaker открыт, не дожидаясь таймаута upstream.
+// Ставится перед RetryUnaryClientInterceptor: серия повторов считается одним отказом.
+func BreakerUnaryClientInterceptor(b *Breaker) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !b.allow(ctx) {
+			return status.Errorf(codes.Unavailable, "%s is unavailable", b.cfg.Name)
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(ctx, classify(err))
+
+		return err
+	}
+}
@@ -0,0 +1,102 @@
+package grpcresilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func invokerWith(err error, calls *int) grpc.UnaryInvoker {
+	return func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		*calls++
+		return err
+	}
+}
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Name: "article", FailureThreshold: 3, OpenTimeout: time.Minute})
+	interceptor := BreakerUnaryClientInterceptor(b)
+
+	calls := 0
+	failing := invokerWith(status.Error(codes.Unavailable, "down"), &calls)
+
+	for range 3 {
+		_ = interceptor(context.Background(), "/svc/Get", nil, nil, nil, failing)
+	}
+	if b.State() != StateOpen {
+		t.Fatalf("state = %v, want open", b.State())
+	}
+
+	err := interceptor(context.Background(), "/svc/Get", nil, nil, nil, failing)
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("code = %v, want Unavailable", status.Code(err))
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3: open breaker must not call upstream", calls)
+	}
+}
+
+func TestBreaker_ClientErrorsDoNotOpen(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Name: "article", FailureThreshold: 2, OpenTimeout: time.Minute})
+	interceptor := BreakerUnaryClientInterceptor(b)
+
+	calls := 0
+	for _, code := range []codes.Code{codes.NotFound, codes.InvalidArgument, codes.Canceled, codes.PermissionDenied} {
+		_ = interceptor(context.Background(), "/svc/Get", nil, nil, nil, invokerWith(status.Error(code, "x"), &calls))
+	}
+
+	if b.State() != StateClosed {
+		t.Errorf("state = %v, want closed", b.State())
+	}
+}
+
+func TestBreaker_SuccessResetsFailures(t *testing.T) {
+	b := NewBreaker(BreakerConfig{Name: "article", FailureThreshold: 2, OpenTimeout: time.Minute})
+	ctx := context.Background()
+
+	b.record(ctx, outcomeFailure)
+	b.record(ctx, outcomeSuccess)
+	b.record(ctx, outcomeFailure)
+
+	if b.State() != StateClosed {
+		t.Errorf("state = %v, want closed", b.State())
+	}
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	b := NewBreaker(BreakerConfig{Name: "article", FailureThreshold: 1, OpenTimeout: 10 * time.Second})
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	b.record(ctx, outcomeFailure)
+	if b.allow(ctx) {
+		t.Fatal("open breaker must reject")
+	}
+
+	now = now.Add(10 * time.Second)
+	if !b.allow(ctx) {
+		t.Fatal("expected probe after open timeout")
+	}
+	if b.allow(ctx) {
+		t.Fatal("only one probe is allowed in half-open")
+	}
+
+	b.record(ctx, outcomeFailure)
+	if b.State() != StateOpen {
+		t.Fatalf("failed probe: state = %v, want open", b.State())
+	}
+
+	now = now.Add(10 * time.Second)
+	if !b.allow(ctx) {
+		t.Fatal("expected probe after open timeout")
+	}
+	b.record(ctx, outcomeSuccess)
+	if b.State() != StateClosed {
+		t.Fatalf("successful probe: state = %v, want closed", b.State())
+	}
+}
@@ -0,0 +1,102 @@
+package grpcresilience
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+type RetryConfig struct {
+	// MaxAttempts - всего попыток вместе с первой.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// IsIdempotent - Get* и List* ничего не меняют, их можно безопасно повторить.
+func IsIdempotent(method string) bool {
+	name := method[strings.LastIndex(method, "/")+1:]
+
+	return strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List")
+}
+
+// RetryUnaryClientInterceptor повторяет идемпотентные вызовы при Unavailable и истёкшем дедлайне попытки.
+// Ставится перед TimeoutUnaryClientInterceptor, чтобы дедлайн действовал на каждую попытку, а не на все сразу.
+func RetryUnaryClientInterceptor(cfg RetryConfig, idempotent func(method string) bool) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !idempotent(method) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		var err error
+		for attempt := range max(cfg.MaxAttempts, 1) {
+			if attempt > 0 {
+				metrics.RecordGRPCClientRetry(ctx, method)
+
+				if waitErr := sleep(ctx, backoff(cfg, attempt)); waitErr != nil {
+					return err
+				}
+			}
+
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			if !isRetryable(ctx, err) {
+				return err
+			}
+		}
+
+		return err
+	}
+}
+
+// isRetryable - упавший или не ответивший вовремя upstream. Если истёк сам запрос, повторять бессмысленно.
+func isRetryable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff - экспоненциальная задержка с full jitter, чтобы инстансы gateway не повторяли запросы синхронно.
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	delay := cfg.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay) //nolint:gosec
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+package grpcresilience
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testRetryConfig = RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := map[string]bool{
+		"/article.v1.ArticleService/GetArticle":         true,
+		"/article.v1.ArticleService/ListArticles":       true,
+		"/article.v1.ArticleService/GetRelatedArticles": true,
+		"/article.v1.ArticleService/CreateArticle":      false,
+		"/auth.v1.AuthService/Login":                    false,
+	}
+
+	for method, want := range tests {
+		if got := IsIdempotent(method); got != want {
+			t.Errorf("IsIdempotent(%q) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestRetry_RetriesUnavailable(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(testRetryConfig, IsIdempotent)
+
+	calls := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+		if calls < 3 {
+			return status.Error(codes.Unavailable, "down")
+		}
+		return nil
+	}
+
+	if err := interceptor(context.Background(), "/svc/GetArticle", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetry_StopsAfterMaxAttempts(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(testRetryConfig, IsIdempotent)
+
+	calls := 0
+	err := interceptor(context.Background(), "/svc/ListArticles", nil, nil, nil,
+		invokerWith(status.Error(codes.DeadlineExceeded, "slow"), &calls))
+
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("code = %v, want DeadlineExceeded", status.Code(err))
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestRetry_NoRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+	}{
+		{"not idempotent", "/svc/CreateArticle", status.Error(codes.Unavailable, "down")},
+		{"client error", "/svc/GetArticle", status.Error(codes.NotFound, "not found")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := RetryUnaryClientInterceptor(testRetryConfig, IsIdempotent)
+
+			calls := 0
+			_ = interceptor(context.Background(), tt.method, nil, nil, nil, invokerWith(tt.err, &calls))
+
+			if calls != 1 {
+				t.Errorf("calls = %d, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestRetry_CanceledContext(t *testing.T) {
+	interceptor := RetryUnaryClientInterceptor(testRetryConfig, IsIdempotent)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		calls++
+		cancel()
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	_ = interceptor(ctx, "/svc/GetArticle", nil, nil, nil, invoker)
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	interceptor := TimeoutUnaryClientInterceptor(time.Second, map[string]time.Duration{
+		"/svc/Login": 5 * time.Second,
+	})
+
+	check := func(method string, want time.Duration) {
+		t.Helper()
+		invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				t.Fatalf("%s: no deadline", method)
+			}
+			if left := time.Until(deadline); left > want || left < want-100*time.Millisecond {
+				t.Errorf("%s: deadline in %v, want ~%v", method, left, want)
+			}
+			return nil
+		}
+		_ = interceptor(context.Background(), method, nil, nil, nil, invoker)
+	}
+
+	check("/svc/Login", 5*time.Second)
+	check("/svc/GetArticle", time.Second)
+}
@@ -0,0 +1,34 @@
+package grpcresilience
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// TimeoutUnaryClientInterceptor ставит дедлайн на вызов: из perMethod по полному имени метода, иначе def.
+// Если у контекста запроса дедлайн раньше, остаётся он.
+func TimeoutUnaryClientInterceptor(def time.Duration, perMethod map[string]time.Duration) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		timeout, ok := perMethod[method]
+		if !ok {
+			timeout = def
+		}
+
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
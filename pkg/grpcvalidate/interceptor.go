@@ -2,12 +2,15 @@ package grpcvalidate
 
 import (
 	"context"
+	"errors"
 
 	"buf.build/go/protovalidate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
 )
 
 func UnaryInterceptor() grpc.UnaryServerInterceptor {
@@ -24,10 +27,28 @@ func UnaryInterceptor() grpc.UnaryServerInterceptor {
 	) (any, error) {
 		if msg, ok := req.(proto.Message); ok {
 			if err := validator.Validate(msg); err != nil {
-				return nil, status.Error(codes.InvalidArgument, err.Error())
+				return nil, validationError(err)
 			}
 		}
 
 		return handler(ctx, req)
 	}
 }
+
+// validationError - нарушения правил protovalidate уходят клиенту по полям в google.rpc.BadRequest.
+func validationError(err error) error {
+	var valErr *protovalidate.ValidationError
+	if !errors.As(err, &valErr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	violations := make([]grpcerr.FieldViolation, 0, len(valErr.Violations))
+	for _, v := range valErr.Violations {
+		violations = append(violations, grpcerr.FieldViolation{
+			Field:       protovalidate.FieldPathString(v.Proto.GetField()),
+			Description: v.Proto.GetMessage(),
+		})
+	}
+
+	return grpcerr.Fields(grpcerr.ReasonValidationFailed, err.Error(), violations...)
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func Postgres(pool *pgxpool.Pool) Checker {
+	return CheckerFunc(pool.Ping)
+}
+
+func Redis(client redis.UniversalClient) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+}
+
+// Kafka проверяет, что доступен хотя бы один брокер: метаданные кластера клиент получит через него.
+func Kafka(brokers []string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var dialer net.Dialer
+		var errs []error
+
+		for _, broker := range brokers {
+			conn, err := dialer.DialContext(ctx, "tcp", broker)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			_ = conn.Close()
+
+			return nil
+		}
+
+		return fmt.Errorf("no kafka broker is reachable: %w", errors.Join(errs...))
+	})
+}
+
+// GRPC спрашивает у upstream его grpc.health.v1 статус.
+func GRPC(conn *grpc.ClientConn) Checker {
+	client := healthpb.NewHealthClient(conn)
+
+	return CheckerFunc(func(ctx context.Context) error {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if err != nil {
+			return err
+		}
+
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("upstream status %s", resp.GetStatus())
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const watchInterval = 5 * time.Second
+
+// RegisterGRPC регистрирует стандартный grpc.health.v1. Пустое имя сервиса - readiness всего процесса.
+func (h *Health) RegisterGRPC(s *grpc.Server) {
+	healthpb.RegisterHealthServer(s, &grpcServer{h: h})
+}
+
+type grpcServer struct {
+	healthpb.UnimplementedHealthServer
+	h *Health
+}
+
+func (s *grpcServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if req.GetService() != "" {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	return &healthpb.HealthCheckResponse{Status: s.servingStatus(ctx)}, nil
+}
+
+// Watch шлёт статус сразу и затем при каждом изменении, опрашивая зависимости раз в watchInterval.
+func (s *grpcServer) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+
+	if req.GetService() != "" {
+		return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN})
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		if current := s.servingStatus(ctx); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-s.h.draining:
+			if last != healthpb.HealthCheckResponse_NOT_SERVING {
+				return stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING})
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *grpcServer) servingStatus(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if s.h.Ready(ctx).OK() {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
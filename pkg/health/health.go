@@ -0,0 +1,119 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error { return f(ctx) }
+
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Optional - отказ виден в отчёте, но readiness не роняет.
+	Optional bool `json:"optional,omitempty"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+func (r Report) OK() bool { return r.Status == StatusOK }
+
+type namedChecker struct {
+	name     string
+	checker  Checker
+	optional bool
+}
+
+// Health - liveness и readiness сервиса. Liveness - процесс жив, readiness - готов принимать трафик:
+// обязательные зависимости доступны и shutdown ещё не начался.
+type Health struct {
+	mu       sync.RWMutex
+	checkers []namedChecker
+	draining <-chan struct{}
+}
+
+// New принимает канал начала shutdown (closer.Draining): после его закрытия readiness сразу отвечает fail.
+func New(draining <-chan struct{}) *Health {
+	return &Health{draining: draining}
+}
+
+// Add добавляет обязательную зависимость: её отказ делает сервис неготовым.
+func (h *Health) Add(name string, c Checker) {
+	h.add(namedChecker{name: name, checker: c})
+}
+
+// AddOptional добавляет зависимость, без которой сервис работает в деградированном режиме.
+func (h *Health) AddOptional(name string, c Checker) {
+	h.add(namedChecker{name: name, checker: c, optional: true})
+}
+
+func (h *Health) add(c namedChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checkers = append(h.checkers, c)
+}
+
+func (h *Health) isDraining() bool {
+	select {
+	case <-h.draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ready опрашивает зависимости параллельно, каждую не дольше checkTimeout.
+func (h *Health) Ready(ctx context.Context) Report {
+	if h.isDraining() {
+		return Report{Status: StatusFail}
+	}
+
+	h.mu.RLock()
+	checkers := h.checkers
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Go(func() {
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			results[i] = CheckResult{Status: StatusOK, Optional: c.optional}
+			if err := c.checker.Check(checkCtx); err != nil {
+				results[i].Status = StatusFail
+				results[i].Error = err.Error()
+			}
+		})
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checkers))}
+	for i, c := range checkers {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == StatusFail && !c.optional {
+			report.Status = StatusFail
+		}
+	}
+
+	return report
+}
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var (
+	okChecker   = CheckerFunc(func(context.Context) error { return nil })
+	failChecker = CheckerFunc(func(context.Context) error { return errors.New("connection refused") })
+)
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(h *Health)
+		want  string
+	}{
+		{
+			name:  "no checkers",
+			setup: func(*Health) {},
+			want:  StatusOK,
+		},
+		{
+			name: "all ok",
+			setup: func(h *Health) {
+				h.Add("postgres", okChecker)
+				h.Add("redis", okChecker)
+			},
+			want: StatusOK,
+		},
+		{
+			name: "required fails",
+			setup: func(h *Health) {
+				h.Add("postgres", okChecker)
+				h.Add("redis", failChecker)
+			},
+			want: StatusFail,
+		},
+		{
+			name: "optional fails",
+			setup: func(h *Health) {
+				h.Add("postgres", okChecker)
+				h.AddOptional("article", failChecker)
+			},
+			want: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(nil)
+			tt.setup(h)
+
+			report := h.Ready(context.Background())
+			if report.Status != tt.want {
+				t.Errorf("status = %q, want %q (%+v)", report.Status, tt.want, report.Checks)
+			}
+		})
+	}
+}
+
+func TestReady_ReportsFailedCheck(t *testing.T) {
+	h := New(nil)
+	h.AddOptional("article", failChecker)
+
+	got := h.Ready(context.Background()).Checks["article"]
+	want := CheckResult{Status: StatusFail, Error: "connection refused", Optional: true}
+	if got != want {
+		t.Errorf("check = %+v, want %+v", got, want)
+	}
+}
+
+func TestReady_Draining(t *testing.T) {
+	draining := make(chan struct{})
+	h := New(draining)
+	h.Add("postgres", okChecker)
+
+	if !h.Ready(context.Background()).OK() {
+		t.Fatal("expected ready before shutdown")
+	}
+
+	close(draining)
+
+	if h.Ready(context.Background()).OK() {
+		t.Fatal("expected not ready after shutdown started")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := New(nil)
+	h.Add("postgres", failChecker)
+
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/healthz", http.StatusOK, StatusOK},
+		{"/readyz", http.StatusServiceUnavailable, StatusFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != tt.code {
+				t.Errorf("status code = %d, want %d", w.Code, tt.code)
+			}
+
+			var report Report
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if report.Status != tt.want {
+				t.Errorf("status = %q, want %q", report.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPCCheck(t *testing.T) {
+	draining := make(chan struct{})
+	h := New(draining)
+	s := &grpcServer{h: h}
+
+	resp, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status = %v, want SERVING", resp.GetStatus())
+	}
+
+	close(draining)
+
+	resp, _ = s.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status = %v, want NOT_SERVING", resp.GetStatus())
+	}
+
+	if _, err := s.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other"}); err == nil {
+		t.Error("expected error for unknown service")
+	}
+}
+
+func TestReadinessStatusHandler_HidesChecks(t *testing.T) {
+	h := New(nil)
+	h.Add("redis", failChecker)
+
+	w := httptest.NewRecorder()
+	h.ReadinessStatusHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status code = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	var report Report
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if report.Status != StatusFail {
+		t.Errorf("status = %q, want %q", report.Status, StatusFail)
+	}
+	if len(report.Checks) != 0 {
+		t.Errorf("checks = %v, want none on the public handler", report.Checks)
+	}
+}
@@ -0,0 +1,49 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// LivenessHandler - /healthz: процесс отвечает, зависимости не проверяются, чтобы их отказ не перезапускал под.
+func (h *Health) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, Report{Status: StatusOK})
+	}
+}
+
+// ReadinessHandler - /readyz: 503 с отчётом по зависимостям, если сервис не готов.
+func (h *Health) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, h.Ready(r.Context()))
+	}
+}
+
+// ReadinessStatusHandler - /readyz для публичного порта: тот же код ответа, но без отчёта по зависимостям,
+// чтобы тексты ошибок Redis и upstream не уходили наружу.
+func (h *Health) ReadinessStatusHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, Report{Status: h.Ready(r.Context()).Status})
+	}
+}
+
+// Handler - /healthz и /readyz для сервисов без своего HTTP сервера.
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("GET /healthz", h.LivenessHandler())
+	mux.Handle("GET /readyz", h.ReadinessHandler())
+
+	return mux
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	code := http.StatusOK
+	if !report.OK() {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(report) //nolint:gosec
+}
@@ -0,0 +1,15 @@
+package consumer
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// AssignmentsHandler - JSON с текущими назначенными партициями и лагом по ним, для admin эндпоинта сервиса.
+func (c *Consumer) AssignmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(c.Assignments()) //nolint:gosec
+	}
+}
@@ -0,0 +1,159 @@
+package consumer
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// PartitionState - назначенная партиция и отставание по ней.
+type PartitionState struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	// Offset - следующий оффсет к коммиту: после последнего помеченного сообщения или с которого начато чтение
+	Offset int64 `json:"offset"`
+	// HighWaterMark - оффсет, который получит следующее записанное в партицию сообщение
+	HighWaterMark int64 `json:"high_water_mark"`
+	// Lag - HighWaterMark - Offset, -1 если оффсет ещё неизвестен (чтение с OffsetOldest/OffsetNewest без коммитов)
+	Lag int64 `json:"lag"`
+}
+
+// Assignments - текущая сессия группы.
+type Assignments struct {
+	MemberID     string           `json:"member_id"`
+	GenerationID int32            `json:"generation_id"`
+	Partitions   []PartitionState `json:"partitions"`
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+type claimState struct {
+	claim sarama.ConsumerGroupClaim
+	// next - следующий оффсет к коммиту
+	next atomic.Int64
+}
+
+// assignments - партиции текущей сессии. Пишут ConsumeClaim (назначение) и пометки оффсетов,
+// читают сбор метрик и Consumer.Assignments.
+type assignments struct {
+	mu           sync.RWMutex
+	memberID     string
+	generationID int32
+	claims       map[topicPartition]*claimState
+	// revokedAt - с какого момента у consumer нет партиций: старт или конец прошлой сессии
+	revokedAt time.Time
+}
+
+func newAssignments() *assignments {
+	return &assignments{claims: make(map[topicPartition]*claimState), revokedAt: time.Now()}
+}
+
+// start - новая сессия после ребалансировки, возвращает сколько consumer был без партиций.
+func (a *assignments) start(session sarama.ConsumerGroupSession) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.memberID = session.MemberID()
+	a.generationID = session.GenerationID()
+	clear(a.claims)
+
+	return time.Since(a.revokedAt)
+}
+
+func (a *assignments) stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	clear(a.claims)
+	a.revokedAt = time.Now()
+}
+
+func (a *assignments) claim(claim sarama.ConsumerGroupClaim) {
+	state := &claimState{claim: claim}
+	state.next.Store(claim.InitialOffset())
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.claims[topicPartition{claim.Topic(), claim.Partition()}] = state
+}
+
+// mark - сообщения партиции до next (не включительно) обработаны.
+func (a *assignments) mark(topic string, partition int32, next int64) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if state, ok := a.claims[topicPartition{topic, partition}]; ok {
+		state.next.Store(next)
+	}
+}
+
+func (a *assignments) snapshot() Assignments {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	partitions := make([]PartitionState, 0, len(a.claims))
+	for tp, state := range a.claims {
+		hwm := state.claim.HighWaterMarkOffset()
+		next := state.next.Load()
+
+		lag := int64(-1)
+		switch {
+		case next >= 0:
+			lag = max(hwm-next, 0)
+		case hwm == 0:
+			// в партицию ещё ничего не писали
+			lag = 0
+		}
+
+		partitions = append(partitions, PartitionState{
+			Topic:         tp.topic,
+			Partition:     tp.partition,
+			Offset:        next,
+			HighWaterMark: hwm,
+			Lag:           lag,
+		})
+	}
+
+	slices.SortFunc(partitions, func(x, y PartitionState) int {
+		return cmp.Or(cmp.Compare(x.Topic, y.Topic), cmp.Compare(x.Partition, y.Partition))
+	})
+
+	return Assignments{MemberID: a.memberID, GenerationID: a.generationID, Partitions: partitions}
+}
+
+func (a *assignments) observe() []metrics.ConsumerPartition {
+	snapshot := a.snapshot()
+
+	partitions := make([]metrics.ConsumerPartition, len(snapshot.Partitions))
+	for i, p := range snapshot.Partitions {
+		partitions[i] = metrics.ConsumerPartition{Topic: p.Topic, Partition: p.Partition, Lag: p.Lag}
+	}
+
+	return partitions
+}
+
+// trackingSession - сессия, которая запоминает помеченные оффсеты для расчёта лага.
+type trackingSession struct {
+	sarama.ConsumerGroupSession
+	assigned *assignments
+}
+
+func (s trackingSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.ConsumerGroupSession.MarkMessage(msg, metadata)
+	s.assigned.mark(msg.Topic, msg.Partition, msg.Offset+1)
+}
+
+func (s trackingSession) MarkOffset(topic string, partition int32, offset int64, metadata string) {
+	s.ConsumerGroupSession.MarkOffset(topic, partition, offset, metadata)
+	s.assigned.mark(topic, partition, offset)
+}
@@ -0,0 +1,79 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+func (s *fakeSession) MemberID() string           { return "member-1" }
+func (s *fakeSession) GenerationID() int32        { return 7 }
+func (s *fakeSession) Claims() map[string][]int32 { return map[string][]int32{"t": {0}} }
+
+func TestAssignments_LagFromMarkedOffsets(t *testing.T) {
+	// третье сообщение падает с retryable ошибкой и не помечается
+	handler := func(_ context.Context, msg kafka.Message) error {
+		if msg.Offset == 2 {
+			return errors.New("temporary")
+		}
+		return nil
+	}
+
+	g := newGroupHandler(handler, func(error) {}, 1)
+	session := &fakeSession{ctx: context.Background()}
+	claim := newFakeClaim("a", "b", "c")
+	claim.hwm = 5
+
+	if err := g.Setup(session); err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	if err := g.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	got := g.assigned.snapshot()
+	if got.MemberID != "member-1" || got.GenerationID != 7 {
+		t.Errorf("session = %s/%d, want member-1/7", got.MemberID, got.GenerationID)
+	}
+	if len(got.Partitions) != 1 {
+		t.Fatalf("partitions = %d, want 1", len(got.Partitions))
+	}
+
+	p := got.Partitions[0]
+	if p.Topic != "t" || p.Partition != 0 {
+		t.Errorf("partition = %s/%d, want t/0", p.Topic, p.Partition)
+	}
+	if p.Offset != 2 || p.HighWaterMark != 5 || p.Lag != 3 {
+		t.Errorf("offset/hwm/lag = %d/%d/%d, want 2/5/3", p.Offset, p.HighWaterMark, p.Lag)
+	}
+
+	if err := g.Cleanup(session); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if n := len(g.assigned.snapshot().Partitions); n != 0 {
+		t.Errorf("partitions after Cleanup = %d, want 0", n)
+	}
+}
+
+func TestAssignments_UnknownOffset(t *testing.T) {
+	a := newAssignments()
+
+	unknown := newFakeClaim()
+	unknown.hwm = 10
+	a.claims[topicPartition{"t", 0}] = &claimState{claim: unknown}
+	a.claims[topicPartition{"t", 0}].next.Store(-2)
+
+	empty := newFakeClaim()
+	a.claims[topicPartition{"t", 1}] = &claimState{claim: empty}
+	a.claims[topicPartition{"t", 1}].next.Store(-2)
+
+	got := a.snapshot().Partitions
+	if got[0].Lag != -1 {
+		t.Errorf("lag without committed offset = %d, want -1", got[0].Lag)
+	}
+	if got[1].Lag != 0 {
+		t.Errorf("lag of empty partition = %d, want 0", got[1].Lag)
+	}
+}
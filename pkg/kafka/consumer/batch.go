@@ -0,0 +1,255 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+type BatchOptions struct {
+	// MaxSize - батч отдаётся обработчику, как только набралось столько сообщений
+	MaxSize int
+	// MaxWait - или когда с первого сообщения батча прошло столько времени
+	MaxWait time.Duration
+}
+
+// DefaultBatchOptions - до 100 сообщений, не дольше 500 мс ожидания.
+var DefaultBatchOptions = BatchOptions{MaxSize: 100, MaxWait: 500 * time.Millisecond}
+
+// ConsumeBatch - как Consume, но обработчик получает пачки сообщений одной партиции.
+// Оффсет помечается только после того, как весь батч обработан. Сообщения, которые обработчик отметил в *BatchError
+// (или весь батч при другой ошибке), повторяются по одному через middleware консьюмера - retry, retry топики, DLQ
+// работают как обычно, а успешные сообщения батча повторно не обрабатываются. Сообщения из retry топиков
+// (с заголовком x-retry-at) в батч не попадают и обрабатываются по одному через middleware, иначе
+// WithRetryTopics не выдержал бы задержку ступени. Concurrent в этом режиме не действует.
+func (c *Consumer) ConsumeBatch(ctx context.Context, handler kafka.BatchHandler, opts BatchOptions) error {
+	if opts.MaxSize <= 0 {
+		opts.MaxSize = DefaultBatchOptions.MaxSize
+	}
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = DefaultBatchOptions.MaxWait
+	}
+
+	return c.run(ctx, func(stop context.CancelCauseFunc) *groupHandler {
+		gh := newGroupHandler(single(handler), stop, 1, c.middlewares...)
+		gh.batch = handler
+		gh.batchOpts = opts
+
+		return gh
+	})
+}
+
+// single - обработчик одного сообщения поверх батчевого, для повторов через middleware.
+func single(handler kafka.BatchHandler) kafka.MessageHandler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		err := handler(ctx, []kafka.Message{msg})
+
+		var batchErr *kafka.BatchError
+		if !errors.As(err, &batchErr) {
+			return err
+		}
+
+		// в батче одно сообщение - индекс 0, его нет в Failed - сообщение обработано
+		failErr, failed := batchErr.Failed[0]
+		switch {
+		case !failed:
+			return nil
+		case failErr == nil:
+			return err
+		default:
+			return failErr
+		}
+	}
+}
+
+func (g *groupHandler) consumeBatches(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	batch := make([]*sarama.ConsumerMessage, 0, g.batchOpts.MaxSize)
+
+	timer := time.NewTimer(g.batchOpts.MaxWait)
+	timer.Stop()
+	defer timer.Stop()
+
+	flush := func() error {
+		timer.Stop()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := g.handleBatch(session, batch)
+		batch = batch[:0]
+
+		return err
+	}
+
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return flush()
+			}
+
+			if delayed(message) {
+				if err := flush(); err != nil {
+					return err
+				}
+				if err := g.processDelayed(session, message); err != nil {
+					return err
+				}
+				continue
+			}
+
+			batch = append(batch, message)
+			if len(batch) == 1 {
+				timer.Reset(g.batchOpts.MaxWait)
+			}
+			if len(batch) >= g.batchOpts.MaxSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+
+		case <-timer.C:
+			if err := flush(); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// delayed - сообщение из retry топика, которое нельзя обработать раньше x-retry-at.
+func delayed(message *sarama.ConsumerMessage) bool {
+	for _, h := range message.Headers {
+		if h != nil && string(h.Key) == kafka.HeaderRetryAt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// processDelayed - одно сообщение через всю цепочку middleware, как в обычном режиме.
+func (g *groupHandler) processDelayed(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) error {
+	done, err := g.process(session.Context(), message)
+	if err != nil {
+		return err
+	}
+	if done {
+		session.MarkMessage(message, "")
+	}
+
+	return nil
+}
+
+// handleBatch - обрабатывает батч, повторяет по одному упавшие сообщения и помечает последний оффсет.
+// Прерванный отменой сессии батч не помечается. Fatal ошибка останавливает consumer и возвращается.
+func (g *groupHandler) handleBatch(session sarama.ConsumerGroupSession, batch []*sarama.ConsumerMessage) error {
+	ctx := session.Context()
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	msgs := make([]kafka.Message, len(batch))
+	for i, m := range batch {
+		msgs[i] = toMessage(m)
+	}
+	last := batch[len(batch)-1]
+
+	err := g.callBatch(ctx, msgs)
+	metrics.RecordConsumerBatch(ctx, last.Topic, len(batch), failedCount(err, len(batch)))
+	if err == nil {
+		session.MarkMessage(last, "")
+		return nil
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	if kafka.Classify(err) == kafka.ErrorFatal {
+		_, ferr := g.handle(ctx, last, err)
+		return ferr
+	}
+
+	failed := failedIndexes(err, len(batch))
+
+	log := logger.Ctx(ctx)
+	log.Warn().
+		Err(err).
+		Str("topic", last.Topic).
+		Int32("partition", last.Partition).
+		Int64("first_offset", batch[0].Offset).
+		Int64("last_offset", last.Offset).
+		Int("failed", len(failed)).
+		Msg("kafka: batch partially failed, retrying messages one by one")
+
+	for _, i := range failed {
+		_, ferr := g.handle(ctx, batch[i], g.handler(ctx, msgs[i]))
+		if ferr != nil {
+			return ferr
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	session.MarkMessage(last, "")
+
+	return nil
+}
+
+// callBatch - паника в батчевом обработчике превращается в ошибку всего батча, дальше её ловит Recovery при повторах.
+func (g *groupHandler) callBatch(ctx context.Context, msgs []kafka.Message) (err error) {
+	start := time.Now()
+	defer func() {
+		metrics.RecordConsumerHandler(ctx, msgs[0].Topic, outcome(err), time.Since(start))
+	}()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("kafka: batch handler panic: %v", r)
+		}
+	}()
+
+	return g.batch(ctx, msgs)
+}
+
+// failedIndexes - индексы из *BatchError по возрастанию, при любой другой ошибке - весь батч.
+func failedIndexes(err error, n int) []int {
+	var batchErr *kafka.BatchError
+	if errors.As(err, &batchErr) {
+		idx := make([]int, 0, len(batchErr.Failed))
+		for i := range batchErr.Failed {
+			if i >= 0 && i < n {
+				idx = append(idx, i)
+			}
+		}
+		slices.Sort(idx)
+
+		return idx
+	}
+
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	return idx
+}
+
+func failedCount(err error, n int) int {
+	if err == nil {
+		return 0
+	}
+
+	return len(failedIndexes(err, n))
+}
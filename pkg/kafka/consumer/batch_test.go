@@ -0,0 +1,237 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+func newBatchHandler(handler kafka.BatchHandler, opts BatchOptions, middlewares ...Middleware) *groupHandler {
+	g := newGroupHandler(single(handler), func(error) {}, 1, middlewares...)
+	g.batch = handler
+	g.batchOpts = opts
+
+	return g
+}
+
+func TestBatch_FlushBySize(t *testing.T) {
+	var sizes []int
+	handler := func(_ context.Context, msgs []kafka.Message) error {
+		sizes = append(sizes, len(msgs))
+		return nil
+	}
+
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 3, MaxWait: time.Hour})
+	session := &fakeSession{ctx: context.Background()}
+
+	if err := g.ConsumeClaim(session, newFakeClaim("a", "b", "c", "d", "e", "f", "g")); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	if len(sizes) != 3 || sizes[0] != 3 || sizes[1] != 3 || sizes[2] != 1 {
+		t.Errorf("batch sizes = %v, want [3 3 1]", sizes)
+	}
+	if got := session.markedOffset(); got != 7 {
+		t.Errorf("marked = %d, want 7", got)
+	}
+}
+
+func TestBatch_FlushByWait(t *testing.T) {
+	flushed := make(chan int, 1)
+	handler := func(_ context.Context, msgs []kafka.Message) error {
+		flushed <- len(msgs)
+		return nil
+	}
+
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 100, MaxWait: 20 * time.Millisecond})
+	session := &fakeSession{ctx: context.Background()}
+
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "t", Offset: 0}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "t", Offset: 1}
+
+	go func() { _ = g.ConsumeClaim(session, claim) }()
+	defer close(claim.messages)
+
+	select {
+	case n := <-flushed:
+		if n != 2 {
+			t.Errorf("batch size = %d, want 2", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch not flushed by MaxWait")
+	}
+}
+
+func TestBatch_PartialFailureRetriesOnlyFailed(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+
+	handler := func(_ context.Context, msgs []kafka.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var batchErr kafka.BatchError
+		for i, m := range msgs {
+			calls[string(m.Key)]++
+			// b падает в батче, но проходит при повторе по одному
+			if string(m.Key) == "b" && len(msgs) > 1 {
+				batchErr.Fail(i, errHandler)
+			}
+		}
+
+		return batchErr.ErrOrNil()
+	}
+
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 3, MaxWait: time.Hour}, WithRetry(2))
+	session := &fakeSession{ctx: context.Background()}
+
+	if err := g.ConsumeClaim(session, newFakeClaim("a", "b", "c")); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	if calls["a"] != 1 || calls["c"] != 1 {
+		t.Errorf("successful messages reprocessed: %v", calls)
+	}
+	if calls["b"] != 2 {
+		t.Errorf("b calls = %d, want 2", calls["b"])
+	}
+	if got := session.markedOffset(); got != 3 {
+		t.Errorf("marked = %d, want 3", got)
+	}
+}
+
+func TestBatch_FailedGoesToDLQ(t *testing.T) {
+	p := &recordingProducer{}
+	handler := func(_ context.Context, msgs []kafka.Message) error {
+		var batchErr kafka.BatchError
+		for i, m := range msgs {
+			if string(m.Key) == "bad" {
+				batchErr.Fail(i, kafka.Permanent(errHandler))
+			}
+		}
+
+		return batchErr.ErrOrNil()
+	}
+
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 10, MaxWait: time.Hour}, WithDLQ(p), WithRetry(3))
+	session := &fakeSession{ctx: context.Background()}
+
+	if err := g.ConsumeClaim(session, newFakeClaim("ok", "bad", "ok")); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	if len(p.sent) != 1 || string(p.sent[0].Key) != "bad" {
+		t.Errorf("dlq = %+v, want only bad", p.sent)
+	}
+	if got := session.markedOffset(); got != 3 {
+		t.Errorf("marked = %d, want 3", got)
+	}
+}
+
+func TestBatch_FatalStops(t *testing.T) {
+	var stopped error
+	handler := func(context.Context, []kafka.Message) error { return kafka.Fatal(errHandler) }
+
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 2, MaxWait: time.Hour})
+	g.stop = func(err error) { stopped = err }
+	session := &fakeSession{ctx: context.Background()}
+
+	if err := g.ConsumeClaim(session, newFakeClaim("a", "b")); !errors.Is(err, errHandler) {
+		t.Errorf("err = %v, want fatal handler error", err)
+	}
+	if stopped == nil {
+		t.Error("consumer not stopped")
+	}
+	if got := session.markedOffset(); got != 0 {
+		t.Errorf("marked = %d, want 0", got)
+	}
+}
+
+func TestBatch_RetryTopicMessagesWaitRetryAt(t *testing.T) {
+	var batches int
+	handler := func(_ context.Context, msgs []kafka.Message) error {
+		batches++
+		return nil
+	}
+
+	var handled []time.Time
+	record := func(next kafka.MessageHandler) kafka.MessageHandler {
+		return func(ctx context.Context, msg kafka.Message) error {
+			handled = append(handled, time.Now())
+			return next(ctx, msg)
+		}
+	}
+
+	p := &recordingProducer{}
+	g := newBatchHandler(handler, BatchOptions{MaxSize: 10, MaxWait: time.Hour}, WithRetryTopics(p), record)
+	session := &fakeSession{ctx: context.Background()}
+
+	retryAt := time.Now().Add(50 * time.Millisecond)
+	claim := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, 2)}
+	for i := range 2 {
+		claim.messages <- &sarama.ConsumerMessage{
+			Topic:  "t.retry.1m",
+			Offset: int64(i),
+			Headers: []*sarama.RecordHeader{{
+				Key:   []byte(kafka.HeaderRetryAt),
+				Value: []byte(retryAt.UTC().Format(time.RFC3339Nano)),
+			}},
+		}
+	}
+	close(claim.messages)
+
+	if err := g.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	if len(handled) != 2 || batches != 2 {
+		t.Fatalf("handled = %d, batches = %d, want each message alone through middleware", len(handled), batches)
+	}
+	for _, at := range handled {
+		if at.Before(retryAt) {
+			t.Errorf("handled at %s, before x-retry-at %s", at, retryAt)
+		}
+	}
+	if got := session.markedOffset(); got != 2 {
+		t.Errorf("marked = %d, want 2", got)
+	}
+}
+
+func TestSingle_BatchErrorForOtherIndex(t *testing.T) {
+	errMsg := errors.New("bad message")
+
+	tests := map[string]struct {
+		failed map[int]error
+		want   error
+	}{
+		"message failed":       {failed: map[int]error{0: errMsg}, want: errMsg},
+		"only other index":     {failed: map[int]error{3: errMsg}},
+		"failed without cause": {failed: map[int]error{0: nil}, want: &kafka.BatchError{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			h := single(func(context.Context, []kafka.Message) error {
+				return &kafka.BatchError{Failed: tt.failed}
+			})
+
+			err := h(context.Background(), kafka.Message{})
+			var batchErr *kafka.BatchError
+			switch {
+			case tt.want == nil && err != nil:
+				t.Errorf("err = %v, want nil", err)
+			case errors.As(tt.want, &batchErr) && !errors.As(err, &batchErr):
+				t.Errorf("err = %v, want *BatchError", err)
+			case tt.want == errMsg && !errors.Is(err, errMsg):
+				t.Errorf("err = %v, want %v", err, errMsg)
+			}
+		})
+	}
+}
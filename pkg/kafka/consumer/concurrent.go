@@ -0,0 +1,133 @@
+package consumer
+
+import (
+	"hash/fnv"
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// workerQueue - сколько сообщений может ждать свободного воркера, пока ConsumeClaim не упрётся в backpressure.
+const workerQueue = 16
+
+// consumeConcurrently раскладывает сообщения партиции по воркерам по хэшу ключа: сообщения с одним ключом
+// обрабатываются одним воркером по порядку, с разными - параллельно. Оффсет помечается только до первого
+// незаконченного сообщения, поэтому после ребалансировки ничего не теряется, а уже обработанное может повториться.
+// Сообщения с retryable ошибкой считаются законченными, как и в последовательном режиме: их пропускают следующие коммиты.
+func (g *groupHandler) consumeConcurrently(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	tracker := newOffsetTracker()
+
+	queues := make([]chan *sarama.ConsumerMessage, g.workers)
+	for i := range queues {
+		queues[i] = make(chan *sarama.ConsumerMessage, workerQueue)
+
+		g.inflight.Add(1)
+		go g.work(session, tracker, queues[i])
+	}
+	defer func() {
+		for _, q := range queues {
+			close(q)
+		}
+	}()
+
+	for i := 0; ; i++ {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			tracker.dispatch(message.Offset)
+			metrics.RecordConsumerInFlight(ctx, message.Topic, 1)
+
+			select {
+			case queues[workerFor(message.Key, i, g.workers)] <- message:
+			case <-ctx.Done():
+				metrics.RecordConsumerInFlight(ctx, message.Topic, -1)
+				return nil
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func (g *groupHandler) work(session sarama.ConsumerGroupSession, tracker *offsetTracker, queue <-chan *sarama.ConsumerMessage) {
+	defer g.inflight.Done()
+
+	ctx := session.Context()
+	for message := range queue {
+		// после отмены сессии сообщения из очереди не начинаем: их перечитает следующий владелец партиции
+		var done bool
+		if ctx.Err() == nil {
+			done, _ = g.process(ctx, message)
+		}
+		metrics.RecordConsumerInFlight(ctx, message.Topic, -1)
+
+		// прерванное отменой сессии сообщение не закончено
+		if !done && ctx.Err() != nil {
+			continue
+		}
+		if next, ok := tracker.complete(message.Offset); ok {
+			session.MarkOffset(message.Topic, message.Partition, next, "")
+		}
+	}
+}
+
+// workerFor - воркер по хэшу ключа. Сообщения без ключа порядка не требуют и раскладываются по кругу.
+func workerFor(key []byte, seq, workers int) int {
+	if key == nil {
+		return seq % workers
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+
+	return int(h.Sum32() % uint32(workers)) //nolint:gosec // workers > 1 и небольшое
+}
+
+// offsetTracker - оффсеты партиции в порядке получения и какие из них уже обработаны.
+type offsetTracker struct {
+	mu      sync.Mutex
+	pending []int64
+	done    map[int64]struct{}
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{done: make(map[int64]struct{})}
+}
+
+func (t *offsetTracker) dispatch(offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pending = append(t.pending, offset)
+}
+
+// complete отмечает оффсет обработанным и возвращает следующий оффсет для коммита,
+// если непрерывный обработанный префикс сдвинулся.
+func (t *offsetTracker) complete(offset int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.done[offset] = struct{}{}
+
+	var last int64
+	advanced := false
+	for len(t.pending) > 0 {
+		head := t.pending[0]
+		if _, ok := t.done[head]; !ok {
+			break
+		}
+
+		delete(t.done, head)
+		t.pending = t.pending[1:]
+		last, advanced = head, true
+	}
+
+	return last + 1, advanced
+}
@@ -0,0 +1,153 @@
+package consumer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+type fakeSession struct {
+	sarama.ConsumerGroupSession
+
+	ctx context.Context //nolint:containedctx // контекст сессии, как у sarama
+
+	mu     sync.Mutex
+	marked int64
+}
+
+func (s *fakeSession) Context() context.Context { return s.ctx }
+
+func (s *fakeSession) MarkOffset(_ string, _ int32, offset int64, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.marked = max(s.marked, offset)
+}
+
+func (s *fakeSession) MarkMessage(msg *sarama.ConsumerMessage, metadata string) {
+	s.MarkOffset(msg.Topic, msg.Partition, msg.Offset+1, metadata)
+}
+
+func (s *fakeSession) markedOffset() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.marked
+}
+
+type fakeClaim struct {
+	sarama.ConsumerGroupClaim
+
+	messages chan *sarama.ConsumerMessage
+	hwm      int64
+}
+
+func (c *fakeClaim) Messages() <-chan *sarama.ConsumerMessage { return c.messages }
+func (c *fakeClaim) Topic() string                            { return "t" }
+func (c *fakeClaim) Partition() int32                         { return 0 }
+func (c *fakeClaim) InitialOffset() int64                     { return 0 }
+func (c *fakeClaim) HighWaterMarkOffset() int64               { return c.hwm }
+
+func newFakeClaim(keys ...string) *fakeClaim {
+	c := &fakeClaim{messages: make(chan *sarama.ConsumerMessage, len(keys)), hwm: int64(len(keys))}
+	for i, k := range keys {
+		c.messages <- &sarama.ConsumerMessage{Topic: "t", Key: []byte(k), Offset: int64(i)}
+	}
+	close(c.messages)
+
+	return c
+}
+
+func TestConcurrent_PerKeyOrderAndContiguousCommit(t *testing.T) {
+	release := make(chan struct{})
+
+	var mu sync.Mutex
+	seen := map[string][]int64{}
+
+	handler := func(_ context.Context, msg kafka.Message) error {
+		// первое сообщение медленное: коммит не должен уйти дальше него
+		if msg.Offset == 0 {
+			<-release
+		}
+
+		mu.Lock()
+		seen[string(msg.Key)] = append(seen[string(msg.Key)], msg.Offset)
+		mu.Unlock()
+
+		return nil
+	}
+
+	g := newGroupHandler(handler, func(error) {}, 4)
+	session := &fakeSession{ctx: context.Background()}
+	claim := newFakeClaim("a", "b", "c", "b", "c", "d", "b")
+
+	if err := g.ConsumeClaim(session, claim); err != nil {
+		t.Fatalf("ConsumeClaim: %v", err)
+	}
+
+	// все кроме первого успевают обработаться, но помечать нечего
+	time.Sleep(50 * time.Millisecond)
+	if got := session.markedOffset(); got != 0 {
+		t.Errorf("marked = %d before slow message finished, want 0", got)
+	}
+
+	close(release)
+	if err := g.Cleanup(session); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	if got := session.markedOffset(); got != 7 {
+		t.Errorf("marked = %d after drain, want 7", got)
+	}
+	if b := seen["b"]; len(b) != 3 || b[0] != 1 || b[1] != 3 || b[2] != 6 {
+		t.Errorf("key b order = %v, want [1 3 6]", b)
+	}
+}
+
+func TestConcurrent_CanceledSessionNotMarked(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	handler := func(ctx context.Context, msg kafka.Message) error {
+		if msg.Offset == 1 {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		return nil
+	}
+
+	g := newGroupHandler(handler, func(error) {}, 2)
+	session := &fakeSession{ctx: ctx}
+
+	_ = g.ConsumeClaim(session, newFakeClaim("a", "a", "a"))
+	if err := g.Cleanup(session); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	if got := session.markedOffset(); got != 1 {
+		t.Errorf("marked = %d, want 1", got)
+	}
+}
+
+func TestOffsetTracker(t *testing.T) {
+	tr := newOffsetTracker()
+	for _, o := range []int64{10, 11, 13} {
+		tr.dispatch(o)
+	}
+
+	if _, ok := tr.complete(11); ok {
+		t.Error("advanced past unfinished 10")
+	}
+	if next, ok := tr.complete(10); !ok || next != 12 {
+		t.Errorf("next = %d/%v, want 12", next, ok)
+	}
+	if next, ok := tr.complete(13); !ok || next != 14 {
+		t.Errorf("next = %d/%v, want 14", next, ok)
+	}
+}
@@ -8,33 +8,71 @@ import (
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
 )
 
 type Consumer struct {
 	group       sarama.ConsumerGroup
 	topics      []string
 	middlewares []Middleware
+	workers     int
+	assigned    *assignments
 }
 
 // New - создаем новую консьюмер группу
-// порядок мидллвар: Recovery → Logging → WithRetry → handler.
+// порядок мидллвар: Logging → WithDLQ → WithRetryTopics → Recovery → WithRetry → handler.
 func New(group sarama.ConsumerGroup, topics []string, middlewares ...Middleware) *Consumer {
 	return &Consumer{
 		group:       group,
 		topics:      topics,
 		middlewares: middlewares,
+		assigned:    newAssignments(),
 	}
 }
 
-// Consume - отвечает за старт consumer loop. Поддерживает ребалансировку
+// Assignments - партиции, назначенные consumer в текущей сессии, и лаг по ним.
+func (c *Consumer) Assignments() Assignments {
+	return c.assigned.snapshot()
+}
+
+// Concurrent - обрабатывать каждую партицию workers горутинами, раскладывая сообщения по хэшу ключа.
+// Порядок сохраняется только для сообщений с одним ключом. Вызывается до Consume.
+func (c *Consumer) Concurrent(workers int) *Consumer {
+	c.workers = workers
+	return c
+}
+
+// Consume - отвечает за старт consumer loop. Поддерживает ребалансировку.
+// Возвращает fatal ошибку обработчика (kafka.Fatal), если она случилась.
 func (c *Consumer) Consume(ctx context.Context, handler kafka.MessageHandler) error {
-	gh := newGroupHandler(handler, c.middlewares...)
+	return c.run(ctx, func(stop context.CancelCauseFunc) *groupHandler {
+		return newGroupHandler(handler, stop, c.workers, c.middlewares...)
+	})
+}
+
+func (c *Consumer) run(ctx context.Context, newHandler func(stop context.CancelCauseFunc) *groupHandler) error {
+	ctx, stop := context.WithCancelCause(ctx)
+	defer stop(nil)
+
+	gh := newHandler(stop)
+	gh.assigned = c.assigned
+
+	unregister, err := metrics.ObserveConsumerPartitions(c.assigned.observe)
+	if err != nil {
+		log := logger.Ctx(ctx)
+		log.Warn().Err(err).Msg("kafka: consumer lag metrics disabled")
+	} else {
+		defer func() { _ = unregister() }()
+	}
 
 	for {
 		if err := c.group.Consume(ctx, c.topics, gh); err != nil {
 			if errors.Is(err, sarama.ErrClosedConsumerGroup) {
 				return nil
 			}
+			if ctx.Err() != nil {
+				return context.Cause(ctx)
+			}
 
 			log := logger.Ctx(ctx)
 			log.Error().Err(err).Msg("kafka: consume error")
@@ -43,7 +81,7 @@ func (c *Consumer) Consume(ctx context.Context, handler kafka.MessageHandler) er
 		}
 
 		if ctx.Err() != nil {
-			return ctx.Err()
+			return context.Cause(ctx)
 		}
 
 		log := logger.Ctx(ctx)
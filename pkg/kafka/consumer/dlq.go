@@ -0,0 +1,62 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// WithDLQ - сообщение, которое не удалось обработать, публикуется в <исходный топик>.dlq с исходными заголовками,
+// текстом ошибки, числом попыток и координатами первой публикации, после чего помечается прочитанным.
+// Если публикация не удалась, возвращается fatal ошибка: consumer останавливается, не сдвинув оффсет за сообщение.
+// Fatal ошибки не перехватываются - consumer должен остановиться.
+// Ошибки из-за отмены контекста (ребалансировка, остановка) в DLQ не попадают - сообщение перечитает следующий владелец партиции.
+func WithDLQ(p kafka.Producer) Middleware {
+	return func(next kafka.MessageHandler) kafka.MessageHandler {
+		return func(ctx context.Context, msg kafka.Message) error {
+			err := next(ctx, msg)
+			if err == nil || ctx.Err() != nil || kafka.Classify(err) == kafka.ErrorFatal {
+				return err
+			}
+
+			topic := kafka.DLQTopic(msg.OriginalTopic())
+			dead := msg.Failed(err, msg.Attempts()+1, time.Now())
+			dead.Topic = topic
+
+			if sendErr := p.Send(ctx, dead); sendErr != nil {
+				return publishError(ctx, topic, sendErr, err)
+			}
+
+			metrics.RecordConsumerDLQ(ctx, msg.OriginalTopic())
+
+			log := logger.Ctx(ctx)
+			log.Error().
+				Err(err).
+				Str("topic", msg.Topic).
+				Int32("partition", msg.Partition).
+				Int64("offset", msg.Offset).
+				Str("dlq", topic).
+				Msg("kafka: message moved to dlq")
+
+			return nil
+		}
+	}
+}
+
+// publishError - неопубликованное сообщение нельзя оставить просто непомеченным: следующий MarkMessage
+// или MarkOffset закоммитит оффсет после него, и оно потеряется. Поэтому ошибка fatal - consumer
+// останавливается, после рестарта сообщение перечитается с последнего коммита. Если публикацию прервала
+// отмена контекста (ребалансировка, остановка), сессия и так закончилась, и ошибка остаётся обычной.
+// Ошибка обработчика не оборачивается, чтобы её класс не повлиял на решение.
+func publishError(ctx context.Context, topic string, sendErr, handlerErr error) error {
+	err := fmt.Errorf("kafka: publish to %s: %w (handler error: %s)", topic, sendErr, handlerErr)
+	if ctx.Err() != nil {
+		return err
+	}
+
+	return kafka.Fatal(err)
+}
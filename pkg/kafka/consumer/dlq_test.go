@@ -0,0 +1,219 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+var errHandler = errors.New("handler failed")
+
+type recordingProducer struct {
+	mu   sync.Mutex
+	sent []kafka.Message
+	err  error
+}
+
+func (p *recordingProducer) Send(_ context.Context, msg kafka.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.sent = append(p.sent, msg)
+
+	return nil
+}
+
+func (p *recordingProducer) Close() error { return nil }
+
+func failing(context.Context, kafka.Message) error { return errHandler }
+
+func testMessage() kafka.Message {
+	return kafka.Message{
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		Headers:   map[string][]byte{"traceparent": []byte("tp")},
+		Topic:     "user-registered",
+		Partition: 1,
+		Offset:    42,
+	}
+}
+
+func TestWithDLQ_PublishesFailed(t *testing.T) {
+	p := &recordingProducer{}
+	h := WithDLQ(p)(failing)
+
+	if err := h(context.Background(), testMessage()); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if len(p.sent) != 1 {
+		t.Fatalf("sent = %d, want 1", len(p.sent))
+	}
+
+	dead := p.sent[0]
+	if dead.Topic != "user-registered.dlq" {
+		t.Errorf("topic = %q", dead.Topic)
+	}
+	want := map[string]string{
+		"traceparent":                 "tp",
+		kafka.HeaderError:             errHandler.Error(),
+		kafka.HeaderAttempts:          "1",
+		kafka.HeaderOriginalTopic:     "user-registered",
+		kafka.HeaderOriginalPartition: "1",
+		kafka.HeaderOriginalOffset:    "42",
+	}
+	for k, v := range want {
+		if got := string(dead.Headers[k]); got != v {
+			t.Errorf("header %s = %q, want %q", k, got, v)
+		}
+	}
+	if string(dead.Key) != "key" || string(dead.Value) != "value" {
+		t.Errorf("payload = %q/%q", dead.Key, dead.Value)
+	}
+}
+
+func TestWithDLQ_SendFailureIsFatal(t *testing.T) {
+	errBroker := errors.New("broker down")
+	p := &recordingProducer{err: errBroker}
+	h := WithDLQ(p)(func(context.Context, kafka.Message) error { return kafka.Permanent(errHandler) })
+
+	err := h(context.Background(), testMessage())
+	if !errors.Is(err, errBroker) {
+		t.Errorf("err = %v, want broker error", err)
+	}
+	// иначе следующий коммит уйдёт дальше неопубликованного сообщения
+	if kafka.Classify(err) != kafka.ErrorFatal {
+		t.Errorf("class = %v, want fatal", kafka.Classify(err))
+	}
+}
+
+func TestWithDLQ_SendFailureStopsBeforeCommit(t *testing.T) {
+	p := &recordingProducer{err: errors.New("broker down")}
+
+	var stopped error
+	g := newGroupHandler(failing, func(err error) { stopped = err }, 1, WithDLQ(p))
+	session := &fakeSession{ctx: context.Background()}
+
+	if err := g.ConsumeClaim(session, newFakeClaim("a", "b", "c")); kafka.Classify(err) != kafka.ErrorFatal {
+		t.Fatalf("ConsumeClaim = %v, want fatal", err)
+	}
+	if stopped == nil {
+		t.Error("consumer not stopped")
+	}
+	if got := session.markedOffset(); got != 0 {
+		t.Errorf("marked offset = %d, want 0", got)
+	}
+}
+
+func TestWithRetryTopics_SendFailureIsFatal(t *testing.T) {
+	p := &recordingProducer{err: errors.New("broker down")}
+	h := WithDLQ(p)(WithRetryTopics(p)(failing))
+
+	if err := h(context.Background(), testMessage()); kafka.Classify(err) != kafka.ErrorFatal {
+		t.Errorf("err = %v, want fatal", err)
+	}
+}
+
+func TestWithDLQ_FatalNotPublished(t *testing.T) {
+	p := &recordingProducer{}
+	h := WithDLQ(p)(func(context.Context, kafka.Message) error { return kafka.Fatal(errHandler) })
+
+	if err := h(context.Background(), testMessage()); kafka.Classify(err) != kafka.ErrorFatal {
+		t.Errorf("err = %v, want fatal", err)
+	}
+	if len(p.sent) != 0 {
+		t.Errorf("sent = %d, want 0", len(p.sent))
+	}
+}
+
+func TestWithDLQ_CanceledContextNotPublished(t *testing.T) {
+	p := &recordingProducer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	h := WithDLQ(p)(func(ctx context.Context, _ kafka.Message) error { return ctx.Err() })
+	if err := h(ctx, testMessage()); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if len(p.sent) != 0 {
+		t.Errorf("sent = %d, want 0", len(p.sent))
+	}
+}
+
+func TestWithRetryTopics_Tiers(t *testing.T) {
+	p := &recordingProducer{}
+	tiers := []RetryTier{{Suffix: "a", Delay: time.Millisecond}, {Suffix: "b", Delay: time.Millisecond}}
+	h := WithDLQ(p)(WithRetryTopics(p, tiers...)(failing))
+
+	msg := testMessage()
+	for _, want := range []string{"user-registered.retry.a", "user-registered.retry.b", "user-registered.dlq"} {
+		if err := h(context.Background(), msg); err != nil {
+			t.Fatalf("err = %v", err)
+		}
+
+		next := p.sent[len(p.sent)-1]
+		if next.Topic != want {
+			t.Fatalf("topic = %q, want %q", next.Topic, want)
+		}
+		// координаты первой публикации не меняются при переходе между топиками
+		if got := string(next.Headers[kafka.HeaderOriginalOffset]); got != "42" {
+			t.Errorf("original offset = %q, want 42", got)
+		}
+
+		// консьюмер читает сообщение уже из нового топика
+		msg = next
+		msg.Partition, msg.Offset = 0, int64(len(p.sent))
+	}
+
+	if got := msg.Attempts(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+	if _, ok := msg.Headers[kafka.HeaderRetryAt]; ok {
+		t.Error("dlq message has retry-at header")
+	}
+}
+
+func TestWithRetryTopics_PermanentSkipsTiers(t *testing.T) {
+	p := &recordingProducer{}
+	h := WithDLQ(p)(WithRetryTopics(p)(func(context.Context, kafka.Message) error { return kafka.Permanent(errHandler) }))
+
+	if err := h(context.Background(), testMessage()); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if len(p.sent) != 1 || p.sent[0].Topic != "user-registered.dlq" {
+		t.Errorf("sent = %+v, want one dlq message", p.sent)
+	}
+}
+
+func TestWithRetryTopics_WaitsRetryAt(t *testing.T) {
+	var handled time.Time
+	h := WithRetryTopics(&recordingProducer{})(func(context.Context, kafka.Message) error {
+		handled = time.Now()
+		return nil
+	})
+
+	msg := testMessage()
+	retryAt := time.Now().Add(50 * time.Millisecond)
+	msg.Headers[kafka.HeaderRetryAt] = []byte(retryAt.UTC().Format(time.RFC3339Nano))
+
+	if err := h(context.Background(), msg); err != nil {
+		t.Fatalf("err = %v", err)
+	}
+	if handled.Before(retryAt) {
+		t.Errorf("handled %v before retry-at %v", handled, retryAt)
+	}
+
+	// отмена контекста прерывает ожидание
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	msg.Headers[kafka.HeaderRetryAt] = []byte(time.Now().Add(time.Hour).UTC().Format(time.RFC3339Nano))
+	if err := h(ctx, msg); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want deadline exceeded", err)
+	}
+}
@@ -1,27 +1,77 @@
 package consumer
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"github.com/IBM/sarama"
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
 )
 
 type Middleware func(next kafka.MessageHandler) kafka.MessageHandler
 
 type groupHandler struct {
 	handler kafka.MessageHandler
+	// stop - останавливает Consume при fatal ошибке обработчика
+	stop context.CancelCauseFunc
+	// workers - сколько горутин обрабатывают одну партицию, 1 - последовательно
+	workers int
+	// inflight - воркеры всех партиций текущей сессии, их дожидается Cleanup
+	inflight sync.WaitGroup
+	// batch - если задан, партиции читаются батчами, а handler используется для повторов по одному
+	batch     kafka.BatchHandler
+	batchOpts BatchOptions
+	// txn - если задан, оффсеты коммитятся в транзакциях продюсера
+	txn *transactional
+	// assigned - партиции текущей сессии и помеченные оффсеты, общие с Consumer
+	assigned *assignments
 }
 
-func newGroupHandler(handler kafka.MessageHandler, middlewares ...Middleware) *groupHandler {
+func newGroupHandler(handler kafka.MessageHandler, stop context.CancelCauseFunc, workers int, middlewares ...Middleware) *groupHandler {
+	handler = timed(handler)
 	for i := len(middlewares) - 1; i >= 0; i-- {
 		handler = middlewares[i](handler)
 	}
 
-	return &groupHandler{handler: handler}
+	return &groupHandler{handler: handler, stop: stop, workers: max(workers, 1), assigned: newAssignments()}
+}
+
+// timed - длительность одной попытки обработчика: внутри всех middleware, без retry и backoff.
+func timed(next kafka.MessageHandler) kafka.MessageHandler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		start := time.Now()
+		err := next(ctx, msg)
+		metrics.RecordConsumerHandler(ctx, msg.Topic, outcome(err), time.Since(start))
+
+		return err
+	}
+}
+
+func outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	return kafka.Classify(err).String()
 }
 
 func (g *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	g.assigned.claim(claim)
+	session = trackingSession{ConsumerGroupSession: session, assigned: g.assigned}
+
+	switch {
+	case g.txn != nil:
+		return g.consumeTransactional(session, claim)
+	case g.batch != nil:
+		return g.consumeBatches(session, claim)
+	case g.workers > 1:
+		return g.consumeConcurrently(session, claim)
+	}
+
 	for {
 		select {
 		case message, ok := <-claim.Messages():
@@ -29,36 +79,91 @@ func (g *groupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim s
 				return nil
 			}
 
-			msg := kafka.Message{
-				Key:       message.Key,
-				Value:     message.Value,
-				Topic:     message.Topic,
-				Partition: message.Partition,
-				Offset:    message.Offset,
-				Timestamp: message.Timestamp,
-				Headers:   extractHeaders(message.Headers),
+			done, err := g.process(session.Context(), message)
+			if err != nil {
+				return err
 			}
-
-			if err := g.handler(session.Context(), msg); err != nil {
-				log := logger.Ctx(session.Context())
-				log.Error().
-					Err(err).
-					Str("topic", message.Topic).
-					Int32("partition", message.Partition).
-					Int64("offset", message.Offset).
-					Msg("kafka: handler error, message not marked")
-
-				continue
+			if done {
+				session.MarkMessage(message, "")
 			}
 
-			session.MarkMessage(message, "")
-
 		case <-session.Context().Done():
 			return nil
 		}
 	}
 }
 
+// process - обрабатывает сообщение и решает, можно ли его пометить.
+func (g *groupHandler) process(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+	return g.handle(ctx, message, g.handler(ctx, toMessage(message)))
+}
+
+// handle - решает по ошибке обработчика, можно ли пометить сообщение. Fatal ошибка останавливает consumer и возвращается.
+func (g *groupHandler) handle(ctx context.Context, message *sarama.ConsumerMessage, err error) (bool, error) {
+	done, err := Resolve(ctx, kafka.Message{Topic: message.Topic, Partition: message.Partition, Offset: message.Offset}, err)
+	if err != nil {
+		g.stop(err)
+	}
+
+	return done, err
+}
+
+// Resolve - разбор ошибки обработчика, общий для Consumer и memkafka.Consumer: nil и permanent - сообщение
+// можно пометить, retryable - остаётся непомеченным, fatal возвращается и должен остановить чтение.
+// Из msg нужны только координаты для лога.
+func Resolve(ctx context.Context, msg kafka.Message, err error) (bool, error) {
+	if err == nil {
+		return true, nil
+	}
+
+	log := logger.Ctx(ctx)
+
+	switch kafka.Classify(err) {
+	case kafka.ErrorPermanent:
+		// повтор не поможет, а непомеченное сообщение всё равно пропустят следующие коммиты
+		log.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Msg("kafka: permanent handler error, message skipped")
+
+		return true, nil
+
+	case kafka.ErrorFatal:
+		log.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Msg("kafka: fatal handler error, stopping consumer")
+
+		return false, err
+
+	default:
+		log.Error().
+			Err(err).
+			Str("topic", msg.Topic).
+			Int32("partition", msg.Partition).
+			Int64("offset", msg.Offset).
+			Msg("kafka: handler error, message not marked")
+
+		return false, nil
+	}
+}
+
+func toMessage(message *sarama.ConsumerMessage) kafka.Message {
+	return kafka.Message{
+		Key:       message.Key,
+		Value:     message.Value,
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Timestamp: message.Timestamp,
+		Headers:   extractHeaders(message.Headers),
+	}
+}
+
 // extractHeaders - мапа с хедерами, перезаписывает дубликаты
 func extractHeaders(headers []*sarama.RecordHeader) map[string][]byte {
 	if len(headers) == 0 {
@@ -75,12 +180,27 @@ func extractHeaders(headers []*sarama.RecordHeader) map[string][]byte {
 	return result
 }
 
-// потом че-то придумаю
+// Setup - начало сессии после ребалансировки: партиции уже назначены, ConsumeClaim ещё не запущены.
+func (g *groupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	revoked := g.assigned.start(session)
+	metrics.RecordConsumerRebalance(session.Context(), revoked)
+
+	log := logger.Ctx(session.Context())
+	log.Info().
+		Str("member_id", session.MemberID()).
+		Int32("generation_id", session.GenerationID()).
+		Any("claims", session.Claims()).
+		Dur("unassigned", revoked).
+		Msg("kafka: partitions assigned")
 
-func (g *groupHandler) Setup(sarama.ConsumerGroupSession) error {
 	return nil
 }
 
+// Cleanup - sarama вызывает его при ребалансировке после выхода всех ConsumeClaim и до финального коммита.
+// В конкурентном режиме здесь дожидаемся воркеров: сообщения, которые они успеют закончить, попадут в коммит.
 func (g *groupHandler) Cleanup(sarama.ConsumerGroupSession) error {
+	g.inflight.Wait()
+	g.assigned.stop()
+
 	return nil
 }
@@ -52,38 +52,3 @@ func Logging(next kafka.MessageHandler) kafka.MessageHandler {
 		return err
 	}
 }
-
-// WithRetry - ретраит сообщение несколько раз, если безуспешно - логирует и скипает.Можно добавить отправку в DLQ
-func WithRetry(maxRetries int) Middleware {
-	return func(next kafka.MessageHandler) kafka.MessageHandler {
-		return func(ctx context.Context, msg kafka.Message) error {
-			var err error
-
-			for attempt := 1; attempt <= maxRetries; attempt++ {
-				err = next(ctx, msg)
-				if err == nil {
-					return nil
-				}
-
-				log := logger.Ctx(ctx)
-				log.Warn().
-					Err(err).
-					Int("attempt", attempt).
-					Int("max_retries", maxRetries).
-					Str("topic", msg.Topic).
-					Int64("offset", msg.Offset).
-					Msg("kafka: handler failed, retrying")
-			}
-
-			log := logger.Ctx(ctx)
-			log.Error().
-				Err(err).
-				Str("topic", msg.Topic).
-				Int32("partition", msg.Partition).
-				Int64("offset", msg.Offset).
-				Msg("kafka: message skipped after max retries")
-
-			return nil
-		}
-	}
-}
@@ -0,0 +1,128 @@
+package consumer
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// RetryPolicy - повторы одного сообщения внутри партиции.
+type RetryPolicy struct {
+	// MaxAttempts - всего попыток вместе с первой.
+	MaxAttempts int
+	// BaseDelay - задержка перед вторым повтором, дальше удваивается. 0 - повторы сразу.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxElapsed - после стольких от первой попытки новая не начинается. 0 - без ограничения.
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryPolicy - короткие повторы, долгие ожидания - дело retry топиков, партицию надолго не держим.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	MaxElapsed:  10 * time.Second,
+}
+
+// WithRetry - повторяет сообщение maxRetries раз подряд без задержки.
+func WithRetry(maxRetries int) Middleware {
+	return WithRetryPolicy(RetryPolicy{MaxAttempts: maxRetries})
+}
+
+// WithRetryPolicy - повторяет retryable ошибки с экспоненциальной задержкой и full jitter, ожидание прерывается
+// отменой контекста сессии. Permanent и fatal ошибки возвращаются сразу. Если безуспешно - логирует
+// и возвращает последнюю ошибку, чтобы её могли обработать WithRetryTopics/WithDLQ. Без них сообщение остаётся непомеченным.
+// Каждая неудачная попытка считается в метриках с классом ошибки.
+func WithRetryPolicy(policy RetryPolicy) Middleware {
+	return func(next kafka.MessageHandler) kafka.MessageHandler {
+		return func(ctx context.Context, msg kafka.Message) error {
+			start := time.Now()
+			maxAttempts := max(policy.MaxAttempts, 1)
+
+			var err error
+			for attempt := 1; ; attempt++ {
+				err = next(ctx, msg)
+				if err == nil {
+					return nil
+				}
+				if ctx.Err() != nil {
+					return err
+				}
+
+				class := kafka.Classify(err)
+				metrics.RecordConsumerError(ctx, msg.Topic, class.String())
+				if class != kafka.ErrorRetryable || attempt >= maxAttempts {
+					break
+				}
+
+				delay := backoff(policy, attempt)
+				if policy.MaxElapsed > 0 && time.Since(start)+delay > policy.MaxElapsed {
+					break
+				}
+
+				log := logger.Ctx(ctx)
+				log.Warn().
+					Err(err).
+					Int("attempt", attempt).
+					Int("max_attempts", maxAttempts).
+					Dur("delay", delay).
+					Str("topic", msg.Topic).
+					Int64("offset", msg.Offset).
+					Msg("kafka: handler failed, retrying")
+
+				if waitErr := sleep(ctx, delay); waitErr != nil {
+					return err
+				}
+			}
+
+			log := logger.Ctx(ctx)
+			log.Error().
+				Err(err).
+				Str("class", kafka.Classify(err).String()).
+				Str("topic", msg.Topic).
+				Int32("partition", msg.Partition).
+				Int64("offset", msg.Offset).
+				Msg("kafka: message failed after retries")
+
+			return err
+		}
+	}
+}
+
+// backoff - задержка перед повтором номер attempt+1, full jitter разводит повторы соседних партиций.
+func backoff(policy RetryPolicy, attempt int) time.Duration {
+	if policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := policy.BaseDelay << (attempt - 1)
+	if delay <= 0 || (policy.MaxDelay > 0 && delay > policy.MaxDelay) {
+		delay = policy.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay) //nolint:gosec // jitter, не криптография
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
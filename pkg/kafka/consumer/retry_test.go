@@ -0,0 +1,90 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+func countingHandler(calls *int, err error) kafka.MessageHandler {
+	return func(context.Context, kafka.Message) error {
+		*calls++
+		return err
+	}
+}
+
+func TestWithRetry_ReturnsLastError(t *testing.T) {
+	calls := 0
+	h := WithRetry(3)(countingHandler(&calls, errHandler))
+
+	if err := h(context.Background(), testMessage()); !errors.Is(err, errHandler) {
+		t.Errorf("err = %v, want handler error", err)
+	}
+	if calls != 3 {
+		t.Errorf("calls = %d, want 3", calls)
+	}
+}
+
+func TestWithRetryPolicy_NotRetried(t *testing.T) {
+	for name, err := range map[string]error{
+		"permanent": kafka.Permanent(errHandler),
+		"fatal":     kafka.Fatal(errHandler),
+	} {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			h := WithRetryPolicy(DefaultRetryPolicy)(countingHandler(&calls, err))
+
+			if got := h(context.Background(), testMessage()); !errors.Is(got, errHandler) {
+				t.Errorf("err = %v, want handler error", got)
+			}
+			if calls != 1 {
+				t.Errorf("calls = %d, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestWithRetryPolicy_MaxElapsed(t *testing.T) {
+	calls := 0
+	policy := RetryPolicy{MaxAttempts: 100, BaseDelay: 10 * time.Millisecond, MaxDelay: 10 * time.Millisecond, MaxElapsed: 50 * time.Millisecond}
+	h := WithRetryPolicy(policy)(countingHandler(&calls, errHandler))
+
+	start := time.Now()
+	_ = h(context.Background(), testMessage())
+
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("elapsed = %v, want about MaxElapsed", elapsed)
+	}
+	if calls < 2 || calls >= 100 {
+		t.Errorf("calls = %d", calls)
+	}
+}
+
+func TestWithRetryPolicy_CancelStopsBackoff(t *testing.T) {
+	calls := 0
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	h := WithRetryPolicy(policy)(countingHandler(&calls, errHandler))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := h(ctx, testMessage()); !errors.Is(err, errHandler) {
+		t.Errorf("err = %v, want handler error", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestBackoff_Bounded(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt < 70; attempt++ {
+		if d := backoff(policy, attempt); d < 0 || d > policy.MaxDelay {
+			t.Fatalf("backoff(%d) = %v", attempt, d)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package consumer
+
+import (
+	"context"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// RetryTier - ступень отложенного повтора: топик <исходный>.retry.<Suffix>, обработка не раньше чем через Delay.
+type RetryTier struct {
+	Suffix string
+	Delay  time.Duration
+}
+
+// DefaultRetryTiers - повтор через минуту, затем через десять.
+var DefaultRetryTiers = []RetryTier{
+	{Suffix: "1m", Delay: time.Minute},
+	{Suffix: "10m", Delay: 10 * time.Minute},
+}
+
+// RetryTopics - топики ступеней для topic, на них consumer подписывается вместе с исходным.
+func RetryTopics(topic string, tiers []RetryTier) []string {
+	topics := make([]string, 0, len(tiers))
+	for _, t := range tiers {
+		topics = append(topics, kafka.RetryTopic(topic, t.Suffix))
+	}
+
+	return topics
+}
+
+// WithRetryTopics - сообщение, которое не удалось обработать, откладывается в следующую ступень retry топиков
+// и помечается прочитанным, партиция не блокируется. Сообщение из retry топика обрабатывается не раньше x-retry-at.
+// Когда ступени кончились, ошибка возвращается дальше - обычно в WithDLQ, который стоит перед этим middleware.
+// Permanent и fatal ошибки возвращаются сразу, ступени на них не тратятся.
+// Без тиров используются DefaultRetryTiers.
+func WithRetryTopics(p kafka.Producer, tiers ...RetryTier) Middleware {
+	if len(tiers) == 0 {
+		tiers = DefaultRetryTiers
+	}
+
+	return func(next kafka.MessageHandler) kafka.MessageHandler {
+		return func(ctx context.Context, msg kafka.Message) error {
+			if err := waitUntil(ctx, msg.RetryAt()); err != nil {
+				return err
+			}
+
+			err := next(ctx, msg)
+			if err == nil || ctx.Err() != nil || kafka.Classify(err) != kafka.ErrorRetryable {
+				return err
+			}
+
+			attempt := msg.Attempts()
+			if attempt >= len(tiers) {
+				return err
+			}
+
+			tier := tiers[attempt]
+			topic := kafka.RetryTopic(msg.OriginalTopic(), tier.Suffix)
+			now := time.Now()
+
+			retry := msg.Failed(err, attempt+1, now)
+			retry.Topic = topic
+			retry.Headers[kafka.HeaderRetryAt] = []byte(now.Add(tier.Delay).UTC().Format(time.RFC3339Nano))
+
+			if sendErr := p.Send(ctx, retry); sendErr != nil {
+				return publishError(ctx, topic, sendErr, err)
+			}
+
+			metrics.RecordConsumerRetry(ctx, msg.OriginalTopic(), topic)
+
+			log := logger.Ctx(ctx)
+			log.Warn().
+				Err(err).
+				Str("topic", msg.Topic).
+				Int32("partition", msg.Partition).
+				Int64("offset", msg.Offset).
+				Str("retry_topic", topic).
+				Int("attempt", attempt+1).
+				Msg("kafka: message deferred to retry topic")
+
+			return nil
+		}
+	}
+}
+
+// waitUntil - в retry топике сообщения идут по возрастанию x-retry-at, поэтому ожидание
+// первого из них задерживает только свою партицию и ровно настолько, насколько нужно.
+func waitUntil(ctx context.Context, at time.Time) error {
+	return sleep(ctx, time.Until(at))
+}
@@ -0,0 +1,120 @@
+package consumer
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+)
+
+// TransformHandler - шаг consume-transform-produce: всё, что отправлено через tx, коммитится атомарно
+// вместе с оффсетом msg.
+type TransformHandler func(ctx context.Context, msg kafka.Message, tx *producer.Tx) error
+
+// NewTransactionalConfig - конфиг группы для ConsumeTransactional: читаются только закоммиченные транзакции,
+// оффсеты коммитит продюсер, а не автокоммит группы.
+func NewTransactionalConfig() *sarama.Config {
+	config := NewConfig()
+	config.Consumer.IsolationLevel = sarama.ReadCommitted
+	config.Consumer.Offsets.AutoCommit.Enable = false
+
+	return config
+}
+
+type transactional struct {
+	producer *producer.TransactionalProducer
+	groupID  string
+}
+
+type txnState struct {
+	message   *sarama.ConsumerMessage
+	committed bool
+}
+
+type txnStateKey struct{}
+
+// ConsumeTransactional - consume-transform-produce с exactly-once: на каждое сообщение одна транзакция p, в которую
+// входят отправленные обработчиком сообщения и оффсет группы groupID. Группа должна быть создана с NewTransactionalConfig.
+// Middleware консьюмера оборачивают всю попытку: retry повторяет транзакцию целиком, а сообщение, которое
+// WithDLQ отправил в DLQ или которое пропущено из-за permanent ошибки, коммитится отдельной транзакцией только с оффсетом.
+// Транзакции одного продюсера идут по очереди, поэтому Concurrent в этом режиме не действует.
+func (c *Consumer) ConsumeTransactional(
+	ctx context.Context,
+	p *producer.TransactionalProducer,
+	groupID string,
+	handler TransformHandler,
+) error {
+	txn := &transactional{producer: p, groupID: groupID}
+
+	return c.run(ctx, func(stop context.CancelCauseFunc) *groupHandler {
+		gh := newGroupHandler(txn.handler(handler), stop, 1, c.middlewares...)
+		gh.txn = txn
+
+		return gh
+	})
+}
+
+// handler - попытка обработки: транзакция с результатом обработчика и оффсетом сообщения.
+func (t *transactional) handler(handler TransformHandler) kafka.MessageHandler {
+	return func(ctx context.Context, msg kafka.Message) error {
+		state, _ := ctx.Value(txnStateKey{}).(*txnState)
+
+		err := t.producer.Transaction(ctx, func(tx *producer.Tx) error {
+			if err := handler(ctx, msg, tx); err != nil {
+				return err
+			}
+
+			return tx.AddConsumed(state.message, t.groupID)
+		})
+		if err == nil {
+			state.committed = true
+		}
+
+		return err
+	}
+}
+
+func (g *groupHandler) consumeTransactional(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case message, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			state := &txnState{message: message}
+			ctx := context.WithValue(session.Context(), txnStateKey{}, state)
+
+			done, err := g.handle(session.Context(), message, g.handler(ctx, toMessage(message)))
+			if err != nil {
+				return err
+			}
+			if !done {
+				continue
+			}
+			if state.committed {
+				g.assigned.mark(message.Topic, message.Partition, message.Offset+1)
+				continue
+			}
+
+			// сообщение закончено без транзакции обработчика: оффсет коммитится отдельно,
+			// чтобы все оффсеты группы шли только через транзакции
+			err = g.txn.producer.Transaction(session.Context(), func(tx *producer.Tx) error {
+				return tx.AddConsumed(message, g.txn.groupID)
+			})
+			if err != nil {
+				if _, ferr := g.handle(session.Context(), message, err); ferr != nil {
+					return ferr
+				}
+				continue
+			}
+
+			g.assigned.mark(message.Topic, message.Partition, message.Offset+1)
+
+		case <-session.Context().Done():
+			return nil
+		}
+	}
+}
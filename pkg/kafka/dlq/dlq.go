@@ -0,0 +1,200 @@
+// Package dlq - чтение и переотправка сообщений из DLQ топиков, которые пишет consumer.WithDLQ.
+package dlq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+var ErrNoOriginalTopic = errors.New("dlq: message has no original topic")
+
+// Record - сообщение из DLQ с разобранными служебными заголовками.
+type Record struct {
+	Message kafka.Message
+
+	Error             string
+	Attempts          int
+	OriginalTopic     string
+	OriginalPartition int32
+	OriginalOffset    int64
+	FailedAt          time.Time
+}
+
+// ParseRecord разбирает заголовки, которые добавляет consumer.WithDLQ. Отсутствующие поля остаются нулевыми.
+func ParseRecord(msg kafka.Message) Record {
+	r := Record{
+		Message:       msg,
+		Error:         string(msg.Headers[kafka.HeaderError]),
+		Attempts:      msg.Attempts(),
+		OriginalTopic: string(msg.Headers[kafka.HeaderOriginalTopic]),
+	}
+
+	if p, err := strconv.ParseInt(string(msg.Headers[kafka.HeaderOriginalPartition]), 10, 32); err == nil {
+		r.OriginalPartition = int32(p)
+	}
+	if o, err := strconv.ParseInt(string(msg.Headers[kafka.HeaderOriginalOffset]), 10, 64); err == nil {
+		r.OriginalOffset = o
+	}
+	if t, err := time.Parse(time.RFC3339Nano, string(msg.Headers[kafka.HeaderFailedAt])); err == nil {
+		r.FailedAt = t
+	}
+
+	return r
+}
+
+// Filter - какие записи DLQ отбирать. Нулевой фильтр отбирает всё.
+type Filter struct {
+	// Partitions - только эти партиции DLQ, пусто - все
+	Partitions []int32
+	// FromOffset - начиная с этого оффсета в партиции
+	FromOffset int64
+	// Limit - не больше стольких записей, 0 - без ограничения
+	Limit int
+}
+
+func (f Filter) matchPartition(partition int32) bool {
+	return len(f.Partitions) == 0 || slices.Contains(f.Partitions, partition)
+}
+
+// Scan читает снимок DLQ топика: все сообщения, которые были в нём на момент вызова,
+// и для каждого отобранного фильтром вызывает fn. Оффсеты группы не коммитятся, топик не меняется.
+func Scan(ctx context.Context, client sarama.Client, topic string, filter Filter, fn func(Record) error) error {
+	partitions, err := client.Partitions(topic)
+	if err != nil {
+		return fmt.Errorf("dlq: partitions of %s: %w", topic, err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("dlq: create consumer: %w", err)
+	}
+	defer consumer.Close() //nolint:errcheck // только чтение
+
+	seen := 0
+	for _, partition := range partitions {
+		if !filter.matchPartition(partition) {
+			continue
+		}
+
+		n, err := scanPartition(ctx, client, consumer, topic, partition, filter, seen, fn)
+		seen += n
+		if err != nil {
+			return err
+		}
+		if filter.Limit > 0 && seen >= filter.Limit {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func scanPartition(
+	ctx context.Context,
+	client sarama.Client,
+	consumer sarama.Consumer,
+	topic string,
+	partition int32,
+	filter Filter,
+	seen int,
+	fn func(Record) error,
+) (int, error) {
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return 0, fmt.Errorf("dlq: oldest offset %s/%d: %w", topic, partition, err)
+	}
+	// high water mark на момент вызова - то, что придёт позже, в снимок не попадает
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return 0, fmt.Errorf("dlq: newest offset %s/%d: %w", topic, partition, err)
+	}
+
+	from := max(oldest, filter.FromOffset)
+	if from >= newest {
+		return 0, nil
+	}
+
+	pc, err := consumer.ConsumePartition(topic, partition, from)
+	if err != nil {
+		return 0, fmt.Errorf("dlq: consume %s/%d: %w", topic, partition, err)
+	}
+	defer pc.Close() //nolint:errcheck // только чтение
+
+	n := 0
+	for {
+		select {
+		case m := <-pc.Messages():
+			if err := fn(ParseRecord(toMessage(m))); err != nil {
+				return n, err
+			}
+			n++
+
+			if m.Offset+1 >= newest || (filter.Limit > 0 && seen+n >= filter.Limit) {
+				return n, nil
+			}
+		case err := <-pc.Errors():
+			return n, fmt.Errorf("dlq: read %s/%d: %w", topic, partition, err)
+		case <-ctx.Done():
+			return n, ctx.Err()
+		}
+	}
+}
+
+// Replay публикует сообщение обратно в исходный топик без служебных заголовков DLQ,
+// поэтому консьюмер обрабатывает его как новое, с полным набором повторов.
+// Из DLQ сообщение не удаляется - повторный запуск с тем же фильтром отправит его снова.
+func Replay(ctx context.Context, p kafka.Producer, r Record) error {
+	if r.OriginalTopic == "" {
+		return ErrNoOriginalTopic
+	}
+
+	headers := make(map[string][]byte, len(r.Message.Headers))
+	for k, v := range r.Message.Headers {
+		headers[k] = v
+	}
+	for _, k := range []string{
+		kafka.HeaderError,
+		kafka.HeaderAttempts,
+		kafka.HeaderOriginalTopic,
+		kafka.HeaderOriginalPartition,
+		kafka.HeaderOriginalOffset,
+		kafka.HeaderFailedAt,
+		kafka.HeaderRetryAt,
+	} {
+		delete(headers, k)
+	}
+
+	return p.Send(ctx, kafka.Message{
+		Key:     r.Message.Key,
+		Value:   r.Message.Value,
+		Headers: headers,
+		Topic:   r.OriginalTopic,
+	})
+}
+
+func toMessage(m *sarama.ConsumerMessage) kafka.Message {
+	headers := make(map[string][]byte, len(m.Headers))
+	for _, h := range m.Headers {
+		if h != nil && h.Key != nil {
+			headers[string(h.Key)] = h.Value
+		}
+	}
+
+	return kafka.Message{
+		Key:       m.Key,
+		Value:     m.Value,
+		Headers:   headers,
+		Topic:     m.Topic,
+		Partition: m.Partition,
+		Offset:    m.Offset,
+		Timestamp: m.Timestamp,
+	}
+}
@@ -0,0 +1,91 @@
+package dlq
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+type recordingProducer struct {
+	sent []kafka.Message
+}
+
+func (p *recordingProducer) Send(_ context.Context, msg kafka.Message) error {
+	p.sent = append(p.sent, msg)
+	return nil
+}
+
+func (p *recordingProducer) Close() error { return nil }
+
+func failedMessage() kafka.Message {
+	src := kafka.Message{
+		Key:       []byte("key"),
+		Value:     []byte("value"),
+		Headers:   map[string][]byte{"traceparent": []byte("tp")},
+		Topic:     "user-registered",
+		Partition: 1,
+		Offset:    42,
+	}
+
+	dead := src.Failed(errors.New("boom"), 3, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	dead.Topic = kafka.DLQTopic(src.Topic)
+
+	return dead
+}
+
+func TestParseRecord(t *testing.T) {
+	r := ParseRecord(failedMessage())
+
+	if r.Error != "boom" || r.Attempts != 3 {
+		t.Errorf("error/attempts = %q/%d", r.Error, r.Attempts)
+	}
+	if r.OriginalTopic != "user-registered" || r.OriginalPartition != 1 || r.OriginalOffset != 42 {
+		t.Errorf("original = %s/%d/%d", r.OriginalTopic, r.OriginalPartition, r.OriginalOffset)
+	}
+	if !r.FailedAt.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("failed at = %v", r.FailedAt)
+	}
+}
+
+func TestReplay_StripsDLQHeaders(t *testing.T) {
+	p := &recordingProducer{}
+
+	if err := Replay(context.Background(), p, ParseRecord(failedMessage())); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	msg := p.sent[0]
+	if msg.Topic != "user-registered" {
+		t.Errorf("topic = %q", msg.Topic)
+	}
+	if len(msg.Headers) != 1 || string(msg.Headers["traceparent"]) != "tp" {
+		t.Errorf("headers = %v, want only traceparent", msg.Headers)
+	}
+	if msg.Attempts() != 0 {
+		t.Errorf("attempts = %d, want 0", msg.Attempts())
+	}
+}
+
+func TestReplay_NoOriginalTopic(t *testing.T) {
+	err := Replay(context.Background(), &recordingProducer{}, Record{})
+	if !errors.Is(err, ErrNoOriginalTopic) {
+		t.Errorf("err = %v, want ErrNoOriginalTopic", err)
+	}
+}
+
+func TestFilter_ZeroValueMatchesAllPartitions(t *testing.T) {
+	var all Filter
+	for _, p := range []int32{0, 1, 5} {
+		if !all.matchPartition(p) {
+			t.Errorf("zero filter skips partition %d", p)
+		}
+	}
+
+	only := Filter{Partitions: []int32{1}}
+	if only.matchPartition(0) || !only.matchPartition(1) {
+		t.Error("filter by partition 1 must match only partition 1")
+	}
+}
@@ -0,0 +1,180 @@
+// Package envelope - версионированные контракты событий Kafka: proto схемы из proto/events,
+// общий конверт eventsv1.Envelope и сериализация в JSON или protobuf по заголовку content-type.
+package envelope
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+// Значения заголовка kafka.HeaderContentType. Сообщение без заголовка читается как JSON.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+var (
+	ErrUnsupportedContentType = errors.New("unsupported content type")
+	ErrTypeMismatch           = errors.New("event type mismatch")
+	ErrUnsupportedVersion     = errors.New("unsupported event version")
+	ErrInvalidPackage         = errors.New("event package has no version suffix")
+	// ErrNotEnveloped - JSON без поля type: событие в формате до конвертов (плоский JSON),
+	// такие ещё лежат в топиках и неотправленных строках outbox. Разбирает их сам консьюмер.
+	ErrNotEnveloped = errors.New("event is not enveloped")
+)
+
+// Meta - поля конверта, которые задаёт продюсер.
+type Meta struct {
+	ID         string
+	OccurredAt time.Time
+	Producer   string
+}
+
+// Encode упаковывает payload в конверт и сериализует его в формате contentType.
+// Тип и версия конверта берутся из proto пакета payload: events.v1.UserRegistered -> версия 1.
+func Encode(payload proto.Message, meta Meta, contentType string) ([]byte, error) {
+	version, err := Version(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := anypb.New(payload)
+	if err != nil {
+		return nil, fmt.Errorf("pack payload: %w", err)
+	}
+
+	env := &eventsv1.Envelope{
+		Id:         meta.ID,
+		Type:       TypeOf(payload),
+		Version:    version,
+		OccurredAt: timestamppb.New(meta.OccurredAt),
+		Producer:   meta.Producer,
+		Payload:    body,
+	}
+
+	switch mediaType(contentType) {
+	case ContentTypeJSON:
+		return protojson.MarshalOptions{UseProtoNames: true}.Marshal(env)
+	case ContentTypeProtobuf:
+		return proto.Marshal(env)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+}
+
+// NewMessage - сообщение с закодированным событием и заголовком content-type.
+func NewMessage(key []byte, payload proto.Message, meta Meta, contentType string) (kafka.Message, error) {
+	value, err := Encode(payload, meta, contentType)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+
+	return kafka.Message{
+		Key:     key,
+		Value:   value,
+		Headers: map[string][]byte{kafka.HeaderContentType: []byte(contentType)},
+	}, nil
+}
+
+// Decode разбирает конверт из сообщения и распаковывает событие в payload.
+// Тип и версия в конверте должны совпадать с payload. Все ошибки - kafka.Permanent:
+// повторная обработка того же сообщения их не исправит.
+func Decode(msg kafka.Message, payload proto.Message) (*eventsv1.Envelope, error) {
+	env, err := decodeEnvelope(msg)
+	if err != nil {
+		return nil, kafka.Permanent(err)
+	}
+
+	if env.GetType() == "" && isJSON(msg) {
+		return nil, kafka.Permanent(ErrNotEnveloped)
+	}
+
+	if want := TypeOf(payload); env.GetType() != want {
+		return nil, kafka.Permanent(fmt.Errorf("%w: got %q, want %q", ErrTypeMismatch, env.GetType(), want))
+	}
+
+	version, err := Version(payload)
+	if err != nil {
+		return nil, kafka.Permanent(err)
+	}
+
+	if env.GetVersion() != version {
+		return nil, kafka.Permanent(fmt.Errorf("%w: %s v%d", ErrUnsupportedVersion, env.GetType(), env.GetVersion()))
+	}
+
+	if err := env.GetPayload().UnmarshalTo(payload); err != nil {
+		return nil, kafka.Permanent(fmt.Errorf("unpack payload: %w", err))
+	}
+
+	return env, nil
+}
+
+// isJSON - сообщение в JSON: с заголовком application/json или без заголовка, как до конвертов.
+func isJSON(msg kafka.Message) bool {
+	contentType := msg.ContentType()
+	return contentType == "" || mediaType(contentType) == ContentTypeJSON
+}
+
+func decodeEnvelope(msg kafka.Message) (*eventsv1.Envelope, error) {
+	contentType := msg.ContentType()
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+
+	env := &eventsv1.Envelope{}
+
+	switch mediaType(contentType) {
+	case ContentTypeJSON:
+		// новые поля от более свежего продюсера не ломают старого консьюмера
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(msg.Value, env); err != nil {
+			return nil, fmt.Errorf("unmarshal json envelope: %w", err)
+		}
+	case ContentTypeProtobuf:
+		if err := proto.Unmarshal(msg.Value, env); err != nil {
+			return nil, fmt.Errorf("unmarshal protobuf envelope: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedContentType, contentType)
+	}
+
+	return env, nil
+}
+
+// TypeOf - тип события для поля Envelope.type: полное имя proto сообщения.
+func TypeOf(payload proto.Message) string {
+	return string(payload.ProtoReflect().Descriptor().FullName())
+}
+
+// Version - мажорная версия схемы из суффикса proto пакета: events.v1 -> 1.
+func Version(payload proto.Message) (uint32, error) {
+	pkg := string(payload.ProtoReflect().Descriptor().ParentFile().Package())
+
+	suffix := pkg[strings.LastIndex(pkg, ".")+1:]
+	if !strings.HasPrefix(suffix, "v") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPackage, pkg)
+	}
+
+	v, err := strconv.ParseUint(suffix[1:], 10, 32)
+	if err != nil || v == 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPackage, pkg)
+	}
+
+	return uint32(v), nil
+}
+
+// mediaType отбрасывает параметры: "application/json; charset=utf-8" -> "application/json".
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.TrimSpace(mt)
+}
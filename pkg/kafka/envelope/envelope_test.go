@@ -0,0 +1,228 @@
+package envelope
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+var testMeta = Meta{
+	ID:         "0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11",
+	OccurredAt: time.Date(2026, 1, 15, 10, 30, 0, 0, time.UTC),
+	Producer:   "auth",
+}
+
+func testPayload() *eventsv1.UserRegistered {
+	return &eventsv1.UserRegistered{
+		UserId: "5c1f2a7e-8d4b-4f0e-9b6a-2e7d3c9a1f40",
+		Email:  "user@example.com",
+		Code:   "123456",
+	}
+}
+
+func assertDecoded(t *testing.T, env *eventsv1.Envelope, got *eventsv1.UserRegistered) {
+	t.Helper()
+
+	if env.GetId() != testMeta.ID {
+		t.Errorf("id = %q, want %q", env.GetId(), testMeta.ID)
+	}
+	if env.GetType() != "events.v1.UserRegistered" {
+		t.Errorf("type = %q, want events.v1.UserRegistered", env.GetType())
+	}
+	if env.GetVersion() != 1 {
+		t.Errorf("version = %d, want 1", env.GetVersion())
+	}
+	if !env.GetOccurredAt().AsTime().Equal(testMeta.OccurredAt) {
+		t.Errorf("occurred_at = %v, want %v", env.GetOccurredAt().AsTime(), testMeta.OccurredAt)
+	}
+	if env.GetProducer() != testMeta.Producer {
+		t.Errorf("producer = %q, want %q", env.GetProducer(), testMeta.Producer)
+	}
+	if !proto.Equal(got, testPayload()) {
+		t.Errorf("payload = %v, want %v", got, testPayload())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, contentType := range []string{ContentTypeJSON, ContentTypeProtobuf} {
+		t.Run(contentType, func(t *testing.T) {
+			msg, err := NewMessage([]byte("key"), testPayload(), testMeta, contentType)
+			if err != nil {
+				t.Fatalf("NewMessage: %v", err)
+			}
+
+			if msg.ContentType() != contentType {
+				t.Errorf("content-type = %q, want %q", msg.ContentType(), contentType)
+			}
+
+			var got eventsv1.UserRegistered
+			env, err := Decode(msg, &got)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			assertDecoded(t, env, &got)
+		})
+	}
+}
+
+// Фикстуры записаны один раз и не перегенерируются: сообщения, уже лежащие в топиках,
+// должны читаться и после изменений схемы.
+func TestDecode_Fixtures(t *testing.T) {
+	tests := []struct {
+		file        string
+		contentType string
+	}{
+		{"testdata/user_registered.v1.json", ContentTypeJSON},
+		{"testdata/user_registered.v1.pb", ContentTypeProtobuf},
+		{"testdata/user_registered.v1.json", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file+"/"+tt.contentType, func(t *testing.T) {
+			value, err := os.ReadFile(tt.file)
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			msg := kafka.Message{Value: value}
+			if tt.contentType != "" {
+				msg.Headers = map[string][]byte{kafka.HeaderContentType: []byte(tt.contentType)}
+			}
+
+			var got eventsv1.UserRegistered
+			env, err := Decode(msg, &got)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			assertDecoded(t, env, &got)
+		})
+	}
+}
+
+func TestDecode_IgnoresUnknownJSONFields(t *testing.T) {
+	value := []byte(`{
+		"id": "0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11",
+		"type": "events.v1.UserRegistered",
+		"version": 1,
+		"occurred_at": "2026-01-15T10:30:00Z",
+		"producer": "auth",
+		"trace_id": "abc",
+		"payload": {
+			"@type": "type.googleapis.com/events.v1.UserRegistered",
+			"user_id": "5c1f2a7e-8d4b-4f0e-9b6a-2e7d3c9a1f40",
+			"email": "user@example.com",
+			"code": "123456",
+			"locale": "ru"
+		}
+	}`)
+
+	var got eventsv1.UserRegistered
+	env, err := Decode(kafka.Message{Value: value}, &got)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	assertDecoded(t, env, &got)
+}
+
+func TestDecode_Errors(t *testing.T) {
+	otherType, err := NewMessage(nil, &eventsv1.Envelope{}, testMeta, ContentTypeProtobuf)
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+
+	body, err := anypb.New(testPayload())
+	if err != nil {
+		t.Fatalf("anypb.New: %v", err)
+	}
+	v2, err := proto.Marshal(&eventsv1.Envelope{Type: "events.v1.UserRegistered", Version: 2, Payload: body})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		msg  kafka.Message
+		want error
+	}{
+		{
+			name: "unsupported content type",
+			msg: kafka.Message{
+				Value:   []byte("<xml/>"),
+				Headers: map[string][]byte{kafka.HeaderContentType: []byte("application/xml")},
+			},
+			want: ErrUnsupportedContentType,
+		},
+		{
+			name: "type mismatch",
+			msg:  otherType,
+			want: ErrTypeMismatch,
+		},
+		{
+			name: "unsupported version",
+			msg: kafka.Message{
+				Value:   v2,
+				Headers: map[string][]byte{kafka.HeaderContentType: []byte(ContentTypeProtobuf)},
+			},
+			want: ErrUnsupportedVersion,
+		},
+		{
+			name: "flat json before envelopes",
+			msg: kafka.Message{
+				Value:   []byte(`{"event_id":"e1","user_id":"u1","email":"a@b.c","code":"123456"}`),
+				Headers: map[string][]byte{kafka.HeaderContentType: []byte(ContentTypeJSON)},
+			},
+			want: ErrNotEnveloped,
+		},
+		{
+			name: "broken json",
+			msg:  kafka.Message{Value: []byte("{")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.msg, &eventsv1.UserRegistered{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+
+			if kafka.Classify(err) != kafka.ErrorPermanent {
+				t.Errorf("class = %v, want permanent", kafka.Classify(err))
+			}
+		})
+	}
+}
+
+func TestEncode_UnsupportedContentType(t *testing.T) {
+	_, err := Encode(testPayload(), testMeta, "text/plain")
+	if !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("error = %v, want ErrUnsupportedContentType", err)
+	}
+}
+
+func TestMediaTypeParams(t *testing.T) {
+	msg, err := NewMessage(nil, testPayload(), testMeta, ContentTypeJSON)
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+	msg.Headers[kafka.HeaderContentType] = []byte("application/json; charset=utf-8")
+
+	var got eventsv1.UserRegistered
+	if _, err := Decode(msg, &got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+}
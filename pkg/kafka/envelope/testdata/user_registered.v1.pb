@@ -0,0 +1,4 @@
+
+$0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11events.v1.UserRegistered"*auth2p
+,type.googleapis.com/events.v1.UserRegistered@
+$5c1f2a7e-8d4b-4f0e-9b6a-2e7d3c9a1f40user@example.com123456
\ No newline at end of file
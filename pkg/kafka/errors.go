@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorClass - как consumer поступает с ошибкой обработчика.
+type ErrorClass int
+
+const (
+	// ErrorRetryable - временная ошибка, сообщение повторяется. Ошибки без класса считаются такими.
+	ErrorRetryable ErrorClass = iota
+	// ErrorPermanent - повтор не поможет (битый payload): сообщение сразу уходит в DLQ или пропускается.
+	ErrorPermanent
+	// ErrorFatal - продолжать чтение нельзя: consumer останавливается, сообщение не помечается.
+	ErrorFatal
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorPermanent:
+		return "permanent"
+	case ErrorFatal:
+		return "fatal"
+	default:
+		return "retryable"
+	}
+}
+
+type classifiedError struct {
+	err   error
+	class ErrorClass
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неисправимую повтором.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classifiedError{err: err, class: ErrorPermanent}
+}
+
+// Fatal помечает ошибку, после которой consumer должен остановиться.
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &classifiedError{err: err, class: ErrorFatal}
+}
+
+// Classify - класс ошибки по цепочке обёрток, ErrorRetryable если его никто не задал.
+func Classify(err error) ErrorClass {
+	var ce *classifiedError
+	if errors.As(err, &ce) {
+		return ce.class
+	}
+
+	return ErrorRetryable
+}
+
+// BatchError - частичная ошибка BatchHandler: Failed - индексы необработанных сообщений и их ошибки.
+// Нулевое значение готово к использованию.
+type BatchError struct {
+	Failed map[int]error
+}
+
+// Fail отмечает сообщение с индексом i необработанным.
+func (e *BatchError) Fail(i int, err error) {
+	if e.Failed == nil {
+		e.Failed = make(map[int]error)
+	}
+	e.Failed[i] = err
+}
+
+// ErrOrNil - nil, если ни одно сообщение не отмечено.
+func (e *BatchError) ErrOrNil() error {
+	if len(e.Failed) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("kafka: %d messages of batch failed", len(e.Failed))
+}
@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+)
+
+// Заголовки, которые consumer добавляет при перекладывании сообщения в retry топик или DLQ.
+// Исходные заголовки сообщения сохраняются как есть.
+const (
+	HeaderError             = "x-error"
+	HeaderAttempts          = "x-attempts"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderFailedAt          = "x-failed-at"
+	// HeaderRetryAt - раньше этого времени сообщение из retry топика не обрабатывается
+	HeaderRetryAt = "x-retry-at"
+)
+
+// HeaderContentType - формат сериализации payload, см. pkg/kafka/envelope.
+const HeaderContentType = "content-type"
+
+const dlqSuffix = ".dlq"
+
+// ContentType - значение HeaderContentType, пустое если заголовка нет.
+func (m Message) ContentType() string { return string(m.Headers[HeaderContentType]) }
+
+// DLQTopic - топик мёртвых сообщений для исходного топика.
+func DLQTopic(topic string) string { return topic + dlqSuffix }
+
+// RetryTopic - топик отложенного повтора, например user-registered.retry.1m.
+func RetryTopic(topic, tier string) string { return topic + ".retry." + tier }
+
+// OriginalTopic - топик, в который сообщение было опубликовано изначально: для сообщений из retry топика
+// и DLQ берётся из заголовка, иначе это топик самого сообщения.
+func (m Message) OriginalTopic() string {
+	if v, ok := m.Headers[HeaderOriginalTopic]; ok && len(v) > 0 {
+		return string(v)
+	}
+
+	return m.Topic
+}
+
+// Attempts - сколько раз сообщение уже не удалось обработать, 0 для сообщения из исходного топика.
+func (m Message) Attempts() int {
+	n, err := strconv.Atoi(string(m.Headers[HeaderAttempts]))
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// RetryAt - время из HeaderRetryAt, нулевое если заголовка нет.
+func (m Message) RetryAt() time.Time {
+	t, err := time.Parse(time.RFC3339Nano, string(m.Headers[HeaderRetryAt]))
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+// Failed - копия сообщения для повторной публикации после ошибки: исходные заголовки, ошибка,
+// номер попытки и координаты первой публикации. Топик назначает вызывающий.
+func (m Message) Failed(err error, attempts int, now time.Time) Message {
+	headers := make(map[string][]byte, len(m.Headers)+6)
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+
+	// координаты первой публикации переживают все retry топики
+	if _, ok := headers[HeaderOriginalTopic]; !ok {
+		headers[HeaderOriginalTopic] = []byte(m.Topic)
+		headers[HeaderOriginalPartition] = []byte(strconv.FormatInt(int64(m.Partition), 10))
+		headers[HeaderOriginalOffset] = []byte(strconv.FormatInt(m.Offset, 10))
+	}
+	headers[HeaderError] = []byte(err.Error())
+	headers[HeaderAttempts] = []byte(strconv.Itoa(attempts))
+	headers[HeaderFailedAt] = []byte(now.UTC().Format(time.RFC3339Nano))
+	delete(headers, HeaderRetryAt)
+
+	return Message{
+		Key:     m.Key,
+		Value:   m.Value,
+		Headers: headers,
+	}
+}
@@ -4,6 +4,10 @@ import "context"
 
 type MessageHandler func(ctx context.Context, msg Message) error
 
+// BatchHandler - обработчик пачки сообщений одной партиции. Если часть сообщений не обработана,
+// возвращает *BatchError с их индексами, остальные считаются обработанными.
+type BatchHandler func(ctx context.Context, msgs []Message) error
+
 type Producer interface {
 	Send(ctx context.Context, msg Message) error
 	Close() error
@@ -0,0 +1,262 @@
+// Package memkafka - Kafka в памяти процесса для тестов и локального запуска: топики с партициями,
+// consumer группы с оффсетами и заголовки. Producer и Consumer реализуют kafka.Producer и kafka.Consumer,
+// Consumer принимает те же consumer.Middleware, что и consumer.New.
+package memkafka
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+// DefaultPartitions - сколько партиций у топика, созданного первой отправкой или подпиской.
+const DefaultPartitions = 1
+
+var (
+	ErrClosed           = errors.New("memkafka: closed")
+	ErrTopicExists      = errors.New("memkafka: topic already exists")
+	ErrInvalidPartition = errors.New("memkafka: invalid partition")
+)
+
+type Option func(*Broker)
+
+// WithPartitions - партиций у автоматически созданных топиков.
+func WithPartitions(n int32) Option {
+	return func(b *Broker) {
+		if n > 0 {
+			b.partitions = n
+		}
+	}
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+// Broker - хранит все сообщения, пока жив. Безопасен для конкурентного использования.
+type Broker struct {
+	mu         sync.Mutex
+	partitions int32
+	topics     map[string][][]kafka.Message
+	// offsets - следующий оффсет к чтению по группе, как закоммиченный оффсет Kafka
+	offsets map[string]map[topicPartition]int64
+	// owners - какой consumer группы читает партицию, одна партиция - один consumer
+	owners map[string]map[topicPartition]*Consumer
+	// appended закрывается и заменяется при каждой записи, его ждут consumer без новых сообщений
+	appended chan struct{}
+}
+
+func NewBroker(opts ...Option) *Broker {
+	b := &Broker{
+		partitions: DefaultPartitions,
+		topics:     make(map[string][][]kafka.Message),
+		offsets:    make(map[string]map[topicPartition]int64),
+		owners:     make(map[string]map[topicPartition]*Consumer),
+		appended:   make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// CreateTopic - топик с заданным числом партиций, до первой отправки в него.
+func (b *Broker) CreateTopic(topic string, partitions int32) error {
+	if partitions <= 0 {
+		return ErrInvalidPartition
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.topics[topic]; ok {
+		return ErrTopicExists
+	}
+	b.topics[topic] = make([][]kafka.Message, partitions)
+
+	return nil
+}
+
+// Partitions - число партиций топика, 0 если топика нет.
+func (b *Broker) Partitions(topic string) int32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return int32(len(b.topics[topic])) //nolint:gosec
+}
+
+// Messages - все сообщения топика: по партициям, внутри партиции по оффсету.
+func (b *Broker) Messages(topic string) []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var msgs []kafka.Message
+	for _, log := range b.topics[topic] {
+		for _, msg := range log {
+			msgs = append(msgs, clone(msg))
+		}
+	}
+
+	return msgs
+}
+
+// Offset - следующий оффсет, который прочитает группа, 0 если группа ещё ничего не коммитила.
+func (b *Broker) Offset(group, topic string, partition int32) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.offsets[group][topicPartition{topic, partition}]
+}
+
+// Lag - сколько сообщений топика группа ещё не закоммитила.
+func (b *Broker) Lag(group, topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var lag int64
+	for p, log := range b.topics[topic] {
+		lag += int64(len(log)) - b.offsets[group][topicPartition{topic, int32(p)}] //nolint:gosec
+	}
+
+	return lag
+}
+
+// append - записывает сообщение в партицию и возвращает его с координатами.
+func (b *Broker) append(msg kafka.Message, partition func(n int32) (int32, error)) (kafka.Message, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	logs := b.topic(msg.Topic)
+
+	p, err := partition(int32(len(logs))) //nolint:gosec
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	if p < 0 || int(p) >= len(logs) {
+		return kafka.Message{}, ErrInvalidPartition
+	}
+
+	msg = clone(msg)
+	msg.Partition = p
+	msg.Offset = int64(len(logs[p]))
+	msg.Metadata = nil
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	logs[p] = append(logs[p], msg)
+
+	close(b.appended)
+	b.appended = make(chan struct{})
+
+	return msg, nil
+}
+
+// topic - партиции топика, создаёт его при первом обращении. Вызывается под mu.
+func (b *Broker) topic(name string) [][]kafka.Message {
+	logs, ok := b.topics[name]
+	if !ok {
+		logs = make([][]kafka.Message, b.partitions)
+		b.topics[name] = logs
+	}
+
+	return logs
+}
+
+// claim - свободные партиции топиков группы переходят к c, возвращаются все партиции c.
+func (b *Broker) claim(c *Consumer) []topicPartition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	owners, ok := b.owners[c.group]
+	if !ok {
+		owners = make(map[topicPartition]*Consumer)
+		b.owners[c.group] = owners
+	}
+
+	var claimed []topicPartition
+	for _, topic := range c.topics {
+		for p := range b.topic(topic) {
+			tp := topicPartition{topic, int32(p)} //nolint:gosec
+			if owner, ok := owners[tp]; !ok || owner == c {
+				owners[tp] = c
+				claimed = append(claimed, tp)
+			}
+		}
+	}
+
+	return claimed
+}
+
+// release - партиции c снова свободны для группы.
+func (b *Broker) release(c *Consumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for tp, owner := range b.owners[c.group] {
+		if owner == c {
+			delete(b.owners[c.group], tp)
+		}
+	}
+}
+
+// fetch - сообщение партиции с оффсетом offset, false если его ещё нет.
+func (b *Broker) fetch(tp topicPartition, offset int64) (kafka.Message, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	logs := b.topics[tp.topic]
+	if int(tp.partition) >= len(logs) || offset >= int64(len(logs[tp.partition])) {
+		return kafka.Message{}, false
+	}
+
+	return clone(logs[tp.partition][offset]), true
+}
+
+// committed - следующий оффсет к чтению группой.
+func (b *Broker) committed(group string, tp topicPartition) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.offsets[group][tp]
+}
+
+func (b *Broker) commit(group string, tp topicPartition, next int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	offsets, ok := b.offsets[group]
+	if !ok {
+		offsets = make(map[topicPartition]int64)
+		b.offsets[group] = offsets
+	}
+	offsets[tp] = max(offsets[tp], next)
+}
+
+// changed - канал, который закроется при следующей записи в любой топик.
+func (b *Broker) changed() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.appended
+}
+
+// clone - сообщение без общих с хранилищем слайсов и мап: обработчик и тест могут его менять.
+func clone(msg kafka.Message) kafka.Message {
+	msg.Key = slices.Clone(msg.Key)
+	msg.Value = slices.Clone(msg.Value)
+	if msg.Headers != nil {
+		msg.Headers = maps.Clone(msg.Headers)
+		for k, v := range msg.Headers {
+			msg.Headers[k] = slices.Clone(v)
+		}
+	}
+
+	return msg
+}
@@ -0,0 +1,144 @@
+package memkafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
+)
+
+// Consumer - член consumer группы. Партиции делятся между членами группы без ребалансировки:
+// свободную партицию забирает первый, кто до неё дошёл, и держит до выхода из Consume.
+// Ошибки обработчика разбираются как в consumer.Consumer: nil и permanent коммитят сообщение,
+// retryable оставляет его непомеченным и идёт дальше, fatal останавливает Consume.
+type Consumer struct {
+	broker      *Broker
+	group       string
+	topics      []string
+	middlewares []consumer.Middleware
+
+	// position - следующий оффсет к чтению в текущем Consume, коммит может отставать
+	position map[topicPartition]int64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewConsumer - порядок middleware как у consumer.New.
+func NewConsumer(broker *Broker, group string, topics []string, middlewares ...consumer.Middleware) *Consumer {
+	return &Consumer{
+		broker:      broker,
+		group:       group,
+		topics:      topics,
+		middlewares: middlewares,
+		done:        make(chan struct{}),
+	}
+}
+
+// Consume - читает, пока не отменён ctx или не вызван Close. Чтение начинается с закоммиченных оффсетов
+// группы, без коммитов - с начала партиции (OffsetOldest).
+func (c *Consumer) Consume(ctx context.Context, handler kafka.MessageHandler) error {
+	handler = c.chain(handler)
+	defer c.broker.release(c)
+
+	c.position = make(map[topicPartition]int64)
+
+	for {
+		changed := c.broker.changed()
+
+		n, err := c.poll(ctx, handler)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+
+		select {
+		case <-changed:
+		case <-c.done:
+			return nil
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// Drain - обрабатывает все уже записанные сообщения и возвращает их количество, не дожидаясь новых.
+// Для тестов: после Drain сообщения, отправленные до вызова, гарантированно обработаны.
+func (c *Consumer) Drain(ctx context.Context, handler kafka.MessageHandler) (int, error) {
+	handler = c.chain(handler)
+	defer c.broker.release(c)
+
+	c.position = make(map[topicPartition]int64)
+
+	total := 0
+	for {
+		n, err := c.poll(ctx, handler)
+		total += n
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+}
+
+func (c *Consumer) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
+
+func (c *Consumer) chain(handler kafka.MessageHandler) kafka.MessageHandler {
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// poll - один проход по партициям consumer, возвращает сколько сообщений прочитано.
+func (c *Consumer) poll(ctx context.Context, handler kafka.MessageHandler) (int, error) {
+	n := 0
+
+	for _, tp := range c.broker.claim(c) {
+		offset, ok := c.position[tp]
+		if !ok {
+			offset = c.broker.committed(c.group, tp)
+		}
+
+		for {
+			select {
+			case <-c.done:
+				return n, nil
+			case <-ctx.Done():
+				return n, context.Cause(ctx)
+			default:
+			}
+
+			msg, ok := c.broker.fetch(tp, offset)
+			if !ok {
+				break
+			}
+
+			n++
+			offset++
+			c.position[tp] = offset
+
+			if err := c.handle(ctx, msg, handler(ctx, msg)); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// handle - коммит по ошибке обработчика, решение о пометке как у consumer.Consumer.
+func (c *Consumer) handle(ctx context.Context, msg kafka.Message, err error) error {
+	done, err := consumer.Resolve(ctx, msg, err)
+	if done {
+		c.broker.commit(c.group, topicPartition{msg.Topic, msg.Partition}, msg.Offset+1)
+	}
+
+	return err
+}
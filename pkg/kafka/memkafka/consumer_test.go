@@ -0,0 +1,150 @@
+package memkafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
+)
+
+func send(t *testing.T, p *Producer, values ...string) {
+	t.Helper()
+
+	for _, v := range values {
+		if err := p.Send(context.Background(), kafka.Message{Key: []byte(v), Value: []byte(v)}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+}
+
+func TestConsumer_GroupsAndOffsets(t *testing.T) {
+	b := NewBroker(WithPartitions(2))
+	send(t, NewProducer(b, "events"), "a", "b", "c")
+
+	var got []string
+	handler := func(_ context.Context, msg kafka.Message) error {
+		got = append(got, string(msg.Value))
+		return nil
+	}
+
+	n, err := NewConsumer(b, "g1", []string{"events"}).Drain(context.Background(), handler)
+	if err != nil || n != 3 {
+		t.Fatalf("Drain = %d, %v, want 3", n, err)
+	}
+	if b.Lag("g1", "events") != 0 {
+		t.Errorf("lag = %d, want 0", b.Lag("g1", "events"))
+	}
+
+	// новый член группы продолжает с закоммиченных оффсетов
+	send(t, NewProducer(b, "events"), "d")
+	if n, _ := NewConsumer(b, "g1", []string{"events"}).Drain(context.Background(), handler); n != 1 {
+		t.Errorf("second Drain = %d, want 1", n)
+	}
+
+	// другая группа читает топик с начала
+	if n, _ := NewConsumer(b, "g2", []string{"events"}).Drain(context.Background(), handler); n != 4 {
+		t.Errorf("other group Drain = %d, want 4", n)
+	}
+	if len(got) != 8 {
+		t.Errorf("handled = %v, want 8 messages", got)
+	}
+}
+
+func TestConsumer_ErrorClasses(t *testing.T) {
+	b := NewBroker()
+	send(t, NewProducer(b, "events"), "retryable", "permanent", "ok", "fatal", "after")
+
+	errFatal := kafka.Fatal(errors.New("db is gone"))
+	handler := func(_ context.Context, msg kafka.Message) error {
+		switch string(msg.Value) {
+		case "retryable":
+			return errors.New("timeout")
+		case "permanent":
+			return kafka.Permanent(errors.New("bad payload"))
+		case "fatal":
+			return errFatal
+		}
+		return nil
+	}
+
+	c := NewConsumer(b, "g", []string{"events"})
+	n, err := c.Drain(context.Background(), handler)
+	if !errors.Is(err, errFatal) || n != 4 {
+		t.Fatalf("Drain = %d, %v, want 4, fatal error", n, err)
+	}
+
+	// retryable пропущена следующими коммитами, fatal не закоммичено
+	if off := b.Offset("g", "events", 0); off != 3 {
+		t.Errorf("committed offset = %d, want 3", off)
+	}
+}
+
+func TestConsumer_MiddlewareChain(t *testing.T) {
+	b := NewBroker()
+	dlq := NewProducer(b, "")
+	send(t, NewProducer(b, "events"), "boom")
+
+	c := NewConsumer(b, "g", []string{"events"}, consumer.WithDLQ(dlq), consumer.Recovery)
+	_, err := c.Drain(context.Background(), func(context.Context, kafka.Message) error {
+		panic("handler bug")
+	})
+	if err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+
+	dead := b.Messages(kafka.DLQTopic("events"))
+	if len(dead) != 1 {
+		t.Fatalf("dlq messages = %d, want 1", len(dead))
+	}
+	if got := string(dead[0].Headers[kafka.HeaderOriginalTopic]); got != "events" {
+		t.Errorf("original topic = %q, want events", got)
+	}
+	if b.Lag("g", "events") != 0 {
+		t.Errorf("lag = %d, want 0", b.Lag("g", "events"))
+	}
+}
+
+func TestConsumer_ConsumeUntilClose(t *testing.T) {
+	b := NewBroker()
+	p := NewProducer(b, "events")
+	c := NewConsumer(b, "g", []string{"events"})
+
+	var wg sync.WaitGroup
+	received := make(chan string, 2)
+	consumeErr := make(chan error, 1)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumeErr <- c.Consume(context.Background(), func(_ context.Context, msg kafka.Message) error {
+			received <- string(msg.Value)
+			return nil
+		})
+	}()
+
+	// сообщения после старта Consume будят ожидающий consumer
+	send(t, p, "a", "b")
+	for _, want := range []string{"a", "b"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Errorf("received %q, want %q", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("message %q not consumed", want)
+		}
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	wg.Wait()
+
+	if err := <-consumeErr; err != nil {
+		t.Errorf("Consume after Close = %v, want nil", err)
+	}
+}
@@ -0,0 +1,107 @@
+package memkafka
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+)
+
+type ProducerOption func(*Producer)
+
+// WithPartitioner - своя стратегия выбора партиции вместо хэша ключа, как producer.WithPartitioner.
+func WithPartitioner(p producer.Partitioner) ProducerOption {
+	return func(pr *Producer) { pr.partitioner = p }
+}
+
+// WithManualPartitioner - партицию выбирает отправитель через kafka.Message.Partition.
+func WithManualPartitioner() ProducerOption {
+	return WithPartitioner(producer.PartitionerFunc(func(msg kafka.Message, _ int32) (int32, error) {
+		return msg.Partition, nil
+	}))
+}
+
+// Producer - запись сразу видна в Broker, Send возвращает управление после записи.
+// По умолчанию партиция выбирается как у producer.WithHashPartitioner.
+type Producer struct {
+	broker      *Broker
+	topic       string
+	partitioner producer.Partitioner
+
+	mu     sync.Mutex
+	next   int32
+	closed bool
+}
+
+func NewProducer(broker *Broker, topic string, opts ...ProducerOption) *Producer {
+	p := &Producer{broker: broker, topic: topic}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Send - топик сообщения, если задан, важнее топика продюсера, как у producer.SyncProducer.
+func (p *Producer) Send(ctx context.Context, msg kafka.Message) error {
+	_, err := p.send(ctx, msg)
+	return err
+}
+
+// SendAsync - как producer.AsyncProducer.SendAsync, но результат готов сразу.
+func (p *Producer) SendAsync(ctx context.Context, msg kafka.Message) (<-chan producer.Result, error) {
+	delivered, err := p.send(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// как у AsyncProducer: Metadata отправителя возвращается в результате
+	delivered.Metadata = msg.Metadata
+
+	result := make(chan producer.Result, 1)
+	result <- producer.Result{Message: delivered}
+
+	return result, nil
+}
+
+func (p *Producer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	return nil
+}
+
+func (p *Producer) send(ctx context.Context, msg kafka.Message) (kafka.Message, error) {
+	if err := ctx.Err(); err != nil {
+		return kafka.Message{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return kafka.Message{}, ErrClosed
+	}
+
+	if msg.Topic == "" {
+		msg.Topic = p.topic
+	}
+
+	return p.broker.append(msg, func(n int32) (int32, error) {
+		switch {
+		case p.partitioner != nil:
+			return p.partitioner.Partition(msg, n)
+		case msg.Key != nil:
+			return producer.HashPartition(msg.Key, n), nil
+		default:
+			// без ключа - по кругу, как sarama.NewRoundRobinPartitioner
+			partition := p.next % n
+			p.next++
+			return partition, nil
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package memkafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+)
+
+func TestProducer_HashByKey(t *testing.T) {
+	b := NewBroker()
+	if err := b.CreateTopic("users", 4); err != nil {
+		t.Fatalf("CreateTopic: %v", err)
+	}
+
+	p := NewProducer(b, "users")
+	for _, v := range []string{"1", "2", "3"} {
+		msg := kafka.Message{Key: []byte("user-1"), Value: []byte(v), Headers: map[string][]byte{"h": []byte(v)}}
+		if err := p.Send(context.Background(), msg); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	msgs := b.Messages("users")
+	if len(msgs) != 3 {
+		t.Fatalf("messages = %d, want 3", len(msgs))
+	}
+
+	want := producer.HashPartition([]byte("user-1"), 4)
+	for i, msg := range msgs {
+		if msg.Topic != "users" || msg.Partition != want || msg.Offset != int64(i) {
+			t.Errorf("message %d at %s/%d/%d, want users/%d/%d", i, msg.Topic, msg.Partition, msg.Offset, want, i)
+		}
+		if string(msg.Value) != string(msg.Headers["h"]) || msg.Timestamp.IsZero() {
+			t.Errorf("message %d = %+v", i, msg)
+		}
+	}
+}
+
+func TestProducer_RoundRobinWithoutKey(t *testing.T) {
+	b := NewBroker(WithPartitions(3))
+	p := NewProducer(b, "events")
+
+	for range 6 {
+		if err := p.Send(context.Background(), kafka.Message{Value: []byte("v")}); err != nil {
+			t.Fatalf("Send: %v", err)
+		}
+	}
+
+	perPartition := make(map[int32]int)
+	for _, msg := range b.Messages("events") {
+		perPartition[msg.Partition]++
+	}
+	for p := range int32(3) {
+		if perPartition[p] != 2 {
+			t.Errorf("partition %d has %d messages, want 2", p, perPartition[p])
+		}
+	}
+}
+
+func TestProducer_ManualPartition(t *testing.T) {
+	b := NewBroker(WithPartitions(2))
+	p := NewProducer(b, "events", WithManualPartitioner())
+
+	if err := p.Send(context.Background(), kafka.Message{Topic: "other", Partition: 1}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if msgs := b.Messages("other"); len(msgs) != 1 || msgs[0].Partition != 1 {
+		t.Errorf("messages = %+v, want one in partition 1", msgs)
+	}
+
+	if err := p.Send(context.Background(), kafka.Message{Partition: 2}); !errors.Is(err, ErrInvalidPartition) {
+		t.Errorf("Send to missing partition = %v, want ErrInvalidPartition", err)
+	}
+}
+
+func TestProducer_SendAsyncAndClose(t *testing.T) {
+	b := NewBroker()
+	p := NewProducer(b, "events")
+
+	result, err := p.SendAsync(context.Background(), kafka.Message{Value: []byte("v"), Metadata: "event-1"})
+	if err != nil {
+		t.Fatalf("SendAsync: %v", err)
+	}
+	if r := <-result; r.Err != nil || r.Message.Metadata != "event-1" || r.Message.Topic != "events" {
+		t.Errorf("result = %+v", r)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := p.Send(context.Background(), kafka.Message{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Send after Close = %v, want ErrClosed", err)
+	}
+}
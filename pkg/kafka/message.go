@@ -8,6 +8,8 @@ type Message struct {
 	Headers  map[string][]byte
 	Metadata any
 
+	// Topic - у прочитанного сообщения топик, из которого оно пришло; при отправке, если задан,
+	// переопределяет топик продюсера.
 	Topic     string
 	Partition int32
 	Offset    int64
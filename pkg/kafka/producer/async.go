@@ -2,30 +2,94 @@ package producer
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/IBM/sarama"
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
 )
 
-// OnSuccessFunc - способ узнать что конкретное сообщение доставлено и выполнить
-// действие, например для передачи eventID в outbox
-type OnSuccessFunc func(metadata any)
+// DefaultMaxInFlight - сколько сообщений может ждать подтверждения брокера, дальше Send блокируется.
+const DefaultMaxInFlight = 1000
 
+var ErrProducerClosed = errors.New("kafka: async producer closed")
+
+// OnSuccessFunc - сообщение доставлено: у msg заполнены Topic, Partition, Offset и исходный Metadata,
+// например eventID для outbox.
+type OnSuccessFunc func(msg kafka.Message)
+
+// OnErrorFunc - сообщение не доставлено после всех повторов sarama.
+type OnErrorFunc func(msg kafka.Message, err error)
+
+// Result - итог доставки одного сообщения из SendAsync.
+type Result struct {
+	Message kafka.Message
+	Err     error
+}
+
+type AsyncOption func(*AsyncProducer)
+
+func WithOnSuccess(fn OnSuccessFunc) AsyncOption {
+	return func(p *AsyncProducer) { p.onSuccess = fn }
+}
+
+func WithOnError(fn OnErrorFunc) AsyncOption {
+	return func(p *AsyncProducer) { p.onError = fn }
+}
+
+// WithMaxInFlight - ограничение неподтверждённых сообщений: при заполнении Send и SendAsync ждут
+// подтверждений (backpressure) или отмены контекста.
+func WithMaxInFlight(n int) AsyncOption {
+	return func(p *AsyncProducer) {
+		if n > 0 {
+			p.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// pending - едет в Metadata сообщения sarama от Input до Successes/Errors.
+type pending struct {
+	msg    kafka.Message
+	result chan Result
+}
+
+// AsyncProducer - обёртка над sarama.AsyncProducer, которой нужны Producer.Return.Successes и Errors
+// (NewAsyncConfig включает оба), иначе подтверждения не придут.
 type AsyncProducer struct {
 	producer  sarama.AsyncProducer
 	topic     string
 	onSuccess OnSuccessFunc
+	onError   OnErrorFunc
+	slots     chan struct{}
 	wg        sync.WaitGroup
+
+	// closeMu - отправка в Input под RLock, чтобы Close не закрыл продюсер посреди неё
+	closeMu sync.RWMutex
+	closed  bool
+
+	mu       sync.Mutex
+	inflight int
+	// idle закрыт, когда неподтверждённых сообщений нет
+	idle chan struct{}
 }
 
-func NewAsync(producer sarama.AsyncProducer, topic string, onSuccess OnSuccessFunc) *AsyncProducer {
+func NewAsync(producer sarama.AsyncProducer, topic string, opts ...AsyncOption) *AsyncProducer {
+	idle := make(chan struct{})
+	close(idle)
+
 	p := &AsyncProducer{
-		producer:  producer,
-		topic:     topic,
-		onSuccess: onSuccess,
+		producer: producer,
+		topic:    topic,
+		slots:    make(chan struct{}, DefaultMaxInFlight),
+		idle:     idle,
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
 
 	p.wg.Add(2)
@@ -35,29 +99,109 @@ func NewAsync(producer sarama.AsyncProducer, topic string, onSuccess OnSuccessFu
 	return p
 }
 
+// Send - отправка без ожидания подтверждения, результат получают OnSuccessFunc и OnErrorFunc.
 func (p *AsyncProducer) Send(ctx context.Context, msg kafka.Message) error {
+	_, err := p.SendAsync(ctx, msg)
+	return err
+}
+
+// SendAsync ставит сообщение в очередь и возвращает канал, в который придёт ровно один Result.
+// Ошибка возвращается, только если сообщение не поставлено: отменён ctx или продюсер закрыт.
+func (p *AsyncProducer) SendAsync(ctx context.Context, msg kafka.Message) (<-chan Result, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+
+	if p.closed {
+		<-p.slots
+		return nil, ErrProducerClosed
+	}
+
+	p.begin()
+
+	pm := toSaramaMsg(p.topic, msg)
+	pend := &pending{msg: msg, result: make(chan Result, 1)}
+	pm.Metadata = pend
+
+	select {
+	case p.producer.Input() <- pm:
+		return pend.result, nil
+	case <-ctx.Done():
+		p.end()
+		return nil, ctx.Err()
+	}
+}
+
+// Flush ждёт подтверждения всех отправленных сообщений. Новые Send во время Flush продлевают ожидание.
+func (p *AsyncProducer) Flush(ctx context.Context) error {
+	p.mu.Lock()
+	idle := p.idle
+	p.mu.Unlock()
+
 	select {
-	case p.producer.Input() <- toSaramaMsg(p.topic, msg):
+	case <-idle:
 		return nil
 	case <-ctx.Done():
-		return ctx.Err()
+		return fmt.Errorf("kafka async flush: %w", ctx.Err())
 	}
 }
 
+// Close - sarama досылает буфер, результаты всех поставленных сообщений приходят до возврата.
 func (p *AsyncProducer) Close() error {
+	p.closeMu.Lock()
+	p.closed = true
+	p.closeMu.Unlock()
+
 	p.producer.AsyncClose()
 	p.wg.Wait()
 
 	return nil
 }
 
+func (p *AsyncProducer) begin() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.inflight == 0 {
+		p.idle = make(chan struct{})
+	}
+	p.inflight++
+}
+
+func (p *AsyncProducer) end() {
+	p.mu.Lock()
+	p.inflight--
+	if p.inflight == 0 {
+		close(p.idle)
+	}
+	p.mu.Unlock()
+
+	<-p.slots
+}
+
 func (p *AsyncProducer) readSuccesses() {
 	defer p.wg.Done()
 
-	for msg := range p.producer.Successes() {
-		if p.onSuccess != nil && msg.Metadata != nil {
-			p.onSuccess(msg.Metadata)
+	for pm := range p.producer.Successes() {
+		pend, ok := pm.Metadata.(*pending)
+		if !ok {
+			continue
+		}
+
+		msg := delivered(pend.msg, pm)
+		metrics.RecordProducerMessage(context.Background(), pm.Topic)
+
+		if p.onSuccess != nil {
+			p.onSuccess(msg)
 		}
+
+		pend.result <- Result{Message: msg}
+		p.end()
 	}
 }
 
@@ -65,10 +209,35 @@ func (p *AsyncProducer) readErrors() {
 	defer p.wg.Done()
 
 	log := logger.Logger()
-	for err := range p.producer.Errors() {
+	for perr := range p.producer.Errors() {
 		log.Error().
-			Err(err.Err).
-			Str("topic", p.topic).
+			Err(perr.Err).
+			Str("topic", perr.Msg.Topic).
 			Msg("kafka: async send failed")
+
+		pend, ok := perr.Msg.Metadata.(*pending)
+		if !ok {
+			continue
+		}
+
+		msg := delivered(pend.msg, perr.Msg)
+		err := fmt.Errorf("kafka async send: %w", perr.Err)
+
+		if p.onError != nil {
+			p.onError(msg, err)
+		}
+
+		pend.result <- Result{Message: msg, Err: err}
+		p.end()
 	}
 }
+
+// delivered - исходное сообщение с координатами, которые назначил брокер.
+func delivered(msg kafka.Message, pm *sarama.ProducerMessage) kafka.Message {
+	msg.Topic = pm.Topic
+	msg.Partition = pm.Partition
+	msg.Offset = pm.Offset
+	msg.Timestamp = pm.Timestamp
+
+	return msg
+}
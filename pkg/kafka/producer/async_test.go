@@ -0,0 +1,181 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+// fakeAsync - sarama.AsyncProducer, подтверждения которого отправляет сам тест.
+type fakeAsync struct {
+	sarama.AsyncProducer
+
+	input     chan *sarama.ProducerMessage
+	successes chan *sarama.ProducerMessage
+	errors    chan *sarama.ProducerError
+	closeOnce sync.Once
+}
+
+func newFakeAsync() *fakeAsync {
+	return &fakeAsync{
+		input:     make(chan *sarama.ProducerMessage, 16),
+		successes: make(chan *sarama.ProducerMessage),
+		errors:    make(chan *sarama.ProducerError),
+	}
+}
+
+func (f *fakeAsync) Input() chan<- *sarama.ProducerMessage     { return f.input }
+func (f *fakeAsync) Successes() <-chan *sarama.ProducerMessage { return f.successes }
+func (f *fakeAsync) Errors() <-chan *sarama.ProducerError      { return f.errors }
+func (f *fakeAsync) AsyncClose() {
+	f.closeOnce.Do(func() {
+		close(f.successes)
+		close(f.errors)
+	})
+}
+
+func (f *fakeAsync) ack(offset int64) {
+	pm := <-f.input
+	pm.Offset = offset
+	f.successes <- pm
+}
+
+func (f *fakeAsync) fail(err error) {
+	pm := <-f.input
+	f.errors <- &sarama.ProducerError{Msg: pm, Err: err}
+}
+
+func waitResult(t *testing.T, ch <-chan Result) Result {
+	t.Helper()
+
+	select {
+	case r := <-ch:
+		return r
+	case <-time.After(time.Second):
+		t.Fatal("no delivery result")
+		return Result{}
+	}
+}
+
+func TestAsync_Results(t *testing.T) {
+	fake := newFakeAsync()
+
+	var mu sync.Mutex
+	var succeeded []any
+	var failed []any
+
+	p := NewAsync(fake, "topic",
+		WithOnSuccess(func(msg kafka.Message) {
+			mu.Lock()
+			defer mu.Unlock()
+			succeeded = append(succeeded, msg.Metadata)
+		}),
+		WithOnError(func(msg kafka.Message, _ error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, msg.Metadata)
+		}),
+	)
+	defer p.Close()
+
+	ok, err := p.SendAsync(context.Background(), kafka.Message{Value: []byte("1"), Metadata: "e1"})
+	if err != nil {
+		t.Fatalf("SendAsync: %v", err)
+	}
+	bad, err := p.SendAsync(context.Background(), kafka.Message{Value: []byte("2"), Metadata: "e2"})
+	if err != nil {
+		t.Fatalf("SendAsync: %v", err)
+	}
+
+	fake.ack(42)
+	fake.fail(sarama.ErrOutOfBrokers)
+
+	r := waitResult(t, ok)
+	if r.Err != nil || r.Message.Offset != 42 || r.Message.Topic != "topic" || r.Message.Metadata != "e1" {
+		t.Errorf("success result = %+v", r)
+	}
+
+	r = waitResult(t, bad)
+	if !errors.Is(r.Err, sarama.ErrOutOfBrokers) || r.Message.Metadata != "e2" {
+		t.Errorf("error result = %+v", r)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(succeeded) != 1 || succeeded[0] != "e1" {
+		t.Errorf("OnSuccess calls = %v, want [e1]", succeeded)
+	}
+	if len(failed) != 1 || failed[0] != "e2" {
+		t.Errorf("OnError calls = %v, want [e2]", failed)
+	}
+}
+
+func TestAsync_MaxInFlightBackpressure(t *testing.T) {
+	fake := newFakeAsync()
+	p := NewAsync(fake, "topic", WithMaxInFlight(1))
+	defer p.Close()
+
+	first, err := p.SendAsync(context.Background(), kafka.Message{Value: []byte("1")})
+	if err != nil {
+		t.Fatalf("SendAsync: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.SendAsync(ctx, kafka.Message{Value: []byte("2")}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SendAsync over limit = %v, want DeadlineExceeded", err)
+	}
+
+	fake.ack(0)
+	waitResult(t, first)
+
+	second, err := p.SendAsync(context.Background(), kafka.Message{Value: []byte("2")})
+	if err != nil {
+		t.Fatalf("SendAsync after ack: %v", err)
+	}
+	fake.ack(1)
+	waitResult(t, second)
+}
+
+func TestAsync_Flush(t *testing.T) {
+	fake := newFakeAsync()
+	p := NewAsync(fake, "topic")
+	defer p.Close()
+
+	if err := p.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush without messages: %v", err)
+	}
+
+	if err := p.Send(context.Background(), kafka.Message{Value: []byte("1")}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Flush with unacked message = %v, want DeadlineExceeded", err)
+	}
+
+	go fake.ack(0)
+
+	if err := p.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+}
+
+func TestAsync_SendAfterClose(t *testing.T) {
+	p := NewAsync(newFakeAsync(), "topic")
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := p.SendAsync(context.Background(), kafka.Message{}); !errors.Is(err, ErrProducerClosed) {
+		t.Errorf("SendAsync after Close = %v, want ErrProducerClosed", err)
+	}
+}
@@ -1,6 +1,8 @@
 package producer
 
 import (
+	"time"
+
 	"github.com/IBM/sarama"
 )
 
@@ -16,27 +18,72 @@ func WithIdempotent() Option {
 	}
 }
 
-//// WithFlush - нужен ли батч на отправку (например каждые 100 сообщений или каждые 0.5 сек)
-// func WithFlush(messages int, frequency time.Duration) Option {
-//	return func(c *sarama.Config) {
-//		c.Producer.Flush.Messages = messages
-//		c.Producer.Flush.Frequency = frequency
-//	}
-//}
-
-//// WithRetry - нужно ли ретраить сообщения которые упали с ошибкой
-// func WithRetry(max int) Option {
-//	return func(c *sarama.Config) {
-//		c.Producer.Retry.Max = max
-//	}
-//}
+// WithFlush - копить сообщения в батч: отправка, когда набралось messages сообщений или прошло frequency
+// (например каждые 100 сообщений или каждые 0.5 сек). Нулевое значение отключает условие.
+func WithFlush(messages int, frequency time.Duration) Option {
+	return func(c *sarama.Config) {
+		c.Producer.Flush.Messages = messages
+		c.Producer.Flush.Frequency = frequency
+	}
+}
+
+// WithRetry - сколько раз sarama повторяет отправку сообщения, упавшего с retryable ошибкой,
+// и пауза между попытками.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *sarama.Config) {
+		c.Producer.Retry.Max = maxRetries
+		c.Producer.Retry.Backoff = backoff
+	}
+}
+
+// WithCompression - сжатие батчей, например sarama.CompressionZSTD или sarama.CompressionSnappy.
+// ZSTD требует версию протокола не ниже 2.1 (по умолчанию у sarama как раз 2.1).
+func WithCompression(codec sarama.CompressionCodec) Option {
+	return func(c *sarama.Config) {
+		c.Producer.Compression = codec
+	}
+}
+
+// WithMaxMessageBytes - максимальный размер сообщения, больше - ошибка sarama.ErrMessageSizeTooLarge
+// без отправки. Не должен превышать message.max.bytes брокера (топика), иначе ошибку вернёт брокер.
+func WithMaxMessageBytes(n int) Option {
+	return func(c *sarama.Config) {
+		c.Producer.MaxMessageBytes = n
+	}
+}
+
+// WithHashPartitioner - партиция по murmur2 хэшу ключа, как у Java клиента: сообщения с одним ключом
+// (например user_id) попадают в одну партицию и читаются по порядку. Без ключа - по кругу.
+// Стратегия по умолчанию у NewSyncConfig и NewAsyncConfig, опция оставлена для явности.
+func WithHashPartitioner() Option {
+	return func(c *sarama.Config) {
+		c.Producer.Partitioner = newMurmur2Partitioner
+	}
+}
+
+// WithManualPartitioner - партицию выбирает отправитель через kafka.Message.Partition.
+func WithManualPartitioner() Option {
+	return func(c *sarama.Config) {
+		c.Producer.Partitioner = sarama.NewManualPartitioner
+	}
+}
+
+// WithPartitioner - своя стратегия выбора партиции, один экземпляр на все топики.
+func WithPartitioner(p Partitioner) Option {
+	return func(c *sarama.Config) {
+		c.Producer.Partitioner = func(string) sarama.Partitioner {
+			return customPartitioner{partitioner: p}
+		}
+	}
+}
 
+// NewSyncConfig - конфиг для NewSync. Партиция по умолчанию - murmur2 хэш ключа, как у WithHashPartitioner.
 func NewSyncConfig(opts ...Option) *sarama.Config {
 	config := sarama.NewConfig()
 	config.Producer.RequiredAcks = sarama.WaitForAll
 	config.Producer.Retry.Max = 5
 	config.Producer.Return.Successes = true
-	config.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	config.Producer.Partitioner = newMurmur2Partitioner
 
 	for _, opt := range opts {
 		opt(config)
@@ -45,12 +92,14 @@ func NewSyncConfig(opts ...Option) *sarama.Config {
 	return config
 }
 
-// NewAsyncConfig - создаем конфиг с набором опций, например:
+// NewAsyncConfig - создаем конфиг с набором опций, партиция по умолчанию как у NewSyncConfig, например:
 //
 // cfg := producer.NewAsyncConfig(
 // producer.WithIdempotent(),
+// producer.WithHashPartitioner(),
 // producer.WithFlush(100, 500*time.Millisecond),
-// producer.WithRetry(3),
+// producer.WithRetry(3, 100*time.Millisecond),
+// producer.WithCompression(sarama.CompressionZSTD),
 // )
 func NewAsyncConfig(opts ...Option) *sarama.Config {
 	config := sarama.NewConfig()
@@ -58,7 +107,21 @@ func NewAsyncConfig(opts ...Option) *sarama.Config {
 	config.Producer.Retry.Max = 5
 	config.Producer.Return.Successes = true
 	config.Producer.Return.Errors = true
-	config.Producer.Partitioner = sarama.NewRoundRobinPartitioner
+	config.Producer.Partitioner = newMurmur2Partitioner
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	return config
+}
+
+// NewTransactionalConfig - конфиг для NewTransactional. transactionalID должен быть стабильным между рестартами
+// и уникальным для каждого экземпляра, например <сервис>-<hostname>: по нему брокер отбрасывает
+// незавершённые транзакции упавшего предшественника.
+func NewTransactionalConfig(transactionalID string, opts ...Option) *sarama.Config {
+	config := NewSyncConfig(WithIdempotent())
+	config.Producer.Transaction.ID = transactionalID
 
 	for _, opt := range opts {
 		opt(config)
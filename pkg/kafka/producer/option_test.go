@@ -0,0 +1,51 @@
+package producer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+func TestOptions(t *testing.T) {
+	cfg := NewAsyncConfig(
+		WithIdempotent(),
+		WithFlush(100, 500*time.Millisecond),
+		WithRetry(3, 250*time.Millisecond),
+		WithCompression(sarama.CompressionZSTD),
+		WithMaxMessageBytes(512*1024),
+	)
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	if cfg.Producer.Flush.Messages != 100 || cfg.Producer.Flush.Frequency != 500*time.Millisecond {
+		t.Errorf("flush = %d/%s, want 100/500ms", cfg.Producer.Flush.Messages, cfg.Producer.Flush.Frequency)
+	}
+	if cfg.Producer.Retry.Max != 3 || cfg.Producer.Retry.Backoff != 250*time.Millisecond {
+		t.Errorf("retry = %d/%s, want 3/250ms", cfg.Producer.Retry.Max, cfg.Producer.Retry.Backoff)
+	}
+	if cfg.Producer.Compression != sarama.CompressionZSTD {
+		t.Errorf("compression = %s, want zstd", cfg.Producer.Compression)
+	}
+	if cfg.Producer.MaxMessageBytes != 512*1024 {
+		t.Errorf("max message bytes = %d, want %d", cfg.Producer.MaxMessageBytes, 512*1024)
+	}
+}
+
+func TestOptions_Invalid(t *testing.T) {
+	tests := map[string]Option{
+		"negative retry":    WithRetry(-1, 0),
+		"zero message size": WithMaxMessageBytes(0),
+		"negative flush":    WithFlush(-1, 0),
+	}
+
+	for name, opt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := NewSyncConfig(opt).Validate(); err == nil {
+				t.Error("Validate: want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package producer
+
+import (
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+// Partitioner - своя стратегия выбора партиции. Результат вне [0, numPartitions) sarama вернёт
+// как ошибку отправки. Выбор считается постоянным: недоступная партиция не заменяется другой.
+type Partitioner interface {
+	Partition(msg kafka.Message, numPartitions int32) (int32, error)
+}
+
+// PartitionerFunc - Partitioner из функции.
+type PartitionerFunc func(msg kafka.Message, numPartitions int32) (int32, error)
+
+func (f PartitionerFunc) Partition(msg kafka.Message, numPartitions int32) (int32, error) {
+	return f(msg, numPartitions)
+}
+
+// customPartitioner - Partitioner в интерфейсе sarama.
+type customPartitioner struct {
+	partitioner Partitioner
+}
+
+func (p customPartitioner) Partition(pm *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	msg := kafka.Message{Topic: pm.Topic, Metadata: pm.Metadata}
+
+	var err error
+	if msg.Key, err = encoded(pm.Key); err != nil {
+		return 0, err
+	}
+	if msg.Value, err = encoded(pm.Value); err != nil {
+		return 0, err
+	}
+
+	if len(pm.Headers) > 0 {
+		msg.Headers = make(map[string][]byte, len(pm.Headers))
+		for _, h := range pm.Headers {
+			msg.Headers[string(h.Key)] = h.Value
+		}
+	}
+
+	return p.partitioner.Partition(msg, numPartitions)
+}
+
+func (customPartitioner) RequiresConsistency() bool { return true }
+
+// murmur2Partitioner - партиция по ключу как у Java клиента: сообщения с одним ключом попадают
+// в одну партицию у всех продюсеров, включая не Go. Сообщения без ключа распределяются по кругу.
+type murmur2Partitioner struct {
+	roundRobin sarama.Partitioner
+}
+
+func newMurmur2Partitioner(topic string) sarama.Partitioner {
+	return &murmur2Partitioner{roundRobin: sarama.NewRoundRobinPartitioner(topic)}
+}
+
+func (p *murmur2Partitioner) Partition(pm *sarama.ProducerMessage, numPartitions int32) (int32, error) {
+	if pm.Key == nil {
+		return p.roundRobin.Partition(pm, numPartitions)
+	}
+
+	key, err := pm.Key.Encode()
+	if err != nil {
+		return 0, err
+	}
+
+	return HashPartition(key, numPartitions), nil
+}
+
+func (p *murmur2Partitioner) RequiresConsistency() bool { return true }
+
+// MessageRequiresConsistency - сообщение без ключа можно отправить в любую доступную партицию.
+func (p *murmur2Partitioner) MessageRequiresConsistency(pm *sarama.ProducerMessage) bool {
+	return pm.Key != nil
+}
+
+// HashPartition - партиция ключа у WithHashPartitioner и Java клиента.
+func HashPartition(key []byte, numPartitions int32) int32 {
+	// toPositive из Java клиента: старший бит сбрасывается, а не берётся модуль
+	return int32(murmur2(key)&0x7fffffff) % numPartitions //nolint:gosec
+}
+
+// murmur2 - порт org.apache.kafka.common.utils.Utils.murmur2.
+func murmur2(data []byte) uint32 {
+	const (
+		seed = 0x9747b28c
+		m    = 0x5bd1e995
+		r    = 24
+	)
+
+	length := len(data)
+	h := uint32(seed) ^ uint32(length) //nolint:gosec
+
+	for i := 0; i+4 <= length; i += 4 {
+		k := uint32(data[i]) | uint32(data[i+1])<<8 | uint32(data[i+2])<<16 | uint32(data[i+3])<<24
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+
+	return h
+}
+
+func encoded(e sarama.Encoder) ([]byte, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	return e.Encode()
+}
@@ -0,0 +1,144 @@
+package producer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+)
+
+// значения из UtilsTest.testMurmur2 Java клиента
+func TestMurmur2_JavaCompatible(t *testing.T) {
+	tests := []struct {
+		key  string
+		want int32
+	}{
+		{"21", -973932308},
+		{"foobar", -790332482},
+		{"a-little-bit-long-string", -985981536},
+		{"a-little-bit-longer-string", -1486304829},
+		{"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8", -58897971},
+		{"abc", 479470107},
+	}
+
+	for _, tt := range tests {
+		if got := int32(murmur2([]byte(tt.key))); got != tt.want { //nolint:gosec
+			t.Errorf("murmur2(%q) = %d, want %d", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestHashPartitioner_SameKeySamePartition(t *testing.T) {
+	p := newMurmur2Partitioner("topic")
+
+	first, err := p.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder("user-1")}, 12)
+	if err != nil {
+		t.Fatalf("Partition: %v", err)
+	}
+
+	for range 10 {
+		got, err := p.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder("user-1")}, 12)
+		if err != nil {
+			t.Fatalf("Partition: %v", err)
+		}
+		if got != first {
+			t.Fatalf("partition = %d, want %d", got, first)
+		}
+	}
+
+	// Java: Utils.toPositive(Utils.murmur2("21".getBytes())) % 7
+	got, err := p.Partition(&sarama.ProducerMessage{Key: sarama.StringEncoder("21")}, 7)
+	if err != nil {
+		t.Fatalf("Partition: %v", err)
+	}
+	if got != 3 {
+		t.Errorf("partition of \"21\" = %d, want 3", got)
+	}
+}
+
+func TestHashPartitioner_NilKeyRoundRobin(t *testing.T) {
+	p := newMurmur2Partitioner("topic")
+
+	seen := make(map[int32]bool)
+	for range 3 {
+		got, err := p.Partition(&sarama.ProducerMessage{}, 3)
+		if err != nil {
+			t.Fatalf("Partition: %v", err)
+		}
+		seen[got] = true
+	}
+
+	if len(seen) != 3 {
+		t.Errorf("partitions for nil keys = %v, want all 3", seen)
+	}
+
+	dp, ok := p.(sarama.DynamicConsistencyPartitioner)
+	if !ok || dp.MessageRequiresConsistency(&sarama.ProducerMessage{}) {
+		t.Error("message without key must not require consistency")
+	}
+}
+
+func TestDefaultPartitioner_HashesKey(t *testing.T) {
+	for name, cfg := range map[string]*sarama.Config{"sync": NewSyncConfig(), "async": NewAsyncConfig()} {
+		t.Run(name, func(t *testing.T) {
+			p := cfg.Producer.Partitioner("topic")
+			key := []byte("019c0000-0000-7000-8000-000000000001")
+
+			got, err := p.Partition(&sarama.ProducerMessage{Key: sarama.ByteEncoder(key)}, 12)
+			if err != nil {
+				t.Fatalf("partition: %v", err)
+			}
+			if want := HashPartition(key, 12); got != want {
+				t.Errorf("partition = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestManualPartitioner_UsesMessagePartition(t *testing.T) {
+	cfg := NewSyncConfig(WithManualPartitioner())
+	p := cfg.Producer.Partitioner("topic")
+
+	got, err := p.Partition(toSaramaMsg("topic", kafka.Message{Partition: 2}), 4)
+	if err != nil {
+		t.Fatalf("Partition: %v", err)
+	}
+	if got != 2 {
+		t.Errorf("partition = %d, want 2", got)
+	}
+}
+
+func TestCustomPartitioner(t *testing.T) {
+	errNoTenant := errors.New("no tenant header")
+
+	cfg := NewAsyncConfig(WithPartitioner(PartitionerFunc(func(msg kafka.Message, n int32) (int32, error) {
+		tenant, ok := msg.Headers["tenant"]
+		if !ok {
+			return 0, errNoTenant
+		}
+		if string(msg.Key) != "k" {
+			t.Errorf("key = %q, want k", msg.Key)
+		}
+		return int32(len(tenant)) % n, nil //nolint:gosec
+	})))
+	p := cfg.Producer.Partitioner("topic")
+
+	if !p.RequiresConsistency() {
+		t.Error("custom partitioner must require consistency")
+	}
+
+	msg := kafka.Message{Key: []byte("k"), Headers: map[string][]byte{"tenant": []byte("acme")}}
+	got, err := p.Partition(toSaramaMsg("topic", msg), 3)
+	if err != nil {
+		t.Fatalf("Partition: %v", err)
+	}
+	if got != 1 {
+		t.Errorf("partition = %d, want 1", got)
+	}
+
+	if _, err := p.Partition(toSaramaMsg("topic", kafka.Message{}), 3); !errors.Is(err, errNoTenant) {
+		t.Errorf("err = %v, want errNoTenant", err)
+	}
+}
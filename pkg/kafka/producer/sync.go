@@ -24,18 +24,19 @@ func NewSync(producer sarama.SyncProducer, topic string) *SyncProducer {
 }
 
 func (p *SyncProducer) Send(ctx context.Context, msg kafka.Message) error {
-	_, _, err := p.producer.SendMessage(toSaramaMsg(p.topic, msg))
+	pm := toSaramaMsg(p.topic, msg)
+	_, _, err := p.producer.SendMessage(pm)
 	if err != nil {
 		log := logger.Ctx(ctx)
 		log.Error().
 			Err(err).
-			Str("topic", p.topic).
+			Str("topic", pm.Topic).
 			Msg("kafka: send failed")
 
 		return fmt.Errorf("kafka sync send: %w", err)
 	}
 
-	metrics.RecordProducerMessage(ctx, p.topic)
+	metrics.RecordProducerMessage(ctx, pm.Topic)
 
 	return nil
 }
@@ -44,11 +45,18 @@ func (p *SyncProducer) Close() error {
 	return p.producer.Close()
 }
 
+// toSaramaMsg - топик сообщения, если задан, важнее топика продюсера: так consumer перекладывает
+// сообщения в retry топики и DLQ тем же продюсером. Partition учитывается только WithManualPartitioner.
 func toSaramaMsg(topic string, msg kafka.Message) *sarama.ProducerMessage {
+	if msg.Topic != "" {
+		topic = msg.Topic
+	}
+
 	pm := &sarama.ProducerMessage{
-		Topic:    topic,
-		Value:    sarama.ByteEncoder(msg.Value),
-		Metadata: msg.Metadata,
+		Topic:     topic,
+		Value:     sarama.ByteEncoder(msg.Value),
+		Partition: msg.Partition,
+		Metadata:  msg.Metadata,
 	}
 
 	if msg.Key != nil {
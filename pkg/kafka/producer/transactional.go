@@ -0,0 +1,127 @@
+package producer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+)
+
+// TransactionalProducer - продюсер на транзакциях Kafka: всё, что отправлено внутри Transaction,
+// вместе с добавленными оффсетами консьюмера становится видно read_committed читателям атомарно.
+// В отличие от WithIdempotent, гарантия переживает рестарт - брокер отбрасывает незавершённые транзакции
+// прошлой сессии с тем же transactional ID.
+type TransactionalProducer struct {
+	producer sarama.SyncProducer
+	topic    string
+	// mu - у sarama продюсера одна транзакция за раз
+	mu sync.Mutex
+}
+
+// NewTransactional - producer должен быть создан с NewTransactionalConfig.
+func NewTransactional(producer sarama.SyncProducer, topic string) *TransactionalProducer {
+	return &TransactionalProducer{
+		producer: producer,
+		topic:    topic,
+	}
+}
+
+// Tx - открытая транзакция, живёт только внутри Transaction.
+type Tx struct {
+	p *TransactionalProducer
+}
+
+// Send - сообщение станет видно только после commit транзакции.
+func (tx *Tx) Send(ctx context.Context, msg kafka.Message) error {
+	pm := toSaramaMsg(tx.p.topic, msg)
+	if _, _, err := tx.p.producer.SendMessage(pm); err != nil {
+		return fmt.Errorf("kafka txn send: %w", err)
+	}
+
+	metrics.RecordProducerMessage(ctx, pm.Topic)
+
+	return nil
+}
+
+// AddConsumed - оффсет прочитанного сообщения группы groupID коммитится вместе с транзакцией.
+func (tx *Tx) AddConsumed(msg *sarama.ConsumerMessage, groupID string) error {
+	if err := tx.p.producer.AddMessageToTxn(msg, groupID, nil); err != nil {
+		return fmt.Errorf("kafka txn add offset: %w", err)
+	}
+
+	return nil
+}
+
+// Transaction - commit, если fn вернул nil, иначе abort. Если продюсер попал в неисправимое состояние,
+// ошибка помечается kafka.Fatal: продюсер нужно пересоздать.
+func (p *TransactionalProducer) Transaction(ctx context.Context, fn func(tx *Tx) error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.producer.BeginTxn(); err != nil {
+		return p.fail(ctx, fmt.Errorf("kafka txn begin: %w", err))
+	}
+
+	if err := fn(&Tx{p: p}); err != nil {
+		return errors.Join(err, p.abort(ctx))
+	}
+
+	if err := p.producer.CommitTxn(); err != nil {
+		return p.fail(ctx, fmt.Errorf("kafka txn commit: %w", err))
+	}
+
+	return nil
+}
+
+// Send - одно сообщение в своей транзакции.
+func (p *TransactionalProducer) Send(ctx context.Context, msg kafka.Message) error {
+	return p.Transaction(ctx, func(tx *Tx) error {
+		return tx.Send(ctx, msg)
+	})
+}
+
+func (p *TransactionalProducer) Close() error {
+	return p.producer.Close()
+}
+
+// fail - после ошибки begin/commit транзакцию надо откатить, если брокер это позволяет.
+func (p *TransactionalProducer) fail(ctx context.Context, err error) error {
+	if p.producer.TxnStatus()&sarama.ProducerTxnFlagAbortableError != 0 {
+		return errors.Join(err, p.abort(ctx))
+	}
+
+	return p.fatal(ctx, err)
+}
+
+func (p *TransactionalProducer) abort(ctx context.Context) error {
+	if p.producer.TxnStatus()&sarama.ProducerTxnFlagInTransaction == 0 &&
+		p.producer.TxnStatus()&sarama.ProducerTxnFlagAbortableError == 0 {
+		return nil
+	}
+
+	if err := p.producer.AbortTxn(); err != nil {
+		return p.fatal(ctx, fmt.Errorf("kafka txn abort: %w", err))
+	}
+
+	return nil
+}
+
+func (p *TransactionalProducer) fatal(ctx context.Context, err error) error {
+	if p.producer.TxnStatus()&sarama.ProducerTxnFlagFatalError == 0 {
+		return err
+	}
+
+	log := logger.Ctx(ctx)
+	log.Error().
+		Err(err).
+		Str("txn_status", p.producer.TxnStatus().String()).
+		Msg("kafka: transactional producer in fatal state")
+
+	return kafka.Fatal(err)
+}
@@ -62,3 +62,13 @@ func EnableOTel(ctx context.Context, cfg OTelConfig) error {
 
 	return nil
 }
+
+// WithStr кладёт в контекст логгер с дополнительным полем - logger.Ctx(ctx) пишет его в каждую запись.
+func WithStr(ctx context.Context, key, value string) context.Context {
+	l, ok := ctx.Value(loggerCtxKey{}).(zerolog.Logger)
+	if !ok {
+		l = log
+	}
+
+	return context.WithValue(ctx, loggerCtxKey{}, l.With().Str(key, value).Logger())
+}
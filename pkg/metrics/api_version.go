@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	apiVersionOnce    sync.Once
+	apiVersionCounter metric.Int64Counter
+)
+
+func initAPIVersionMetrics() {
+	apiVersionOnce.Do(func() {
+		meter := otel.Meter("pkg/metrics")
+		apiVersionCounter, _ = meter.Int64Counter("http.server.api_version.total", //nolint:gosec
+			metric.WithDescription("Total number of API requests by version, route and how the version was selected: path, accept, default"),
+		)
+	})
+}
+
+// RecordAPIVersion - deprecated показывает, сколько клиентов ещё ходит в устаревшие маршруты.
+func RecordAPIVersion(ctx context.Context, version, route, source string, deprecated bool) {
+	initAPIVersionMetrics()
+
+	apiVersionCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("api.version", version),
+		attribute.String("http.route", route),
+		attribute.String("api.version.source", source),
+		attribute.Bool("api.deprecated", deprecated),
+	))
+}
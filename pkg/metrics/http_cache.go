@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	httpCacheOnce    sync.Once
+	httpCacheCounter metric.Int64Counter
+)
+
+func initHTTPCacheMetrics() {
+	httpCacheOnce.Do(func() {
+		meter := otel.Meter("pkg/metrics")
+		httpCacheCounter, _ = meter.Int64Counter("http.server.cache.total", //nolint:gosec
+			metric.WithDescription("Total number of HTTP cache lookups by result: hit, miss, bypass, not_modified"),
+		)
+	})
+}
+
+func RecordHTTPCache(ctx context.Context, route, result string) {
+	initHTTPCacheMetrics()
+
+	httpCacheCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("http.route", route),
+		attribute.String("cache.result", result),
+	))
+}
@@ -10,7 +10,6 @@ import (
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
-	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
 )
 
 var (
@@ -18,11 +17,24 @@ var (
 	kafkaConsumerCounter  metric.Int64Counter
 	kafkaConsumerDuration metric.Float64Histogram
 	kafkaProducerCounter  metric.Int64Counter
+	kafkaRetryCounter     metric.Int64Counter
+	kafkaDLQCounter       metric.Int64Counter
+	kafkaErrorCounter     metric.Int64Counter
+	kafkaInFlight         metric.Int64UpDownCounter
+	kafkaBatchSize        metric.Int64Histogram
+	kafkaBatchFailed      metric.Int64Counter
+	kafkaHandlerDuration  metric.Float64Histogram
+	kafkaRebalanceCounter metric.Int64Counter
+	kafkaRebalanceTime    metric.Float64Histogram
+	kafkaLag              metric.Int64ObservableGauge
+	kafkaAssigned         metric.Int64ObservableGauge
+	kafkaMeter            metric.Meter
 )
 
 func initKafkaMetrics() {
 	kafkaOnce.Do(func() {
 		meter := otel.Meter("pkg/metrics")
+		kafkaMeter = meter
 		kafkaConsumerCounter, _ = meter.Int64Counter("kafka.consumer.messages.total", //nolint:gosec
 			metric.WithDescription("Total number of consumed Kafka messages"),
 		)
@@ -33,10 +45,47 @@ func initKafkaMetrics() {
 		kafkaProducerCounter, _ = meter.Int64Counter("kafka.producer.messages.total", //nolint:gosec
 			metric.WithDescription("Total number of produced Kafka messages"),
 		)
+		kafkaRetryCounter, _ = meter.Int64Counter("kafka.consumer.retries.total", //nolint:gosec
+			metric.WithDescription("Total number of Kafka messages moved to retry topics"),
+		)
+		kafkaDLQCounter, _ = meter.Int64Counter("kafka.consumer.dlq.total", //nolint:gosec
+			metric.WithDescription("Total number of Kafka messages moved to dead letter topics"),
+		)
+		kafkaErrorCounter, _ = meter.Int64Counter("kafka.consumer.errors.total", //nolint:gosec
+			metric.WithDescription("Total number of failed Kafka handler attempts by error class"),
+		)
+		kafkaInFlight, _ = meter.Int64UpDownCounter("kafka.consumer.inflight", //nolint:gosec
+			metric.WithDescription("Number of Kafka messages dispatched to consumer workers and not yet processed"),
+		)
+		kafkaBatchSize, _ = meter.Int64Histogram("kafka.consumer.batch.size", //nolint:gosec
+			metric.WithDescription("Number of Kafka messages in a consumed batch"),
+		)
+		kafkaBatchFailed, _ = meter.Int64Counter("kafka.consumer.batch.failed.total", //nolint:gosec
+			metric.WithDescription("Total number of batch messages retried one by one after a batch failure"),
+		)
+		kafkaHandlerDuration, _ = meter.Float64Histogram("kafka.consumer.handler.duration", //nolint:gosec
+			metric.WithDescription("Duration of a single Kafka handler attempt in seconds, without retries and backoff"),
+			metric.WithUnit("s"),
+		)
+		kafkaRebalanceCounter, _ = meter.Int64Counter("kafka.consumer.rebalances.total", //nolint:gosec
+			metric.WithDescription("Total number of consumer group sessions started after a rebalance"),
+		)
+		kafkaRebalanceTime, _ = meter.Float64Histogram("kafka.consumer.rebalance.duration", //nolint:gosec
+			metric.WithDescription("Time in seconds the consumer had no partitions assigned during a rebalance"),
+			metric.WithUnit("s"),
+		)
+		kafkaLag, _ = meter.Int64ObservableGauge("kafka.consumer.lag", //nolint:gosec
+			metric.WithDescription("Messages between the partition high-water mark and the next offset to commit"),
+		)
+		kafkaAssigned, _ = meter.Int64ObservableGauge("kafka.consumer.assigned.partitions", //nolint:gosec
+			metric.WithDescription("Number of partitions assigned to the consumer"),
+		)
 	})
 }
 
-func ConsumerMiddleware() consumer.Middleware {
+// ConsumerMiddleware - сигнатура совпадает с consumer.Middleware, сам тип не импортируется,
+// чтобы consumer мог писать метрики через этот пакет.
+func ConsumerMiddleware() func(kafka.MessageHandler) kafka.MessageHandler {
 	return func(next kafka.MessageHandler) kafka.MessageHandler {
 		return func(ctx context.Context, msg kafka.Message) error {
 			initKafkaMetrics()
@@ -62,3 +111,113 @@ func RecordProducerMessage(ctx context.Context, topic string) {
 		attribute.String("messaging.destination", topic),
 	))
 }
+
+// RecordConsumerRetry - сообщение из topic отложено в retry топик target.
+func RecordConsumerRetry(ctx context.Context, topic, target string) {
+	initKafkaMetrics()
+
+	kafkaRetryCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("messaging.destination", topic),
+		attribute.String("messaging.kafka.retry_topic", target),
+	))
+}
+
+// RecordConsumerDLQ - сообщение из topic отправлено в DLQ.
+func RecordConsumerDLQ(ctx context.Context, topic string) {
+	initKafkaMetrics()
+
+	kafkaDLQCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("messaging.destination", topic),
+	))
+}
+
+// RecordConsumerError - неудачная попытка обработки, class: retryable, permanent или fatal.
+func RecordConsumerError(ctx context.Context, topic, class string) {
+	initKafkaMetrics()
+
+	kafkaErrorCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("messaging.destination", topic),
+		attribute.String("error.class", class),
+	))
+}
+
+// RecordConsumerInFlight - delta +1 при передаче сообщения воркеру, -1 по завершении.
+func RecordConsumerInFlight(ctx context.Context, topic string, delta int64) {
+	initKafkaMetrics()
+
+	kafkaInFlight.Add(ctx, delta, metric.WithAttributes(
+		attribute.String("messaging.destination", topic),
+	))
+}
+
+// RecordConsumerBatch - обработан батч из size сообщений, failed из них будут повторены по одному.
+func RecordConsumerBatch(ctx context.Context, topic string, size, failed int) {
+	initKafkaMetrics()
+
+	attrs := metric.WithAttributes(attribute.String("messaging.destination", topic))
+	kafkaBatchSize.Record(ctx, int64(size), attrs)
+	if failed > 0 {
+		kafkaBatchFailed.Add(ctx, int64(failed), attrs)
+	}
+}
+
+// RecordConsumerHandler - одна попытка обработчика без retry и backoff, outcome: ok или класс ошибки.
+func RecordConsumerHandler(ctx context.Context, topic, outcome string, d time.Duration) {
+	initKafkaMetrics()
+
+	kafkaHandlerDuration.Record(ctx, d.Seconds(), metric.WithAttributes(
+		attribute.String("messaging.destination", topic),
+		attribute.String("outcome", outcome),
+	))
+}
+
+// RecordConsumerRebalance - началась новая сессия группы, d - сколько consumer был без партиций.
+func RecordConsumerRebalance(ctx context.Context, d time.Duration) {
+	initKafkaMetrics()
+
+	kafkaRebalanceCounter.Add(ctx, 1)
+	kafkaRebalanceTime.Record(ctx, d.Seconds())
+}
+
+// ConsumerPartition - назначенная consumer партиция для kafka.consumer.lag.
+type ConsumerPartition struct {
+	Topic     string
+	Partition int32
+	// Lag < 0 - неизвестен, в метрику не попадает
+	Lag int64
+}
+
+// ObserveConsumerPartitions - fn опрашивается при каждом сборе метрик: лаг по партициям и число
+// назначенных партиций по топикам. Возвращает функцию, снимающую регистрацию.
+func ObserveConsumerPartitions(fn func() []ConsumerPartition) (func() error, error) {
+	initKafkaMetrics()
+
+	reg, err := kafkaMeter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		assigned := make(map[string]int64)
+
+		for _, p := range fn() {
+			assigned[p.Topic]++
+			if p.Lag < 0 {
+				continue
+			}
+
+			o.ObserveInt64(kafkaLag, p.Lag, metric.WithAttributes(
+				attribute.String("messaging.destination", p.Topic),
+				attribute.Int("messaging.kafka.partition", int(p.Partition)),
+			))
+		}
+
+		for topic, n := range assigned {
+			o.ObserveInt64(kafkaAssigned, n, metric.WithAttributes(
+				attribute.String("messaging.destination", topic),
+			))
+		}
+
+		return nil
+	}, kafkaLag, kafkaAssigned)
+	if err != nil {
+		return nil, err
+	}
+
+	return reg.Unregister, nil
+}
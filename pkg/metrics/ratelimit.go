@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	rateLimitOnce    sync.Once
+	rateLimitCounter metric.Int64Counter
+)
+
+func initRateLimitMetrics() {
+	rateLimitOnce.Do(func() {
+		meter := otel.Meter("pkg/metrics")
+		rateLimitCounter, _ = meter.Int64Counter("http.server.ratelimit.total", //nolint:gosec
+			metric.WithDescription("Total number of rate limit decisions"),
+		)
+	})
+}
+
+func RecordRateLimit(ctx context.Context, policy string, allowed bool) {
+	initRateLimitMetrics()
+
+	decision := "allowed"
+	if !allowed {
+		decision = "rejected"
+	}
+
+	rateLimitCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("ratelimit.policy", policy),
+		attribute.String("ratelimit.decision", decision),
+	))
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var (
+	resilienceOnce           sync.Once
+	clientRetryCounter       metric.Int64Counter
+	breakerStateGauge        metric.Int64Gauge
+	breakerTransitionCounter metric.Int64Counter
+)
+
+func initResilienceMetrics() {
+	resilienceOnce.Do(func() {
+		meter := otel.Meter("pkg/metrics")
+		clientRetryCounter, _ = meter.Int64Counter("rpc.client.retry.total", //nolint:gosec
+			metric.WithDescription("Total number of gRPC client retries"),
+		)
+		breakerStateGauge, _ = meter.Int64Gauge("rpc.client.circuit_breaker.state", //nolint:gosec
+			metric.WithDescription("Circuit breaker state: 0 closed, 1 open, 2 half-open"),
+		)
+		breakerTransitionCounter, _ = meter.Int64Counter("rpc.client.circuit_breaker.transitions.total", //nolint:gosec
+			metric.WithDescription("Total number of circuit breaker state transitions"),
+		)
+	})
+}
+
+func RecordGRPCClientRetry(ctx context.Context, method string) {
+	initResilienceMetrics()
+
+	clientRetryCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rpc.method", method),
+	))
+}
+
+func RecordCircuitBreakerState(ctx context.Context, upstream string, state int64) {
+	initResilienceMetrics()
+
+	breakerStateGauge.Record(ctx, state, metric.WithAttributes(
+		attribute.String("rpc.upstream", upstream),
+	))
+}
+
+func RecordCircuitBreakerTransition(ctx context.Context, upstream, state string) {
+	initResilienceMetrics()
+
+	breakerTransitionCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("rpc.upstream", upstream),
+		attribute.String("circuit_breaker.state", state),
+	))
+}
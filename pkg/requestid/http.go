@@ -0,0 +1,15 @@
+package requestid
+
+import "net/http"
+
+// HTTPMiddleware берёт X-Request-ID клиента или генерирует новый и возвращает его в ответе.
+func HTTPMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := fromValue(r.Header.Get(Header))
+
+			w.Header().Set(Header, id)
+			next.ServeHTTP(w, r.WithContext(WithID(r.Context(), id)))
+		})
+	}
+}
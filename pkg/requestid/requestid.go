@@ -0,0 +1,62 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+)
+
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+	// LogField - поле с id запроса во всех записях logger.Ctx
+	LogField = "request_id"
+
+	maxLen = 128
+)
+
+type ctxKey struct{}
+
+// New генерирует id запроса. UUIDv7 сортируется по времени, удобно искать в логах.
+func New() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// WithID кладёт id в контекст и в логгер контекста.
+func WithID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, ctxKey{}, id)
+	return logger.WithStr(ctx, LogField, id)
+}
+
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(string)
+	return id, ok
+}
+
+// Valid - id от клиента попадает в логи и заголовки, поэтому принимаются только короткие строки из безопасных символов.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func fromValue(id string) string {
+	if Valid(id) {
+		return id
+	}
+
+	return New()
+}
@@ -0,0 +1,103 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHTTPMiddleware_KeepsClientID(t *testing.T) {
+	var got string
+	handler := HTTPMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(Header, "client-id-1")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if got != "client-id-1" {
+		t.Errorf("context id = %q, want %q", got, "client-id-1")
+	}
+	if h := w.Header().Get(Header); h != "client-id-1" {
+		t.Errorf("response header = %q, want %q", h, "client-id-1")
+	}
+}
+
+func TestHTTPMiddleware_ReplacesInvalidID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{name: "missing", id: ""},
+		{name: "too long", id: strings.Repeat("a", maxLen+1)},
+		{name: "unsafe chars", id: "id\nforged log line"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := HTTPMiddleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got, _ = FromContext(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.id != "" {
+				r.Header.Set(Header, tt.id)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got == "" || got == tt.id || !Valid(got) {
+				t.Errorf("context id = %q, want generated", got)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor_ForwardsID(t *testing.T) {
+	ctx := WithID(context.Background(), "req-1")
+
+	var got []string
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get(MetadataKey)
+		return nil
+	}
+
+	if err := UnaryClientInterceptor()(ctx, "/svc/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if len(got) != 1 || got[0] != "req-1" {
+		t.Errorf("metadata = %v, want [req-1]", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	var got string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		got, _ = FromContext(ctx)
+		return nil, nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "req-1"))
+	if _, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if got != "req-1" {
+		t.Errorf("id = %q, want %q", got, "req-1")
+	}
+
+	if _, err := UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if got == "" || got == "req-1" {
+		t.Errorf("id = %q, want generated", got)
+	}
+}
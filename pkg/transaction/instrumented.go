@@ -38,3 +38,10 @@ func (i *instrumentedExecutor) QueryRow(ctx context.Context, sql string, args ..
 	metrics.RecordDBOperation(ctx, "query_row", start)
 	return row
 }
+
+func (i *instrumentedExecutor) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	start := time.Now()
+	n, err := i.inner.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	metrics.RecordDBOperation(ctx, "copy", start)
+	return n, err
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
 	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/grpcvalidate"
+	"github.com/SonOfSteveJobs/habr/pkg/health"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/pkg/requestid"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/config"
 )
@@ -19,6 +21,7 @@ import (
 type App struct {
 	infra   *infraContainer
 	service *serviceContainer
+	health  *health.Health
 
 	grpcServer *grpc.Server
 	listener   net.Listener
@@ -38,6 +41,23 @@ func (a *App) Run() {
 	log := logger.Logger()
 	cfg := config.AppConfig()
 
+	flusherCtx, flusherCancel := context.WithCancel(context.Background())
+	flusherDone := make(chan struct{})
+	go func() {
+		defer close(flusherDone)
+		a.service.ViewFlusher().Run(flusherCtx)
+	}()
+	// ждём последний сброс просмотров, пока Redis и Postgres ещё открыты
+	closer.AddNamed("view flusher", func(ctx context.Context) error {
+		flusherCancel()
+		select {
+		case <-flusherDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
 	log.Info().Str("port", cfg.GRPCPort()).Msg("starting gRPC server")
 
 	go func() {
@@ -63,6 +83,7 @@ func (a *App) initDeps(ctx context.Context) error {
 		{"metrics", a.initMetrics},
 		{"infra: postgres, redis", a.initInfra},
 		{"service: repositories, services", a.initService},
+		{"health", a.initHealth},
 		{"gRPC server", a.initGRPCServer},
 		{"listener", a.initListener},
 	}
@@ -92,6 +113,16 @@ func (a *App) initService(_ context.Context) error {
 	return nil
 }
 
+// initHealth - readiness по зависимостям, отдаётся через grpc.health.v1 и падает с началом shutdown.
+func (a *App) initHealth(_ context.Context) error {
+	closer.SetDrainDelay(config.AppConfig().DrainDelay())
+	a.health = health.New(closer.Draining())
+	a.health.Add("postgres", health.Postgres(a.infra.PgPool()))
+	a.health.Add("redis", health.Redis(a.infra.RedisClient()))
+
+	return nil
+}
+
 func (a *App) initTracing(ctx context.Context) error {
 	if err := tracing.InitTracer(ctx, config.AppConfig().Tracing()); err != nil {
 		return err
@@ -126,11 +157,14 @@ func (a *App) initGRPCServer(_ context.Context) error {
 	a.grpcServer = grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			tracing.UnaryServerInterceptor(),
+			requestid.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor(),
 			grpcvalidate.UnaryInterceptor(),
 		),
+		grpc.ChainStreamInterceptor(requestid.StreamServerInterceptor()),
 	)
 	articlev1.RegisterArticleServiceServer(a.grpcServer, a.service.Handler())
+	a.health.RegisterGRPC(a.grpcServer)
 	reflection.Register(a.grpcServer)
 	closer.AddNamed("gRPC server", func(_ context.Context) error {
 		a.grpcServer.GracefulStop()
@@ -5,7 +5,10 @@ import (
 	articlegrpc "github.com/SonOfSteveJobs/habr/services/article/internal/handler/grpc"
 	articlerepo "github.com/SonOfSteveJobs/habr/services/article/internal/repository/article"
 	cacherepo "github.com/SonOfSteveJobs/habr/services/article/internal/repository/cache"
+	moderationrepo "github.com/SonOfSteveJobs/habr/services/article/internal/repository/moderation"
+	viewrepo "github.com/SonOfSteveJobs/habr/services/article/internal/repository/view"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/service"
+	"github.com/SonOfSteveJobs/habr/services/article/internal/views"
 )
 
 type serviceContainer struct {
@@ -13,6 +16,9 @@ type serviceContainer struct {
 
 	articleRepo    *articlerepo.Repository
 	cacheRepo      *cacherepo.Repository
+	viewRepo       *viewrepo.Repository
+	moderationRepo *moderationrepo.Repository
+	viewFlusher    *views.Flusher
 	articleService *service.Service
 	handler        *articlegrpc.Handler
 }
@@ -40,11 +46,45 @@ func (c *serviceContainer) CacheRepo() *cacherepo.Repository {
 	return c.cacheRepo
 }
 
+func (c *serviceContainer) ViewRepo() *viewrepo.Repository {
+	if c.viewRepo == nil {
+		c.viewRepo = viewrepo.New(
+			c.infra.RedisClient(),
+			config.AppConfig().ViewsDedupWindow(),
+		)
+	}
+
+	return c.viewRepo
+}
+
+func (c *serviceContainer) ModerationRepo() *moderationrepo.Repository {
+	if c.moderationRepo == nil {
+		c.moderationRepo = moderationrepo.New(c.infra.TxManager())
+	}
+
+	return c.moderationRepo
+}
+
+func (c *serviceContainer) ViewFlusher() *views.Flusher {
+	if c.viewFlusher == nil {
+		c.viewFlusher = views.NewFlusher(
+			c.ViewRepo(),
+			c.ArticleRepo(),
+			c.CacheRepo(),
+			config.AppConfig().ViewsFlushInterval(),
+		)
+	}
+
+	return c.viewFlusher
+}
+
 func (c *serviceContainer) ArticleService() *service.Service {
 	if c.articleService == nil {
 		c.articleService = service.New(
 			c.ArticleRepo(),
 			c.CacheRepo(),
+			c.ViewRepo(),
+			c.ModerationRepo(),
 			c.infra.TxManager(),
 		)
 	}
@@ -7,25 +7,37 @@ import (
 	"github.com/joho/godotenv"
 )
 
-const defaultCacheArticlesTTL = 5 * time.Minute
+const (
+	defaultCacheArticlesTTL   = 5 * time.Minute
+	defaultViewsDedupWindow   = 30 * time.Minute
+	defaultViewsFlushInterval = 30 * time.Second
+)
 
 var appConfig *Config
 
 type Config struct {
-	grpcPort         string
-	dbURI            string
-	redisAddr        string
-	logger           LoggerConfig
-	cacheArticlesTTL time.Duration
-	tracing          *TracingConfig
+	grpcPort           string
+	dbURI              string
+	redisAddr          string
+	logger             LoggerConfig
+	cacheArticlesTTL   time.Duration
+	viewsDedupWindow   time.Duration
+	viewsFlushInterval time.Duration
+	tracing            *TracingConfig
+	drainDelay         time.Duration
 }
 
-func (c *Config) GRPCPort() string                { return c.grpcPort }
-func (c *Config) DBURI() string                   { return c.dbURI }
-func (c *Config) RedisAddr() string               { return c.redisAddr }
-func (c *Config) Logger() LoggerConfig            { return c.logger }
-func (c *Config) CacheArticlesTTL() time.Duration { return c.cacheArticlesTTL }
-func (c *Config) Tracing() *TracingConfig         { return c.tracing }
+func (c *Config) GRPCPort() string                  { return c.grpcPort }
+func (c *Config) DBURI() string                     { return c.dbURI }
+func (c *Config) RedisAddr() string                 { return c.redisAddr }
+func (c *Config) Logger() LoggerConfig              { return c.logger }
+func (c *Config) CacheArticlesTTL() time.Duration   { return c.cacheArticlesTTL }
+func (c *Config) ViewsDedupWindow() time.Duration   { return c.viewsDedupWindow }
+func (c *Config) ViewsFlushInterval() time.Duration { return c.viewsFlushInterval }
+func (c *Config) Tracing() *TracingConfig           { return c.tracing }
+
+// DrainDelay - пауза при shutdown, пока балансировщик снимает под, до остановки gRPC сервера.
+func (c *Config) DrainDelay() time.Duration { return c.drainDelay }
 
 func Load(path ...string) error {
 	err := godotenv.Load(path...)
@@ -62,21 +74,58 @@ func Load(path ...string) error {
 		cacheArticlesTTL = parsed
 	}
 
+	viewsDedupWindow, err := parseDuration("VIEWS_DEDUP_WINDOW", defaultViewsDedupWindow, ErrInvalidViewsDedupWindow)
+	if err != nil {
+		return err
+	}
+
+	viewsFlushInterval, err := parseDuration("VIEWS_FLUSH_INTERVAL", defaultViewsFlushInterval, ErrInvalidViewsFlushInterval)
+	if err != nil {
+		return err
+	}
+
 	tracing, err := newTracingConfig()
 	if err != nil {
 		return err
 	}
 
+	var drainDelay time.Duration
+	if v := os.Getenv("SHUTDOWN_DRAIN_DELAY"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			return ErrInvalidDrainDelay
+		}
+		drainDelay = parsed
+	}
+
 	appConfig = &Config{
-		grpcPort:         grpcPort,
-		dbURI:            dbURI,
-		redisAddr:        redisAddr,
-		logger:           logger,
-		cacheArticlesTTL: cacheArticlesTTL,
-		tracing:          tracing,
+		grpcPort:           grpcPort,
+		dbURI:              dbURI,
+		redisAddr:          redisAddr,
+		logger:             logger,
+		cacheArticlesTTL:   cacheArticlesTTL,
+		viewsDedupWindow:   viewsDedupWindow,
+		viewsFlushInterval: viewsFlushInterval,
+		tracing:            tracing,
+		drainDelay:         drainDelay,
 	}
 
 	return nil
 }
 
 func AppConfig() *Config { return appConfig }
+
+// parseDuration читает положительную длительность из env, при отсутствии переменной возвращает def.
+func parseDuration(key string, def time.Duration, errInvalid error) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	parsed, err := time.ParseDuration(v)
+	if err != nil || parsed <= 0 {
+		return 0, errInvalid
+	}
+
+	return parsed, nil
+}
@@ -10,6 +10,9 @@ var (
 	ErrLoggerAsJsonNotProvided    = errors.New("LOGGER_AS_JSON is not provided")
 	ErrLoggerAsJsonInvalid        = errors.New("LOGGER_AS_JSON must be true or false")
 	ErrInvalidCacheTTL            = errors.New("CACHE_ARTICLES_TTL is not a valid duration")
+	ErrInvalidViewsDedupWindow    = errors.New("VIEWS_DEDUP_WINDOW is not a valid duration")
+	ErrInvalidViewsFlushInterval  = errors.New("VIEWS_FLUSH_INTERVAL is not a valid duration")
 	ErrOtelEndpointNotProvided    = errors.New("OTEL_COLLECTOR_ENDPOINT is not provided")
 	ErrOtelServiceNameNotProvided = errors.New("OTEL_SERVICE_NAME is not provided")
+	ErrInvalidDrainDelay          = errors.New("SHUTDOWN_DRAIN_DELAY must be a non-negative duration")
 )
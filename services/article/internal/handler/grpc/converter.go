@@ -7,6 +7,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
@@ -14,69 +15,150 @@ import (
 func createArticleError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrInvalidTitle):
-		return status.Error(codes.InvalidArgument, "invalid title")
+		return grpcerr.Field(grpcerr.ReasonInvalidTitle, "title", "invalid title")
 	case errors.Is(err, model.ErrInvalidContent):
-		return status.Error(codes.InvalidArgument, "invalid content")
+		return grpcerr.Field(grpcerr.ReasonInvalidContent, "content", "invalid content")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("create article: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func getArticleError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrArticleNotFound):
-		return status.Error(codes.NotFound, "article not found")
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("get article: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func updateArticleError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrArticleNotFound):
-		return status.Error(codes.NotFound, "article not found")
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
 	case errors.Is(err, model.ErrNotAuthor):
-		return status.Error(codes.PermissionDenied, "not the author")
+		return grpcerr.New(codes.PermissionDenied, grpcerr.ReasonNotAuthor, "not the author")
 	case errors.Is(err, model.ErrInvalidTitle):
-		return status.Error(codes.InvalidArgument, "invalid title")
+		return grpcerr.Field(grpcerr.ReasonInvalidTitle, "title", "invalid title")
 	case errors.Is(err, model.ErrInvalidContent):
-		return status.Error(codes.InvalidArgument, "invalid content")
+		return grpcerr.Field(grpcerr.ReasonInvalidContent, "content", "invalid content")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("update article: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func deleteArticleError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrArticleNotFound):
-		return status.Error(codes.NotFound, "article not found")
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
 	case errors.Is(err, model.ErrNotAuthor):
-		return status.Error(codes.PermissionDenied, "not the author")
+		return grpcerr.New(codes.PermissionDenied, grpcerr.ReasonNotAuthor, "not the author")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("delete article: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func listArticlesError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrInvalidCursor):
-		return status.Error(codes.InvalidArgument, "invalid cursor")
+		return grpcerr.Field(grpcerr.ReasonInvalidCursor, "cursor", "invalid cursor")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("list articles: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
+
+func listMostReadError(ctx context.Context, err error) error {
+	log := logger.Ctx(ctx)
+	log.Error().Err(err).Msg("list most read: internal error")
+
+	return grpcerr.Internal()
+}
+
+func getRelatedArticlesError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrArticleNotFound):
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
+	default:
+		log := logger.Ctx(ctx)
+		log.Error().Err(err).Msg("get related articles: internal error")
+
+		return grpcerr.Internal()
+	}
+}
+
+func exportArticlesError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+
+	log := logger.Ctx(ctx)
+	log.Error().Err(err).Msg("export articles: internal error")
+
+	return grpcerr.Internal()
+}
+
+// importResultError - причина отказа для отдельной статьи импорта. Внутренние ошибки наружу не отдаются.
+func importResultError(err error) string {
+	switch {
+	case errors.Is(err, model.ErrInvalidTitle):
+		return "invalid title"
+	case errors.Is(err, model.ErrInvalidContent):
+		return "invalid content"
+	case errors.Is(err, model.ErrInvalidCreatedAt):
+		return "created_at is in the future"
+	default:
+		return "internal error"
+	}
+}
+
+func reportArticleError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrArticleNotFound):
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
+	case errors.Is(err, model.ErrInvalidReason):
+		return grpcerr.Field(grpcerr.ReasonInvalidReportReason, "reason", "invalid reason")
+	default:
+		log := logger.Ctx(ctx)
+		log.Error().Err(err).Msg("report article: internal error")
+
+		return grpcerr.Internal()
+	}
+}
+
+func moderateArticleError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrArticleNotFound):
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonArticleNotFound, "article not found")
+	case errors.Is(err, model.ErrInvalidReason):
+		return grpcerr.Field(grpcerr.ReasonInvalidReportReason, "reason", "invalid reason")
+	case errors.Is(err, model.ErrInvalidModerationAction):
+		return grpcerr.Field(grpcerr.ReasonInvalidModerationAction, "action", "invalid moderation action")
+	default:
+		log := logger.Ctx(ctx)
+		log.Error().Err(err).Msg("moderate article: internal error")
+
+		return grpcerr.Internal()
+	}
+}
+
+func listModerationQueueError(ctx context.Context, err error) error {
+	log := logger.Ctx(ctx)
+	log.Error().Err(err).Msg("list moderation queue: internal error")
+
+	return grpcerr.Internal()
+}
@@ -2,6 +2,8 @@ package articlegrpc
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -15,11 +17,25 @@ import (
 type ArticleService interface {
 	CreateArticle(ctx context.Context, authorID uuid.UUID, title, content string) (*model.Article, error)
 	ListArticles(ctx context.Context, cursor string, limit int32) (*model.ArticlePage, error)
-	GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error)
+	GetArticle(ctx context.Context, id uuid.UUID, viewerID string) (*model.Article, error)
 	UpdateArticle(ctx context.Context, id, authorID uuid.UUID, title, content *string) (*model.Article, error)
 	DeleteArticle(ctx context.Context, id, authorID uuid.UUID) error
+	ListMostRead(ctx context.Context, limit int32) ([]*model.Article, error)
+	GetRelatedArticles(ctx context.Context, id uuid.UUID, limit int32) ([]*model.Article, error)
+	ExportArticles(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error
+	ImportBatch(ctx context.Context, authorID uuid.UUID, offset int, items []model.ImportItem) []model.ImportResult
+	ReportArticle(ctx context.Context, articleID, reporterID uuid.UUID, reason string) error
+	ModerateArticle(ctx context.Context, id, moderatorID uuid.UUID, action model.ModerationAction, reason string) error
+	ListModerationQueue(ctx context.Context, limit int32) ([]*model.ReportedArticle, error)
 }
 
+// importBatchSize - сколько статей из потока импорта вставляется одним COPY.
+const importBatchSize = 500
+
+// maxImportResults - сколько результатов по статьям попадает в ответ импорта. Счётчики считают весь поток,
+// а список дальше не растёт, чтобы большой архив не держал в памяти результат на каждую статью.
+const maxImportResults = 1000
+
 type Handler struct {
 	articlev1.UnimplementedArticleServiceServer
 	articleService ArticleService
@@ -68,7 +84,7 @@ func (h *Handler) GetArticle(ctx context.Context, req *articlev1.GetArticleReque
 		return nil, status.Error(codes.InvalidArgument, "invalid id")
 	}
 
-	article, err := h.articleService.GetArticle(ctx, id)
+	article, err := h.articleService.GetArticle(ctx, id, req.GetViewerId())
 	if err != nil {
 		return nil, getArticleError(ctx, err)
 	}
@@ -117,12 +133,222 @@ func (h *Handler) DeleteArticle(ctx context.Context, req *articlev1.DeleteArticl
 	return &articlev1.DeleteArticleResponse{}, nil
 }
 
+func (h *Handler) ListMostRead(ctx context.Context, req *articlev1.ListMostReadRequest) (*articlev1.ListMostReadResponse, error) {
+	articles, err := h.articleService.ListMostRead(ctx, req.GetLimit())
+	if err != nil {
+		return nil, listMostReadError(ctx, err)
+	}
+
+	resp := make([]*articlev1.Article, len(articles))
+	for i, a := range articles {
+		resp[i] = toProtoArticle(a)
+	}
+
+	return &articlev1.ListMostReadResponse{
+		Articles: resp,
+	}, nil
+}
+
+func (h *Handler) GetRelatedArticles(ctx context.Context, req *articlev1.GetRelatedArticlesRequest) (*articlev1.GetRelatedArticlesResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	related, err := h.articleService.GetRelatedArticles(ctx, id, req.GetLimit())
+	if err != nil {
+		return nil, getRelatedArticlesError(ctx, err)
+	}
+
+	articles := make([]*articlev1.Article, len(related))
+	for i, a := range related {
+		articles[i] = toProtoArticle(a)
+	}
+
+	return &articlev1.GetRelatedArticlesResponse{
+		Articles: articles,
+	}, nil
+}
+
+func (h *Handler) ExportArticles(req *articlev1.ExportArticlesRequest, stream articlev1.ArticleService_ExportArticlesServer) error {
+	ctx := stream.Context()
+
+	authorID := uuid.Nil
+	if req.GetAuthorId() != "" {
+		id, err := uuid.Parse(req.GetAuthorId())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid author_id")
+		}
+		authorID = id
+	}
+
+	err := h.articleService.ExportArticles(ctx, authorID, func(a *model.Article) error {
+		return stream.Send(&articlev1.ExportArticlesResponse{Article: toProtoArticle(a)})
+	})
+	if err != nil {
+		return exportArticlesError(ctx, err)
+	}
+
+	return nil
+}
+
+func (h *Handler) ImportArticles(stream articlev1.ArticleService_ImportArticlesServer) error {
+	ctx := stream.Context()
+
+	var (
+		authorID uuid.UUID
+		batch    []model.ImportItem
+		offset   int
+		resp     articlev1.ImportArticlesResponse
+	)
+
+	flush := func() {
+		for _, r := range h.articleService.ImportBatch(ctx, authorID, offset, batch) {
+			if len(resp.Results) < maxImportResults {
+				resp.Results = append(resp.Results, toProtoImportResult(r))
+			} else {
+				resp.ResultsTruncated = true
+			}
+			if r.Err != nil {
+				resp.Failed++
+			} else {
+				resp.Imported++
+			}
+		}
+
+		offset += len(batch)
+		batch = batch[:0]
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		id, err := uuid.Parse(req.GetAuthorId())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid author_id")
+		}
+		if authorID == uuid.Nil {
+			authorID = id
+		} else if id != authorID {
+			return status.Error(codes.InvalidArgument, "author_id must be the same for the whole stream")
+		}
+
+		item := model.ImportItem{Title: req.GetTitle(), Content: req.GetContent()}
+		if ts := req.GetCreatedAt(); ts != nil {
+			item.CreatedAt = ts.AsTime()
+		}
+
+		batch = append(batch, item)
+		if len(batch) == importBatchSize {
+			flush()
+		}
+	}
+
+	if len(batch) > 0 {
+		flush()
+	}
+
+	return stream.SendAndClose(&resp)
+}
+
+func (h *Handler) ReportArticle(ctx context.Context, req *articlev1.ReportArticleRequest) (*articlev1.ReportArticleResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	reporterID, err := uuid.Parse(req.GetReporterId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid reporter_id")
+	}
+
+	if err := h.articleService.ReportArticle(ctx, id, reporterID, req.GetReason()); err != nil {
+		return nil, reportArticleError(ctx, err)
+	}
+
+	return &articlev1.ReportArticleResponse{}, nil
+}
+
+func (h *Handler) ModerateArticle(ctx context.Context, req *articlev1.ModerateArticleRequest) (*articlev1.ModerateArticleResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid id")
+	}
+
+	moderatorID, err := uuid.Parse(req.GetModeratorId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid moderator_id")
+	}
+
+	err = h.articleService.ModerateArticle(ctx, id, moderatorID, fromProtoModerationAction(req.GetAction()), req.GetReason())
+	if err != nil {
+		return nil, moderateArticleError(ctx, err)
+	}
+
+	return &articlev1.ModerateArticleResponse{}, nil
+}
+
+func (h *Handler) ListModerationQueue(ctx context.Context, req *articlev1.ListModerationQueueRequest) (*articlev1.ListModerationQueueResponse, error) {
+	queue, err := h.articleService.ListModerationQueue(ctx, req.GetLimit())
+	if err != nil {
+		return nil, listModerationQueueError(ctx, err)
+	}
+
+	items := make([]*articlev1.ReportedArticle, len(queue))
+	for i, item := range queue {
+		items[i] = &articlev1.ReportedArticle{
+			Article:         toProtoArticle(item.Article),
+			ReportCount:     int32(item.ReportCount),
+			Reasons:         item.Reasons,
+			FirstReportedAt: timestamppb.New(item.FirstReportedAt),
+		}
+	}
+
+	return &articlev1.ListModerationQueueResponse{
+		Items: items,
+	}, nil
+}
+
+func fromProtoModerationAction(action articlev1.ModerationAction) model.ModerationAction {
+	switch action {
+	case articlev1.ModerationAction_MODERATION_ACTION_HIDE:
+		return model.ModerationHide
+	case articlev1.ModerationAction_MODERATION_ACTION_DELETE:
+		return model.ModerationDelete
+	case articlev1.ModerationAction_MODERATION_ACTION_DISMISS:
+		return model.ModerationDismiss
+	default:
+		return ""
+	}
+}
+
+func toProtoImportResult(r model.ImportResult) *articlev1.ImportArticleResult {
+	if r.Err != nil {
+		return &articlev1.ImportArticleResult{
+			Index: int32(r.Index),
+			Error: importResultError(r.Err),
+		}
+	}
+
+	return &articlev1.ImportArticleResult{
+		Index: int32(r.Index),
+		Id:    r.ArticleID.String(),
+	}
+}
+
 func toProtoArticle(a *model.Article) *articlev1.Article {
 	return &articlev1.Article{
 		Id:        a.ID.String(),
 		AuthorId:  a.AuthorID.String(),
 		Title:     a.Title,
 		Content:   a.Content,
+		ViewCount: a.ViewCount,
 		CreatedAt: timestamppb.New(a.CreatedAt),
 		UpdatedAt: timestamppb.New(a.UpdatedAt),
 	}
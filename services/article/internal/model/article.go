@@ -22,6 +22,7 @@ type Article struct {
 	AuthorID  uuid.UUID
 	Title     string
 	Content   string
+	ViewCount int64
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -8,4 +8,10 @@ var (
 	ErrInvalidContent  = errors.New("invalid content")
 	ErrInvalidCursor   = errors.New("invalid cursor")
 	ErrNotAuthor       = errors.New("not the author")
+	ErrInvalidReason   = errors.New("invalid reason")
+
+	ErrInvalidModerationAction = errors.New("invalid moderation action")
+
+	// ErrInvalidCreatedAt - дата публикации импортируемой статьи в будущем
+	ErrInvalidCreatedAt = errors.New("invalid created_at")
 )
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImportItem - статья из архива импорта. Нулевой CreatedAt - время импорта.
+type ImportItem struct {
+	Title     string
+	Content   string
+	CreatedAt time.Time
+}
+
+// ImportResult - итог импорта одной статьи: ArticleID при успехе, Err при отказе.
+type ImportResult struct {
+	Index     int
+	ArticleID uuid.UUID
+	Err       error
+}
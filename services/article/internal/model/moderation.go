@@ -0,0 +1,104 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	reasonMinLen = 1
+	reasonMaxLen = 1000
+)
+
+// ModerationAction - действие модератора над статьёй.
+type ModerationAction string
+
+const (
+	// ModerationHide скрывает статью из ленты и выдачи, автор её не теряет.
+	ModerationHide ModerationAction = "hide"
+	// ModerationDelete удаляет статью независимо от автора.
+	ModerationDelete ModerationAction = "delete"
+	// ModerationDismiss закрывает жалобы, статья остаётся как есть.
+	ModerationDismiss ModerationAction = "dismiss"
+)
+
+// Report - жалоба пользователя на статью.
+type Report struct {
+	ID         uuid.UUID
+	ArticleID  uuid.UUID
+	ReporterID uuid.UUID
+	Reason     string
+	CreatedAt  time.Time
+}
+
+func NewReport(articleID, reporterID uuid.UUID, reason string) (*Report, error) {
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		ID:         id,
+		ArticleID:  articleID,
+		ReporterID: reporterID,
+		Reason:     reason,
+	}, nil
+}
+
+// ModerationRecord - строка журнала модерации.
+type ModerationRecord struct {
+	ID          uuid.UUID
+	ArticleID   uuid.UUID
+	ModeratorID uuid.UUID
+	Action      ModerationAction
+	Reason      string
+	CreatedAt   time.Time
+}
+
+func NewModerationRecord(articleID, moderatorID uuid.UUID, action ModerationAction, reason string) (*ModerationRecord, error) {
+	switch action {
+	case ModerationHide, ModerationDelete, ModerationDismiss:
+	default:
+		return nil, ErrInvalidModerationAction
+	}
+
+	if err := validateReason(reason); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ModerationRecord{
+		ID:          id,
+		ArticleID:   articleID,
+		ModeratorID: moderatorID,
+		Action:      action,
+		Reason:      reason,
+	}, nil
+}
+
+// ReportedArticle - элемент очереди модерации: статья с открытыми жалобами.
+type ReportedArticle struct {
+	Article         *Article
+	ReportCount     int
+	Reasons         []string
+	FirstReportedAt time.Time
+}
+
+func validateReason(reason string) error {
+	reasonLen := utf8.RuneCountInString(reason)
+	if reasonLen < reasonMinLen || reasonLen > reasonMaxLen {
+		return ErrInvalidReason
+	}
+
+	return nil
+}
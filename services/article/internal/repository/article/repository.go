@@ -43,8 +43,9 @@ func (r *Repository) List(ctx context.Context, cursor string, limit int) (*model
 
 	if cursor == "" {
 		const query = `
-			SELECT id, author_id, title, content, created_at, updated_at
+			SELECT id, author_id, title, content, view_count, created_at, updated_at
 			FROM articles
+			WHERE hidden_at IS NULL
 			ORDER BY created_at DESC, id DESC
 			LIMIT $1
 		`
@@ -56,9 +57,9 @@ func (r *Repository) List(ctx context.Context, cursor string, limit int) (*model
 		}
 
 		const query = `
-			SELECT id, author_id, title, content, created_at, updated_at
+			SELECT id, author_id, title, content, view_count, created_at, updated_at
 			FROM articles
-			WHERE (created_at, id) < ($1, $2)
+			WHERE hidden_at IS NULL AND (created_at, id) < ($1, $2)
 			ORDER BY created_at DESC, id DESC
 			LIMIT $3
 		`
@@ -88,13 +89,13 @@ func (r *Repository) List(ctx context.Context, cursor string, limit int) (*model
 
 func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*model.Article, error) {
 	const query = `
-		SELECT id, author_id, title, content, created_at, updated_at
-		FROM articles WHERE id = $1
+		SELECT id, author_id, title, content, view_count, created_at, updated_at
+		FROM articles WHERE id = $1 AND hidden_at IS NULL
 	`
 
 	var a model.Article
 	err := r.txManager.ExtractExecutor(ctx).QueryRow(ctx, query, id).
-		Scan(&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.CreatedAt, &a.UpdatedAt)
+		Scan(&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.ViewCount, &a.CreatedAt, &a.UpdatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, model.ErrArticleNotFound
@@ -105,10 +106,11 @@ func (r *Repository) GetByID(ctx context.Context, id uuid.UUID) (*model.Article,
 	return &a, nil
 }
 
+// Update меняет статью автора. Скрытую модератором статью автор не видит и не редактирует - pgx.ErrNoRows.
 func (r *Repository) Update(ctx context.Context, article *model.Article) error {
 	const query = `
 		UPDATE articles SET title = $1, content = $2, updated_at = NOW()
-		WHERE id = $3 AND author_id = $4
+		WHERE id = $3 AND author_id = $4 AND hidden_at IS NULL
 		RETURNING updated_at
 	`
 
@@ -118,8 +120,9 @@ func (r *Repository) Update(ctx context.Context, article *model.Article) error {
 	).Scan(&article.UpdatedAt)
 }
 
+// Delete удаляет статью автора. Скрытую статью удаляет только модератор, чтобы она не пропала до разбора.
 func (r *Repository) Delete(ctx context.Context, id, authorId uuid.UUID) error {
-	const query = `DELETE FROM articles WHERE id = $1 AND author_id = $2`
+	const query = `DELETE FROM articles WHERE id = $1 AND author_id = $2 AND hidden_at IS NULL`
 
 	ct, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, id, authorId)
 	if err != nil {
@@ -133,13 +136,221 @@ func (r *Repository) Delete(ctx context.Context, id, authorId uuid.UUID) error {
 	return nil
 }
 
+// Hide скрывает статью из ленты, выдачи и поиска похожих. Повторное скрытие не ошибка.
+func (r *Repository) Hide(ctx context.Context, id uuid.UUID) error {
+	const query = `UPDATE articles SET hidden_at = COALESCE(hidden_at, NOW()) WHERE id = $1`
+
+	ct, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("hide article: %w", err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return model.ErrArticleNotFound
+	}
+
+	return nil
+}
+
+// Lock блокирует статью, в том числе скрытую, до конца транзакции: параллельное удаление дождётся её.
+func (r *Repository) Lock(ctx context.Context, id uuid.UUID) error {
+	const query = `SELECT 1 FROM articles WHERE id = $1 FOR SHARE`
+
+	var one int
+	err := r.txManager.ExtractExecutor(ctx).QueryRow(ctx, query, id).Scan(&one)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.ErrArticleNotFound
+		}
+		return fmt.Errorf("lock article: %w", err)
+	}
+
+	return nil
+}
+
+// ForceDelete удаляет статью без проверки автора — только для модерации.
+func (r *Repository) ForceDelete(ctx context.Context, id uuid.UUID) error {
+	const query = `DELETE FROM articles WHERE id = $1`
+
+	ct, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("force delete article: %w", err)
+	}
+
+	if ct.RowsAffected() == 0 {
+		return model.ErrArticleNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) ListMostRead(ctx context.Context, limit int) ([]*model.Article, error) {
+	const query = `
+		SELECT id, author_id, title, content, view_count, created_at, updated_at
+		FROM articles
+		WHERE view_count > 0 AND hidden_at IS NULL
+		ORDER BY view_count DESC, id DESC
+		LIMIT $1
+	`
+
+	rows, err := r.txManager.ExtractExecutor(ctx).Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query most read articles: %w", err)
+	}
+	defer rows.Close()
+
+	return scanArticles(rows, limit)
+}
+
+// AddViews прибавляет накопленные просмотры одним запросом на всю пачку.
+func (r *Repository) AddViews(ctx context.Context, views map[uuid.UUID]int64) error {
+	ids := make([]uuid.UUID, 0, len(views))
+	counts := make([]int64, 0, len(views))
+
+	for id, count := range views {
+		ids = append(ids, id)
+		counts = append(counts, count)
+	}
+
+	const query = `
+		UPDATE articles a SET view_count = a.view_count + v.count
+		FROM unnest($1::uuid[], $2::bigint[]) AS v(id, count)
+		WHERE a.id = v.id
+	`
+
+	if _, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, ids, counts); err != nil {
+		return fmt.Errorf("add views: %w", err)
+	}
+
+	return nil
+}
+
+// Export отдаёт статьи автора (uuid.Nil - все статьи) по одной в fn, не загружая выборку в память целиком.
+// Скрытые статьи автору не выгружаются, в резервную копию всех статей попадают.
+func (r *Repository) Export(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error {
+	const query = `
+		SELECT id, author_id, title, content, view_count, created_at, updated_at
+		FROM articles
+		WHERE $1::uuid IS NULL OR (author_id = $1 AND hidden_at IS NULL)
+		ORDER BY created_at, id
+	`
+
+	var author *uuid.UUID
+	if authorID != uuid.Nil {
+		author = &authorID
+	}
+
+	rows, err := r.txManager.ExtractExecutor(ctx).Query(ctx, query, author)
+	if err != nil {
+		return fmt.Errorf("query export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a model.Article
+
+		if err := rows.Scan(&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.ViewCount, &a.CreatedAt, &a.UpdatedAt); err != nil {
+			return fmt.Errorf("scan article: %w", err)
+		}
+
+		if err := fn(&a); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return nil
+}
+
+// CopyArticles вставляет пачку статей через COPY. Пачка либо вставляется целиком, либо не вставляется.
+func (r *Repository) CopyArticles(ctx context.Context, articles []*model.Article) error {
+	columns := []string{"id", "author_id", "title", "content", "created_at", "updated_at"}
+
+	_, err := r.txManager.ExtractExecutor(ctx).CopyFrom(
+		ctx,
+		pgx.Identifier{"articles"},
+		columns,
+		pgx.CopyFromSlice(len(articles), func(i int) ([]any, error) {
+			a := articles[i]
+			return []any{a.ID, a.AuthorID, a.Title, a.Content, a.CreatedAt, a.UpdatedAt}, nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("copy articles: %w", err)
+	}
+
+	return nil
+}
+
+// Веса составляющих сходства: заголовок (триграммы), пересечение лексем текста, тот же автор.
+const (
+	relatedTitleWeight  = 0.4
+	relatedTextWeight   = 0.4
+	relatedAuthorWeight = 0.2
+)
+
+// relatedMaxLexemes - сколько лексем исходной статьи попадает в запрос похожих: с наибольшей суммой весов
+// вхождений (метки A-D как у ts_rank, без setweight все D - то есть самые частые). Длинная статья иначе
+// даёт OR из тысяч лексем.
+const relatedMaxLexemes = 32
+
+// ListRelated ищет статьи, похожие на статью id. Кандидаты — совпадение заголовка по pg_trgm,
+// пересечение лексем search_vector или тот же автор; порядок — взвешенная сумма этих сигналов.
+// Хабов и тегов у статей нет, поэтому сигнала по ним тоже нет.
+func (r *Repository) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error) {
+	const query = `
+		WITH src AS (
+			SELECT id, author_id, title,
+				(
+					SELECT to_tsquery('simple', string_agg(quote_literal(t.lexeme), ' | '))
+					FROM (
+						SELECT l.lexeme
+						FROM unnest(search_vector) AS l(lexeme, positions, weights)
+						-- веса как у ts_rank по умолчанию: D 0.1, C 0.2, B 0.4, A 1.0
+						ORDER BY COALESCE((
+							SELECT sum(CASE w WHEN 'A' THEN 1.0 WHEN 'B' THEN 0.4 WHEN 'C' THEN 0.2 ELSE 0.1 END)
+							FROM unnest(l.weights) AS w
+						), 0) DESC, l.lexeme
+						LIMIT $6
+					) AS t
+				) AS lexemes
+			FROM articles
+			WHERE id = $1
+		)
+		SELECT a.id, a.author_id, a.title, a.content, a.view_count, a.created_at, a.updated_at
+		FROM articles a, src
+		WHERE a.id <> src.id AND a.hidden_at IS NULL
+			AND (a.title % src.title OR a.search_vector @@ src.lexemes OR a.author_id = src.author_id)
+		ORDER BY
+			$2::float8 * similarity(a.title, src.title)
+			+ $3::float8 * COALESCE(ts_rank(a.search_vector, src.lexemes), 0)
+			+ $4::float8 * (a.author_id = src.author_id)::int DESC,
+			a.id DESC
+		LIMIT $5
+	`
+
+	rows, err := r.txManager.ExtractExecutor(ctx).Query(
+		ctx, query,
+		id, relatedTitleWeight, relatedTextWeight, relatedAuthorWeight, limit, relatedMaxLexemes,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query related articles: %w", err)
+	}
+	defer rows.Close()
+
+	return scanArticles(rows, limit)
+}
+
 func scanArticles(rows pgx.Rows, capacity int) ([]*model.Article, error) {
 	articles := make([]*model.Article, 0, capacity+1)
 
 	for rows.Next() {
 		var a model.Article
 
-		if err := rows.Scan(&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		if err := rows.Scan(&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.ViewCount, &a.CreatedAt, &a.UpdatedAt); err != nil {
 			return nil, fmt.Errorf("scan article: %w", err)
 		}
 
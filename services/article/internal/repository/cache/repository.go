@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,7 +14,29 @@ import (
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
-const cacheKey = "articles:first_page"
+const (
+	feedGenerationKey = "feed:gen"
+	feedPageKeyFormat = "feed:%d:%s:%d"
+	articleKeyFormat  = "article:%s"
+	// articleVersionKeyFormat - версия кеша статьи, растёт при каждой инвалидации
+	articleVersionKeyFormat = "article:%s:version"
+	relatedKeyFormat        = "related:%s"
+	// relatedRefsKeyFormat - множество статей, в чьих похожих лежит эта статья
+	relatedRefsKeyFormat = "related:%s:refs"
+)
+
+// deleteArticleScript читает обратные ссылки и удаляет списки по ним одним шагом: SetRelated, пришедший между
+// SMEMBERS и DEL, иначе добавил бы ссылку, которую DEL сотрёт, а его список остался бы со статьёй.
+var deleteArticleScript = redis.NewScript(`
+local refs = redis.call('SMEMBERS', KEYS[3])
+for _, ref in ipairs(refs) do
+	redis.call('DEL', ARGV[2] .. ref)
+end
+redis.call('DEL', KEYS[1], KEYS[2], KEYS[3])
+redis.call('INCR', KEYS[4])
+redis.call('PEXPIRE', KEYS[4], ARGV[1])
+return #refs
+`)
 
 type cachedPage struct {
 	Articles   []cachedArticle `json:"articles"`
@@ -25,6 +48,7 @@ type cachedArticle struct {
 	AuthorID  uuid.UUID `json:"author_id"`
 	Title     string    `json:"title"`
 	Content   string    `json:"content"`
+	ViewCount int64     `json:"view_count"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -38,8 +62,27 @@ func New(client *redis.Client, ttl time.Duration) *Repository {
 	return &Repository{client: client, ttl: ttl}
 }
 
-func (r *Repository) Get(ctx context.Context) (*model.ArticlePage, error) {
-	data, err := r.client.Get(ctx, cacheKey).Bytes()
+// FeedGeneration возвращает текущее поколение ленты. Отсутствие ключа — нулевое поколение.
+func (r *Repository) FeedGeneration(ctx context.Context) (int64, error) {
+	gen, err := r.client.Get(ctx, feedGenerationKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return gen, nil
+}
+
+// BumpFeedGeneration переводит ленту на новое поколение.
+// Страницы старого поколения не удаляются: они доживают по TTL и отдаются как stale, пока пересобирается новое.
+func (r *Repository) BumpFeedGeneration(ctx context.Context) error {
+	return r.client.Incr(ctx, feedGenerationKey).Err()
+}
+
+func (r *Repository) GetPage(ctx context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error) {
+	data, err := r.client.Get(ctx, pageKey(gen, cursor, limit)).Bytes()
 	if errors.Is(err, redis.Nil) {
 		return nil, nil
 	}
@@ -54,14 +97,7 @@ func (r *Repository) Get(ctx context.Context) (*model.ArticlePage, error) {
 
 	articles := make([]*model.Article, len(cached.Articles))
 	for i, a := range cached.Articles {
-		articles[i] = &model.Article{
-			ID:        a.ID,
-			AuthorID:  a.AuthorID,
-			Title:     a.Title,
-			Content:   a.Content,
-			CreatedAt: a.CreatedAt,
-			UpdatedAt: a.UpdatedAt,
-		}
+		articles[i] = fromCachedArticle(a)
 	}
 
 	return &model.ArticlePage{
@@ -70,21 +106,14 @@ func (r *Repository) Get(ctx context.Context) (*model.ArticlePage, error) {
 	}, nil
 }
 
-func (r *Repository) Set(ctx context.Context, page *model.ArticlePage) error {
+func (r *Repository) SetPage(ctx context.Context, gen int64, cursor string, limit int, page *model.ArticlePage) error {
 	cached := cachedPage{
 		Articles:   make([]cachedArticle, len(page.Articles)),
 		NextCursor: page.NextCursor,
 	}
 
 	for i, a := range page.Articles {
-		cached.Articles[i] = cachedArticle{
-			ID:        a.ID,
-			AuthorID:  a.AuthorID,
-			Title:     a.Title,
-			Content:   a.Content,
-			CreatedAt: a.CreatedAt,
-			UpdatedAt: a.UpdatedAt,
-		}
+		cached.Articles[i] = toCachedArticle(a)
 	}
 
 	data, err := json.Marshal(cached)
@@ -92,9 +121,193 @@ func (r *Repository) Set(ctx context.Context, page *model.ArticlePage) error {
 		return fmt.Errorf("marshal cached page: %w", err)
 	}
 
-	return r.client.Set(ctx, cacheKey, data, r.ttl).Err()
+	return r.client.Set(ctx, pageKey(gen, cursor, limit), data, r.ttl).Err()
+}
+
+func (r *Repository) GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error) {
+	data, err := r.client.Get(ctx, articleKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cached cachedArticle
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("unmarshal cached article: %w", err)
+	}
+
+	return fromCachedArticle(cached), nil
+}
+
+// ArticleVersion возвращает версию кеша статьи. Отсутствие ключа — нулевая версия.
+func (r *Repository) ArticleVersion(ctx context.Context, id uuid.UUID) (int64, error) {
+	version, err := r.client.Get(ctx, articleVersionKey(id)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// SetArticle кладёт статью, прочитанную из БД при версии version. Если статью с тех пор инвалидировали,
+// запись пропускается: иначе чтение, начатое до изменения, вернуло бы в кеш устаревшую статью.
+func (r *Repository) SetArticle(ctx context.Context, article *model.Article, version int64) error {
+	data, err := json.Marshal(toCachedArticle(article))
+	if err != nil {
+		return fmt.Errorf("marshal cached article: %w", err)
+	}
+
+	versionKey := articleVersionKey(article.ID)
+	err = r.client.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, versionKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != version {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, articleKey(article.ID), data, r.ttl)
+			return nil
+		})
+		return err
+	}, versionKey)
+	// версию сменили между WATCH и EXEC - статья уже устарела
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil
+	}
+
+	return err
+}
+
+// DeleteArticle удаляет всё, что закешировано по статье: саму статью, её похожие и похожие других статей,
+// в которые она попала, и поднимает версию, чтобы незавершённые заполнения кеша не записали статью,
+// прочитанную до изменения.
+func (r *Repository) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+	return deleteArticleScript.Run(
+		ctx, r.client,
+		[]string{articleKey(id), relatedKey(id), relatedRefsKey(id), articleVersionKey(id)},
+		r.ttl.Milliseconds(), fmt.Sprintf(relatedKeyFormat, ""),
+	).Err()
+}
+
+// DropArticles удаляет закешированные статьи и поднимает их версии, похожие не трогает. Нужен после сброса
+// просмотров в БД: view_count в кеше устарел, а списки похожих от просмотров не зависят.
+func (r *Repository) DropArticles(ctx context.Context, ids []uuid.UUID) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			versionKey := articleVersionKey(id)
+			pipe.Incr(ctx, versionKey)
+			pipe.PExpire(ctx, versionKey, r.ttl)
+			pipe.Del(ctx, articleKey(id))
+		}
+		return nil
+	})
+
+	return err
+}
+
+// GetRelated читает похожие статьи. Все limit одной статьи лежат в одном хеше, чтобы инвалидировать их одним DEL.
+func (r *Repository) GetRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error) {
+	data, err := r.client.HGet(ctx, relatedKey(id), strconv.Itoa(limit)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cached []cachedArticle
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil, fmt.Errorf("unmarshal cached related: %w", err)
+	}
+
+	articles := make([]*model.Article, len(cached))
+	for i, a := range cached {
+		articles[i] = fromCachedArticle(a)
+	}
+
+	return articles, nil
+}
+
+func (r *Repository) SetRelated(ctx context.Context, id uuid.UUID, limit int, articles []*model.Article) error {
+	cached := make([]cachedArticle, len(articles))
+	for i, a := range articles {
+		cached[i] = toCachedArticle(a)
+	}
+
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("marshal cached related: %w", err)
+	}
+
+	key := relatedKey(id)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, strconv.Itoa(limit), data)
+		pipe.Expire(ctx, key, r.ttl)
+		// обратные ссылки: удаление или скрытие статьи из списка сбросит и этот список
+		for _, a := range articles {
+			refs := relatedRefsKey(a.ID)
+			pipe.SAdd(ctx, refs, id.String())
+			pipe.Expire(ctx, refs, r.ttl)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// PageKey - ключ страницы ленты поколения gen, им же сервис схлопывает параллельные пересборки страницы.
+func (r *Repository) PageKey(gen int64, cursor string, limit int) string {
+	return pageKey(gen, cursor, limit)
+}
+
+func pageKey(gen int64, cursor string, limit int) string {
+	return fmt.Sprintf(feedPageKeyFormat, gen, cursor, limit)
+}
+
+func articleKey(id uuid.UUID) string {
+	return fmt.Sprintf(articleKeyFormat, id.String())
+}
+
+func articleVersionKey(id uuid.UUID) string {
+	return fmt.Sprintf(articleVersionKeyFormat, id.String())
 }
 
-func (r *Repository) Invalidate(ctx context.Context) error {
-	return r.client.Del(ctx, cacheKey).Err()
+func relatedKey(id uuid.UUID) string {
+	return fmt.Sprintf(relatedKeyFormat, id.String())
+}
+
+func relatedRefsKey(id uuid.UUID) string {
+	return fmt.Sprintf(relatedRefsKeyFormat, id.String())
+}
+
+func toCachedArticle(a *model.Article) cachedArticle {
+	return cachedArticle{
+		ID:        a.ID,
+		AuthorID:  a.AuthorID,
+		Title:     a.Title,
+		Content:   a.Content,
+		ViewCount: a.ViewCount,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
+
+func fromCachedArticle(a cachedArticle) *model.Article {
+	return &model.Article{
+		ID:        a.ID,
+		AuthorID:  a.AuthorID,
+		Title:     a.Title,
+		Content:   a.Content,
+		ViewCount: a.ViewCount,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
 }
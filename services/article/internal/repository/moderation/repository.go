@@ -0,0 +1,114 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/transaction"
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+// queueReasonsLimit - сколько последних причин жалоб показывается в очереди модерации.
+const queueReasonsLimit = 5
+
+type Repository struct {
+	txManager *transaction.Manager
+}
+
+func New(txManager *transaction.Manager) *Repository {
+	return &Repository{txManager: txManager}
+}
+
+// CreateReport сохраняет жалобу. Повторная жалоба того же пользователя, пока открыта предыдущая, игнорируется.
+func (r *Repository) CreateReport(ctx context.Context, report *model.Report) error {
+	const query = `
+		INSERT INTO article_reports (id, article_id, reporter_id, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (article_id, reporter_id) WHERE resolved_at IS NULL DO NOTHING
+	`
+
+	_, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, report.ID, report.ArticleID, report.ReporterID, report.Reason)
+	if err != nil {
+		return fmt.Errorf("create report: %w", err)
+	}
+
+	return nil
+}
+
+// ResolveReports закрывает открытые жалобы на статью — статья уходит из очереди модерации.
+func (r *Repository) ResolveReports(ctx context.Context, articleID uuid.UUID) error {
+	const query = `UPDATE article_reports SET resolved_at = NOW() WHERE article_id = $1 AND resolved_at IS NULL`
+
+	if _, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, articleID); err != nil {
+		return fmt.Errorf("resolve reports: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Repository) CreateRecord(ctx context.Context, record *model.ModerationRecord) error {
+	const query = `
+		INSERT INTO moderation_log (id, article_id, moderator_id, action, reason)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.txManager.ExtractExecutor(ctx).Exec(
+		ctx, query,
+		record.ID, record.ArticleID, record.ModeratorID, string(record.Action), record.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("create moderation record: %w", err)
+	}
+
+	return nil
+}
+
+// ListQueue - статьи с открытыми жалобами: сначала с наибольшим числом жалоб, при равенстве — ждущие дольше.
+func (r *Repository) ListQueue(ctx context.Context, limit int) ([]*model.ReportedArticle, error) {
+	const query = `
+		SELECT a.id, a.author_id, a.title, a.content, a.view_count, a.created_at, a.updated_at,
+			COUNT(*) AS report_count,
+			MIN(rep.created_at) AS first_reported_at,
+			(array_agg(rep.reason ORDER BY rep.created_at DESC))[1:$2] AS reasons
+		FROM article_reports rep
+		JOIN articles a ON a.id = rep.article_id
+		WHERE rep.resolved_at IS NULL
+		GROUP BY a.id
+		ORDER BY report_count DESC, first_reported_at
+		LIMIT $1
+	`
+
+	rows, err := r.txManager.ExtractExecutor(ctx).Query(ctx, query, limit, queueReasonsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("query moderation queue: %w", err)
+	}
+	defer rows.Close()
+
+	queue := make([]*model.ReportedArticle, 0, limit)
+
+	for rows.Next() {
+		var (
+			a    model.Article
+			item model.ReportedArticle
+		)
+
+		err := rows.Scan(
+			&a.ID, &a.AuthorID, &a.Title, &a.Content, &a.ViewCount, &a.CreatedAt, &a.UpdatedAt,
+			&item.ReportCount, &item.FirstReportedAt, &item.Reasons,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan reported article: %w", err)
+		}
+
+		item.Article = &a
+		queue = append(queue, &item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return queue, nil
+}
@@ -0,0 +1,97 @@
+package view
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	pendingKey        = "views:pending"
+	visitorsKeyFormat = "views:%s:%d"
+)
+
+// recordScript учитывает просмотр только если читатель ещё не попадал в HyperLogLog текущего окна.
+// PFADD возвращает 1, когда оценка кардинальности изменилась — это и есть новый уникальный просмотр.
+var recordScript = redis.NewScript(`
+if redis.call('PFADD', KEYS[1], ARGV[1]) == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[2])
+	redis.call('HINCRBY', KEYS[2], ARGV[3], 1)
+	return 1
+end
+return 0
+`)
+
+// drainScript забирает накопленные счётчики и обнуляет их атомарно, чтобы не потерять просмотры между HGETALL и DEL.
+var drainScript = redis.NewScript(`
+local views = redis.call('HGETALL', KEYS[1])
+redis.call('DEL', KEYS[1])
+return views
+`)
+
+type Repository struct {
+	client *redis.Client
+	window time.Duration
+}
+
+func New(client *redis.Client, window time.Duration) *Repository {
+	return &Repository{client: client, window: window}
+}
+
+// Record учитывает просмотр статьи читателем. Повторные просмотры в пределах окна не считаются.
+func (r *Repository) Record(ctx context.Context, articleID uuid.UUID, viewerID string) (bool, error) {
+	bucket := time.Now().UnixNano() / int64(r.window)
+	visitorsKey := fmt.Sprintf(visitorsKeyFormat, articleID.String(), bucket)
+
+	counted, err := recordScript.Run(
+		ctx, r.client,
+		[]string{visitorsKey, pendingKey},
+		viewerID, max(int64(r.window/time.Second), 1), articleID.String(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return counted == 1, nil
+}
+
+// Drain возвращает накопленные с прошлого сброса просмотры и обнуляет их.
+func (r *Repository) Drain(ctx context.Context) (map[uuid.UUID]int64, error) {
+	raw, err := drainScript.Run(ctx, r.client, []string{pendingKey}).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	views := make(map[uuid.UUID]int64, len(raw)/2)
+	for i := 0; i+1 < len(raw); i += 2 {
+		id, err := uuid.Parse(raw[i])
+		if err != nil {
+			return nil, fmt.Errorf("parse article id %q: %w", raw[i], err)
+		}
+
+		count, err := strconv.ParseInt(raw[i+1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse view count %q: %w", raw[i+1], err)
+		}
+
+		views[id] = count
+	}
+
+	return views, nil
+}
+
+// Restore возвращает просмотры обратно в буфер, если сброс в БД не удался.
+func (r *Repository) Restore(ctx context.Context, views map[uuid.UUID]int64) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for id, count := range views {
+			pipe.HIncrBy(ctx, pendingKey, id.String(), count)
+		}
+		return nil
+	})
+
+	return err
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/google/uuid"
 
-	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
@@ -20,10 +19,7 @@ func (s *Service) CreateArticle(ctx context.Context, authorID uuid.UUID, title,
 		return nil, fmt.Errorf("save article: %w", err)
 	}
 
-	if err := s.cacheRepo.Invalidate(ctx); err != nil {
-		log := logger.Ctx(ctx)
-		log.Warn().Err(err).Msg("cache invalidate failed")
-	}
+	s.invalidateFeed(ctx)
 
 	return article, nil
 }
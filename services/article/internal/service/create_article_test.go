@@ -153,3 +153,25 @@ func TestCreateArticle_RepoError(t *testing.T) {
 		t.Errorf("error = %v, want %v", err, repoErr)
 	}
 }
+
+func TestCreateArticle_BumpsFeedGeneration(t *testing.T) {
+	var bumped bool
+
+	repo := &mockArticleRepo{
+		createFn: func(_ context.Context, _ *model.Article) error { return nil },
+	}
+	cache := defaultCacheRepo()
+	cache.bumpFn = func(_ context.Context) error {
+		bumped = true
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	if _, err := svc.CreateArticle(context.Background(), uuid.Must(uuid.NewV7()), "title", "content"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !bumped {
+		t.Error("cache.BumpFeedGeneration was not called")
+	}
+}
@@ -5,8 +5,6 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-
-	"github.com/SonOfSteveJobs/habr/pkg/logger"
 )
 
 func (s *Service) DeleteArticle(ctx context.Context, id, authorID uuid.UUID) error {
@@ -14,10 +12,7 @@ func (s *Service) DeleteArticle(ctx context.Context, id, authorID uuid.UUID) err
 		return fmt.Errorf("delete article: %w", err)
 	}
 
-	if err := s.cacheRepo.Invalidate(ctx); err != nil {
-		log := logger.Ctx(ctx)
-		log.Warn().Err(err).Msg("cache invalidate failed")
-	}
+	s.invalidateArticle(ctx, id)
 
 	return nil
 }
@@ -68,12 +68,12 @@ func TestDeleteArticle_CacheInvalidateErrorNonFatal(t *testing.T) {
 	repo := &mockArticleRepo{
 		deleteFn: func(_ context.Context, _, _ uuid.UUID) error { return nil },
 	}
-	cache := &mockCacheRepo{
-		getFn: func(_ context.Context) (*model.ArticlePage, error) { return nil, nil },
-		setFn: func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error {
-			return errors.New("redis connection refused")
-		},
+	cache := defaultCacheRepo()
+	cache.bumpFn = func(_ context.Context) error {
+		return errors.New("redis connection refused")
+	}
+	cache.deleteArticleFn = func(_ context.Context, _ uuid.UUID) error {
+		return errors.New("redis connection refused")
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+// ExportArticles передаёт в fn статьи автора по одной. uuid.Nil - все статьи.
+func (s *Service) ExportArticles(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error {
+	if err := s.articleRepo.Export(ctx, authorID, fn); err != nil {
+		return fmt.Errorf("export articles: %w", err)
+	}
+
+	return nil
+}
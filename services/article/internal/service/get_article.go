@@ -6,14 +6,66 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
-func (s *Service) GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error) {
-	article, err := s.articleRepo.GetByID(ctx, id)
+// GetArticle отдаёт статью и учитывает просмотр. Пустой viewerID — просмотр не учитывается.
+func (s *Service) GetArticle(ctx context.Context, id uuid.UUID, viewerID string) (*model.Article, error) {
+	article, err := s.getArticle(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("get article: %w", err)
+		return nil, err
+	}
+
+	if viewerID != "" {
+		// просмотр — не критичная часть чтения, ошибка Redis не должна отдавать клиенту ошибку
+		if _, err := s.viewRepo.Record(ctx, id, viewerID); err != nil {
+			log := logger.Ctx(ctx)
+			log.Warn().Err(err).Msg("record view failed")
+		}
 	}
 
 	return article, nil
 }
+
+func (s *Service) getArticle(ctx context.Context, id uuid.UUID) (*model.Article, error) {
+	article, err := s.cacheRepo.GetArticle(ctx, id)
+	if err != nil {
+		log := logger.Ctx(ctx)
+		log.Warn().Err(err).Msg("cache get article failed")
+	}
+	if article != nil {
+		return article, nil
+	}
+
+	v, err, _ := s.group.Do("article:"+id.String(), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		log := logger.Ctx(ctx)
+
+		// версия читается до БД: если статью изменят, пока идёт чтение, устаревшая копия не попадёт в кеш
+		version, versionErr := s.cacheRepo.ArticleVersion(ctx, id)
+		if versionErr != nil {
+			log.Warn().Err(versionErr).Msg("cache article version get failed")
+		}
+
+		article, err := s.articleRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get article: %w", err)
+		}
+
+		if versionErr == nil {
+			if err := s.cacheRepo.SetArticle(ctx, article, version); err != nil {
+				log.Warn().Err(err).Msg("cache set article failed")
+			}
+		}
+
+		return article, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*model.Article), nil
+}
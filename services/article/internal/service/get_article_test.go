@@ -33,7 +33,7 @@ func TestGetArticle_Success(t *testing.T) {
 	}
 	svc := newTestService(repo)
 
-	article, err := svc.GetArticle(context.Background(), articleID)
+	article, err := svc.GetArticle(context.Background(), articleID, "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -59,7 +59,7 @@ func TestGetArticle_NotFound(t *testing.T) {
 	}
 	svc := newTestService(repo)
 
-	_, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()))
+	_, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), "")
 	if !errors.Is(err, model.ErrArticleNotFound) {
 		t.Errorf("error = %v, want ErrArticleNotFound", err)
 	}
@@ -74,8 +74,219 @@ func TestGetArticle_RepoError(t *testing.T) {
 	}
 	svc := newTestService(repo)
 
-	_, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()))
+	_, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), "")
 	if !errors.Is(err, repoErr) {
 		t.Errorf("error = %v, want %v", err, repoErr)
 	}
 }
+
+func TestGetArticle_CacheHit(t *testing.T) {
+	cached := &model.Article{
+		ID:    uuid.Must(uuid.NewV7()),
+		Title: "Cached Title",
+	}
+
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*model.Article, error) {
+			t.Error("repo.GetByID was called, want cache hit")
+			return nil, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.getArticleFn = func(_ context.Context, _ uuid.UUID) (*model.Article, error) { return cached, nil }
+	svc := newTestServiceWithCache(repo, cache)
+
+	article, err := svc.GetArticle(context.Background(), cached.ID, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if article.Title != "Cached Title" {
+		t.Errorf("article.Title = %q, want %q", article.Title, "Cached Title")
+	}
+}
+
+func TestGetArticle_CacheMissStoresArticle(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	var stored *model.Article
+
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id, Title: "From DB"}, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.setArticleFn = func(_ context.Context, a *model.Article, _ int64) error {
+		stored = a
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	if _, err := svc.GetArticle(context.Background(), articleID, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored == nil || stored.ID != articleID {
+		t.Errorf("cache.SetArticle got %v, want article %v", stored, articleID)
+	}
+}
+
+func TestGetArticle_CacheFillUsesVersionBeforeRead(t *testing.T) {
+	version := int64(3)
+	var storedVersion int64
+
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			// инвалидация во время чтения из БД
+			version++
+			return &model.Article{ID: id}, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.versionFn = func(_ context.Context, _ uuid.UUID) (int64, error) { return version, nil }
+	cache.setArticleFn = func(_ context.Context, _ *model.Article, v int64) error {
+		storedVersion = v
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	if _, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if storedVersion != 3 {
+		t.Errorf("SetArticle version = %d, want version read before the DB (3)", storedVersion)
+	}
+}
+
+func TestGetArticle_VersionErrorSkipsCacheFill(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.versionFn = func(_ context.Context, _ uuid.UUID) (int64, error) {
+		return 0, errors.New("redis connection refused")
+	}
+	cache.setArticleFn = func(_ context.Context, _ *model.Article, _ int64) error {
+		t.Error("SetArticle called without a known version")
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	if _, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetArticle_CacheErrorFallbackToDB(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.getArticleFn = func(_ context.Context, _ uuid.UUID) (*model.Article, error) {
+		return nil, errors.New("redis connection refused")
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	if _, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !repo.getByIDCalled {
+		t.Error("repo.GetByID was not called after cache error")
+	}
+}
+
+func TestGetArticle_RecordsView(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	var (
+		gotArticleID uuid.UUID
+		gotViewer    string
+	)
+
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+	views := &mockViewRepo{
+		recordFn: func(_ context.Context, id uuid.UUID, viewerID string) (bool, error) {
+			gotArticleID = id
+			gotViewer = viewerID
+			return true, nil
+		},
+	}
+	svc := newTestServiceWithViews(repo, views)
+
+	if _, err := svc.GetArticle(context.Background(), articleID, "ip:10.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotArticleID != articleID {
+		t.Errorf("view article id = %v, want %v", gotArticleID, articleID)
+	}
+	if gotViewer != "ip:10.0.0.1" {
+		t.Errorf("viewer = %q, want %q", gotViewer, "ip:10.0.0.1")
+	}
+}
+
+func TestGetArticle_EmptyViewerSkipsView(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+	views := &mockViewRepo{
+		recordFn: func(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+			t.Error("viewRepo.Record was called, want skipped for empty viewer")
+			return false, nil
+		},
+	}
+	svc := newTestServiceWithViews(repo, views)
+
+	if _, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetArticle_NotFoundSkipsView(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*model.Article, error) {
+			return nil, model.ErrArticleNotFound
+		},
+	}
+	views := &mockViewRepo{
+		recordFn: func(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+			t.Error("viewRepo.Record was called, want skipped for missing article")
+			return false, nil
+		},
+	}
+	svc := newTestServiceWithViews(repo, views)
+
+	_, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), "ip:10.0.0.1")
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+}
+
+func TestGetArticle_RecordViewErrorNonFatal(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+	views := &mockViewRepo{
+		recordFn: func(_ context.Context, _ uuid.UUID, _ string) (bool, error) {
+			return false, errors.New("redis connection refused")
+		},
+	}
+	svc := newTestServiceWithViews(repo, views)
+
+	if _, err := svc.GetArticle(context.Background(), uuid.Must(uuid.NewV7()), "ip:10.0.0.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+const (
+	defaultRelatedLimit = 5
+	maxRelatedLimit     = 50
+)
+
+// GetRelatedArticles считает похожие статьи лениво: при первом запросе и после редактирования статьи.
+func (s *Service) GetRelatedArticles(ctx context.Context, id uuid.UUID, limit int32) ([]*model.Article, error) {
+	l := int(limit)
+	if l <= 0 {
+		l = defaultRelatedLimit
+	}
+	if l > maxRelatedLimit {
+		l = maxRelatedLimit
+	}
+
+	log := logger.Ctx(ctx)
+
+	related, err := s.cacheRepo.GetRelated(ctx, id, l)
+	if err != nil {
+		log.Warn().Err(err).Msg("cache get related failed")
+	}
+	if related != nil {
+		return related, nil
+	}
+
+	// статья должна существовать: пустой список и "нет такой статьи" — разные ответы
+	if _, err := s.getArticle(ctx, id); err != nil {
+		return nil, err
+	}
+
+	v, err, _ := s.group.Do(fmt.Sprintf("related:%s:%d", id, l), func() (any, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+		defer cancel()
+
+		related, err := s.articleRepo.ListRelated(ctx, id, l)
+		if err != nil {
+			return nil, fmt.Errorf("list related: %w", err)
+		}
+
+		if err := s.cacheRepo.SetRelated(ctx, id, l, related); err != nil {
+			log := logger.Ctx(ctx)
+			log.Warn().Err(err).Msg("cache set related failed")
+		}
+
+		return related, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.([]*model.Article), nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func existingArticleRepo() *mockArticleRepo {
+	return &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+}
+
+func TestGetRelatedArticles_CacheHit(t *testing.T) {
+	cached := []*model.Article{{ID: uuid.Must(uuid.NewV7()), Title: "Related"}}
+
+	repo := existingArticleRepo()
+	repo.listRelatedFn = func(_ context.Context, _ uuid.UUID, _ int) ([]*model.Article, error) {
+		t.Error("repo.ListRelated was called, want cache hit")
+		return nil, nil
+	}
+	cache := defaultCacheRepo()
+	cache.getRelatedFn = func(_ context.Context, _ uuid.UUID, _ int) ([]*model.Article, error) { return cached, nil }
+	svc := newTestServiceWithCache(repo, cache)
+
+	related, err := svc.GetRelatedArticles(context.Background(), uuid.Must(uuid.NewV7()), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(related) != 1 || related[0].Title != "Related" {
+		t.Errorf("related = %v, want cached list", related)
+	}
+}
+
+func TestGetRelatedArticles_CacheMissComputesAndStores(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	computed := []*model.Article{{ID: uuid.Must(uuid.NewV7())}, {ID: uuid.Must(uuid.NewV7())}}
+	var stored []*model.Article
+
+	repo := existingArticleRepo()
+	repo.listRelatedFn = func(_ context.Context, id uuid.UUID, limit int) ([]*model.Article, error) {
+		if id != articleID {
+			t.Errorf("id = %v, want %v", id, articleID)
+		}
+		if limit != 3 {
+			t.Errorf("limit = %d, want 3", limit)
+		}
+		return computed, nil
+	}
+	cache := defaultCacheRepo()
+	cache.setRelatedFn = func(_ context.Context, _ uuid.UUID, _ int, articles []*model.Article) error {
+		stored = articles
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	related, err := svc.GetRelatedArticles(context.Background(), articleID, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(related) != 2 {
+		t.Errorf("related count = %d, want 2", len(related))
+	}
+	if len(stored) != 2 {
+		t.Errorf("cache.SetRelated got %d articles, want 2", len(stored))
+	}
+}
+
+func TestGetRelatedArticles_LimitBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int32
+		want  int
+	}{
+		{"default", 0, defaultRelatedLimit},
+		{"above max", 500, maxRelatedLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := existingArticleRepo()
+			repo.listRelatedFn = func(_ context.Context, _ uuid.UUID, limit int) ([]*model.Article, error) {
+				if limit != tt.want {
+					t.Errorf("limit = %d, want %d", limit, tt.want)
+				}
+				return nil, nil
+			}
+			svc := newTestService(repo)
+
+			if _, err := svc.GetRelatedArticles(context.Background(), uuid.Must(uuid.NewV7()), tt.limit); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetRelatedArticles_ArticleNotFound(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*model.Article, error) {
+			return nil, model.ErrArticleNotFound
+		},
+	}
+	svc := newTestService(repo)
+
+	_, err := svc.GetRelatedArticles(context.Background(), uuid.Must(uuid.NewV7()), 5)
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+	if repo.listRelatedCalled {
+		t.Error("repo.ListRelated was called for missing article")
+	}
+}
+
+func TestGetRelatedArticles_RepoError(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	repo := existingArticleRepo()
+	repo.listRelatedFn = func(_ context.Context, _ uuid.UUID, _ int) ([]*model.Article, error) { return nil, repoErr }
+	svc := newTestService(repo)
+
+	_, err := svc.GetRelatedArticles(context.Background(), uuid.Must(uuid.NewV7()), 5)
+	if !errors.Is(err, repoErr) {
+		t.Errorf("error = %v, want %v", err, repoErr)
+	}
+}
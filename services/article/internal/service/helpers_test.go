@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 
@@ -23,6 +24,20 @@ type mockArticleRepo struct {
 
 	deleteFn     func(ctx context.Context, id, authorID uuid.UUID) error
 	deleteCalled bool
+
+	listMostReadFn func(ctx context.Context, limit int) ([]*model.Article, error)
+
+	listRelatedFn     func(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error)
+	listRelatedCalled bool
+
+	exportFn func(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error
+
+	copyFn     func(ctx context.Context, articles []*model.Article) error
+	copyCalled bool
+
+	hideFn        func(ctx context.Context, id uuid.UUID) error
+	lockFn        func(ctx context.Context, id uuid.UUID) error
+	forceDeleteFn func(ctx context.Context, id uuid.UUID) error
 }
 
 func (m *mockArticleRepo) Create(ctx context.Context, article *model.Article) error {
@@ -50,22 +65,99 @@ func (m *mockArticleRepo) Delete(ctx context.Context, id, authorID uuid.UUID) er
 	return m.deleteFn(ctx, id, authorID)
 }
 
+func (m *mockArticleRepo) ListMostRead(ctx context.Context, limit int) ([]*model.Article, error) {
+	return m.listMostReadFn(ctx, limit)
+}
+
+func (m *mockArticleRepo) ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error) {
+	m.listRelatedCalled = true
+	return m.listRelatedFn(ctx, id, limit)
+}
+
+func (m *mockArticleRepo) Export(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error {
+	return m.exportFn(ctx, authorID, fn)
+}
+
+func (m *mockArticleRepo) CopyArticles(ctx context.Context, articles []*model.Article) error {
+	m.copyCalled = true
+	return m.copyFn(ctx, articles)
+}
+
+func (m *mockArticleRepo) Hide(ctx context.Context, id uuid.UUID) error {
+	return m.hideFn(ctx, id)
+}
+
+func (m *mockArticleRepo) Lock(ctx context.Context, id uuid.UUID) error {
+	return m.lockFn(ctx, id)
+}
+
+func (m *mockArticleRepo) ForceDelete(ctx context.Context, id uuid.UUID) error {
+	return m.forceDeleteFn(ctx, id)
+}
+
 type mockCacheRepo struct {
-	getFn        func(ctx context.Context) (*model.ArticlePage, error)
-	setFn        func(ctx context.Context, page *model.ArticlePage) error
-	invalidateFn func(ctx context.Context) error
+	genFn           func(ctx context.Context) (int64, error)
+	bumpFn          func(ctx context.Context) error
+	getPageFn       func(ctx context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error)
+	setPageFn       func(ctx context.Context, gen int64, cursor string, limit int, page *model.ArticlePage) error
+	getArticleFn    func(ctx context.Context, id uuid.UUID) (*model.Article, error)
+	versionFn       func(ctx context.Context, id uuid.UUID) (int64, error)
+	setArticleFn    func(ctx context.Context, article *model.Article, version int64) error
+	deleteArticleFn func(ctx context.Context, id uuid.UUID) error
+	getRelatedFn    func(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error)
+	setRelatedFn    func(ctx context.Context, id uuid.UUID, limit int, articles []*model.Article) error
+}
+
+func (m *mockCacheRepo) FeedGeneration(ctx context.Context) (int64, error) {
+	return m.genFn(ctx)
+}
+
+func (m *mockCacheRepo) BumpFeedGeneration(ctx context.Context) error {
+	return m.bumpFn(ctx)
 }
 
-func (m *mockCacheRepo) Get(ctx context.Context) (*model.ArticlePage, error) {
-	return m.getFn(ctx)
+func (m *mockCacheRepo) GetPage(ctx context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error) {
+	return m.getPageFn(ctx, gen, cursor, limit)
 }
 
-func (m *mockCacheRepo) Set(ctx context.Context, page *model.ArticlePage) error {
-	return m.setFn(ctx, page)
+func (m *mockCacheRepo) SetPage(ctx context.Context, gen int64, cursor string, limit int, page *model.ArticlePage) error {
+	return m.setPageFn(ctx, gen, cursor, limit, page)
 }
 
-func (m *mockCacheRepo) Invalidate(ctx context.Context) error {
-	return m.invalidateFn(ctx)
+func (m *mockCacheRepo) PageKey(gen int64, cursor string, limit int) string {
+	return fmt.Sprintf("%d:%s:%d", gen, cursor, limit)
+}
+
+func (m *mockCacheRepo) GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error) {
+	return m.getArticleFn(ctx, id)
+}
+
+func (m *mockCacheRepo) ArticleVersion(ctx context.Context, id uuid.UUID) (int64, error) {
+	return m.versionFn(ctx, id)
+}
+
+func (m *mockCacheRepo) SetArticle(ctx context.Context, article *model.Article, version int64) error {
+	return m.setArticleFn(ctx, article, version)
+}
+
+func (m *mockCacheRepo) DeleteArticle(ctx context.Context, id uuid.UUID) error {
+	return m.deleteArticleFn(ctx, id)
+}
+
+func (m *mockCacheRepo) GetRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error) {
+	return m.getRelatedFn(ctx, id, limit)
+}
+
+func (m *mockCacheRepo) SetRelated(ctx context.Context, id uuid.UUID, limit int, articles []*model.Article) error {
+	return m.setRelatedFn(ctx, id, limit, articles)
+}
+
+type mockViewRepo struct {
+	recordFn func(ctx context.Context, articleID uuid.UUID, viewerID string) (bool, error)
+}
+
+func (m *mockViewRepo) Record(ctx context.Context, articleID uuid.UUID, viewerID string) (bool, error) {
+	return m.recordFn(ctx, articleID, viewerID)
 }
 
 type mockTxManager struct{}
@@ -76,16 +168,75 @@ func (m *mockTxManager) Wrap(ctx context.Context, fn func(ctx context.Context) e
 
 func defaultCacheRepo() *mockCacheRepo {
 	return &mockCacheRepo{
-		getFn:        func(_ context.Context) (*model.ArticlePage, error) { return nil, nil },
-		setFn:        func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error { return nil },
+		genFn:  func(_ context.Context) (int64, error) { return 0, nil },
+		bumpFn: func(_ context.Context) error { return nil },
+		getPageFn: func(_ context.Context, _ int64, _ string, _ int) (*model.ArticlePage, error) {
+			return nil, nil
+		},
+		setPageFn:       func(_ context.Context, _ int64, _ string, _ int, _ *model.ArticlePage) error { return nil },
+		getArticleFn:    func(_ context.Context, _ uuid.UUID) (*model.Article, error) { return nil, nil },
+		versionFn:       func(_ context.Context, _ uuid.UUID) (int64, error) { return 0, nil },
+		setArticleFn:    func(_ context.Context, _ *model.Article, _ int64) error { return nil },
+		deleteArticleFn: func(_ context.Context, _ uuid.UUID) error { return nil },
+		getRelatedFn: func(_ context.Context, _ uuid.UUID, _ int) ([]*model.Article, error) {
+			return nil, nil
+		},
+		setRelatedFn: func(_ context.Context, _ uuid.UUID, _ int, _ []*model.Article) error { return nil },
+	}
+}
+
+func defaultViewRepo() *mockViewRepo {
+	return &mockViewRepo{
+		recordFn: func(_ context.Context, _ uuid.UUID, _ string) (bool, error) { return true, nil },
+	}
+}
+
+type mockModerationRepo struct {
+	createReportFn   func(ctx context.Context, report *model.Report) error
+	resolveReportsFn func(ctx context.Context, articleID uuid.UUID) error
+	createRecordFn   func(ctx context.Context, record *model.ModerationRecord) error
+	listQueueFn      func(ctx context.Context, limit int) ([]*model.ReportedArticle, error)
+}
+
+func (m *mockModerationRepo) CreateReport(ctx context.Context, report *model.Report) error {
+	return m.createReportFn(ctx, report)
+}
+
+func (m *mockModerationRepo) ResolveReports(ctx context.Context, articleID uuid.UUID) error {
+	return m.resolveReportsFn(ctx, articleID)
+}
+
+func (m *mockModerationRepo) CreateRecord(ctx context.Context, record *model.ModerationRecord) error {
+	return m.createRecordFn(ctx, record)
+}
+
+func (m *mockModerationRepo) ListQueue(ctx context.Context, limit int) ([]*model.ReportedArticle, error) {
+	return m.listQueueFn(ctx, limit)
+}
+
+func defaultModerationRepo() *mockModerationRepo {
+	return &mockModerationRepo{
+		createReportFn:   func(_ context.Context, _ *model.Report) error { return nil },
+		resolveReportsFn: func(_ context.Context, _ uuid.UUID) error { return nil },
+		createRecordFn:   func(_ context.Context, _ *model.ModerationRecord) error { return nil },
+		listQueueFn: func(_ context.Context, _ int) ([]*model.ReportedArticle, error) {
+			return nil, nil
+		},
 	}
 }
 
 func newTestService(repo *mockArticleRepo) *Service {
-	return New(repo, defaultCacheRepo(), &mockTxManager{})
+	return New(repo, defaultCacheRepo(), defaultViewRepo(), defaultModerationRepo(), &mockTxManager{})
 }
 
 func newTestServiceWithCache(repo *mockArticleRepo, cache *mockCacheRepo) *Service {
-	return New(repo, cache, &mockTxManager{})
+	return New(repo, cache, defaultViewRepo(), defaultModerationRepo(), &mockTxManager{})
+}
+
+func newTestServiceWithViews(repo *mockArticleRepo, views *mockViewRepo) *Service {
+	return New(repo, defaultCacheRepo(), views, defaultModerationRepo(), &mockTxManager{})
+}
+
+func newTestServiceWithModeration(repo *mockArticleRepo, moderation *mockModerationRepo) *Service {
+	return New(repo, defaultCacheRepo(), defaultViewRepo(), moderation, &mockTxManager{})
 }
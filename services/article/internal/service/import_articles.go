@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+// importClockSkew - насколько created_at импорта может опережать часы сервиса. Лента упорядочена по created_at,
+// и статья из будущего навсегда осталась бы первой.
+const importClockSkew = 5 * time.Minute
+
+// ImportBatch проверяет статьи пачки через model.NewArticle и вставляет прошедшие проверку одним COPY.
+// Невалидные статьи не мешают остальным; ошибка БД отклоняет всю пачку. offset - номер первой статьи пачки в потоке.
+func (s *Service) ImportBatch(ctx context.Context, authorID uuid.UUID, offset int, items []model.ImportItem) []model.ImportResult {
+	results := make([]model.ImportResult, len(items))
+	articles := make([]*model.Article, 0, len(items))
+	positions := make([]int, 0, len(items))
+	now := time.Now()
+
+	for i, item := range items {
+		results[i].Index = offset + i
+
+		article, err := model.NewArticle(authorID, item.Title, item.Content)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		if item.CreatedAt.After(now.Add(importClockSkew)) {
+			results[i].Err = model.ErrInvalidCreatedAt
+			continue
+		}
+
+		article.CreatedAt = now
+		if !item.CreatedAt.IsZero() {
+			article.CreatedAt = item.CreatedAt
+		}
+		article.UpdatedAt = now
+
+		articles = append(articles, article)
+		positions = append(positions, i)
+	}
+
+	if len(articles) == 0 {
+		return results
+	}
+
+	if err := s.articleRepo.CopyArticles(ctx, articles); err != nil {
+		log := logger.Ctx(ctx)
+		log.Error().Err(err).Int("batch", len(articles)).Msg("import batch failed")
+
+		for _, pos := range positions {
+			results[pos].Err = err
+		}
+
+		return results
+	}
+
+	for i, pos := range positions {
+		results[pos].ArticleID = articles[i].ID
+	}
+
+	s.invalidateFeed(ctx)
+
+	return results
+}
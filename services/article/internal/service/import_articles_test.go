@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func TestImportBatch_Success(t *testing.T) {
+	createdAt := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	authorID := uuid.Must(uuid.NewV7())
+
+	var copied []*model.Article
+	repo := &mockArticleRepo{
+		copyFn: func(_ context.Context, articles []*model.Article) error {
+			copied = articles
+			return nil
+		},
+	}
+	svc := newTestService(repo)
+
+	results := svc.ImportBatch(context.Background(), authorID, 10, []model.ImportItem{
+		{Title: "First", Content: "content", CreatedAt: createdAt},
+		{Title: "Second", Content: "content"},
+	})
+
+	if len(copied) != 2 {
+		t.Fatalf("copied = %d articles, want 2", len(copied))
+	}
+
+	if !copied[0].CreatedAt.Equal(createdAt) {
+		t.Errorf("copied[0].CreatedAt = %v, want %v", copied[0].CreatedAt, createdAt)
+	}
+
+	if copied[1].CreatedAt.IsZero() {
+		t.Error("copied[1].CreatedAt is zero, want import time")
+	}
+
+	for i, r := range results {
+		if r.Err != nil {
+			t.Errorf("results[%d].Err = %v, want nil", i, r.Err)
+		}
+		if r.Index != 10+i {
+			t.Errorf("results[%d].Index = %d, want %d", i, r.Index, 10+i)
+		}
+		if r.ArticleID != copied[i].ID {
+			t.Errorf("results[%d].ArticleID = %v, want %v", i, r.ArticleID, copied[i].ID)
+		}
+		if copied[i].AuthorID != authorID {
+			t.Errorf("copied[%d].AuthorID = %v, want %v", i, copied[i].AuthorID, authorID)
+		}
+	}
+}
+
+func TestImportBatch_InvalidItemsSkipped(t *testing.T) {
+	var copied []*model.Article
+	repo := &mockArticleRepo{
+		copyFn: func(_ context.Context, articles []*model.Article) error {
+			copied = articles
+			return nil
+		},
+	}
+	svc := newTestService(repo)
+
+	results := svc.ImportBatch(context.Background(), uuid.Must(uuid.NewV7()), 0, []model.ImportItem{
+		{Title: "", Content: "content"},
+		{Title: "Valid", Content: "content"},
+		{Title: "No content", Content: ""},
+	})
+
+	if len(copied) != 1 || copied[0].Title != "Valid" {
+		t.Fatalf("copied = %v, want only the valid article", copied)
+	}
+
+	if !errors.Is(results[0].Err, model.ErrInvalidTitle) {
+		t.Errorf("results[0].Err = %v, want ErrInvalidTitle", results[0].Err)
+	}
+
+	if results[1].Err != nil || results[1].ArticleID != copied[0].ID {
+		t.Errorf("results[1] = %+v, want success with id %v", results[1], copied[0].ID)
+	}
+
+	if !errors.Is(results[2].Err, model.ErrInvalidContent) {
+		t.Errorf("results[2].Err = %v, want ErrInvalidContent", results[2].Err)
+	}
+}
+
+func TestImportBatch_AllInvalidSkipsCopy(t *testing.T) {
+	repo := &mockArticleRepo{}
+	cache := defaultCacheRepo()
+	bumped := false
+	cache.bumpFn = func(_ context.Context) error {
+		bumped = true
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	results := svc.ImportBatch(context.Background(), uuid.Must(uuid.NewV7()), 0, []model.ImportItem{{}})
+
+	if repo.copyCalled {
+		t.Error("repo.CopyArticles was called, want skipped")
+	}
+
+	if bumped {
+		t.Error("feed generation bumped, want untouched")
+	}
+
+	if results[0].Err == nil {
+		t.Error("results[0].Err = nil, want validation error")
+	}
+}
+
+func TestImportBatch_CopyErrorFailsBatch(t *testing.T) {
+	dbErr := errors.New("db error")
+	repo := &mockArticleRepo{
+		copyFn: func(_ context.Context, _ []*model.Article) error { return dbErr },
+	}
+	svc := newTestService(repo)
+
+	results := svc.ImportBatch(context.Background(), uuid.Must(uuid.NewV7()), 0, []model.ImportItem{
+		{Title: "First", Content: "content"},
+		{Title: "", Content: "content"},
+	})
+
+	if !errors.Is(results[0].Err, dbErr) {
+		t.Errorf("results[0].Err = %v, want db error", results[0].Err)
+	}
+
+	if results[0].ArticleID != uuid.Nil {
+		t.Errorf("results[0].ArticleID = %v, want uuid.Nil", results[0].ArticleID)
+	}
+
+	if !errors.Is(results[1].Err, model.ErrInvalidTitle) {
+		t.Errorf("results[1].Err = %v, want ErrInvalidTitle", results[1].Err)
+	}
+}
+
+func TestImportBatch_InvalidatesFeed(t *testing.T) {
+	repo := &mockArticleRepo{
+		copyFn: func(_ context.Context, _ []*model.Article) error { return nil },
+	}
+	cache := defaultCacheRepo()
+	bumps := 0
+	cache.bumpFn = func(_ context.Context) error {
+		bumps++
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	svc.ImportBatch(context.Background(), uuid.Must(uuid.NewV7()), 0, []model.ImportItem{
+		{Title: "First", Content: "content"},
+		{Title: "Second", Content: "content"},
+	})
+
+	if bumps != 1 {
+		t.Errorf("feed bumped %d times, want 1", bumps)
+	}
+}
+
+func TestExportArticles_PassesArticles(t *testing.T) {
+	authorID := uuid.Must(uuid.NewV7())
+	articles := []*model.Article{{ID: uuid.Must(uuid.NewV7())}, {ID: uuid.Must(uuid.NewV7())}}
+	repo := &mockArticleRepo{
+		exportFn: func(_ context.Context, gotAuthor uuid.UUID, fn func(*model.Article) error) error {
+			if gotAuthor != authorID {
+				t.Errorf("authorID = %v, want %v", gotAuthor, authorID)
+			}
+			for _, a := range articles {
+				if err := fn(a); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	svc := newTestService(repo)
+
+	var got []uuid.UUID
+	err := svc.ExportArticles(context.Background(), authorID, func(a *model.Article) error {
+		got = append(got, a.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 2 || got[0] != articles[0].ID || got[1] != articles[1].ID {
+		t.Errorf("exported = %v, want %v and %v", got, articles[0].ID, articles[1].ID)
+	}
+}
+
+func TestExportArticles_CallbackErrorStops(t *testing.T) {
+	sendErr := errors.New("client gone")
+	repo := &mockArticleRepo{
+		exportFn: func(_ context.Context, _ uuid.UUID, fn func(*model.Article) error) error {
+			return fn(&model.Article{})
+		},
+	}
+	svc := newTestService(repo)
+
+	err := svc.ExportArticles(context.Background(), uuid.Nil, func(_ *model.Article) error { return sendErr })
+	if !errors.Is(err, sendErr) {
+		t.Errorf("error = %v, want %v", err, sendErr)
+	}
+}
+
+func TestImportBatch_FutureCreatedAtRejected(t *testing.T) {
+	var copied []*model.Article
+	repo := &mockArticleRepo{
+		copyFn: func(_ context.Context, articles []*model.Article) error {
+			copied = articles
+			return nil
+		},
+	}
+	svc := newTestService(repo)
+
+	results := svc.ImportBatch(context.Background(), uuid.Must(uuid.NewV7()), 0, []model.ImportItem{
+		{Title: "Future", Content: "content", CreatedAt: time.Now().Add(24 * time.Hour)},
+		{Title: "Skewed", Content: "content", CreatedAt: time.Now().Add(time.Minute)},
+	})
+
+	if !errors.Is(results[0].Err, model.ErrInvalidCreatedAt) {
+		t.Errorf("results[0].Err = %v, want ErrInvalidCreatedAt", results[0].Err)
+	}
+
+	if results[1].Err != nil {
+		t.Errorf("results[1].Err = %v, want nil within clock skew", results[1].Err)
+	}
+
+	if len(copied) != 1 || copied[0].Title != "Skewed" {
+		t.Errorf("copied = %v, want only the skewed article", copied)
+	}
+}
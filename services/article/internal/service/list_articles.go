@@ -3,12 +3,18 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
-const defaultLimit = 20
+const (
+	defaultLimit = 20
+
+	// refreshTimeout ограничивает пересборку страницы, которая живёт дольше запроса, её запустившего
+	refreshTimeout = 5 * time.Second
+)
 
 func (s *Service) ListArticles(ctx context.Context, cursor string, limit int32) (*model.ArticlePage, error) {
 	l := int(limit)
@@ -16,29 +22,68 @@ func (s *Service) ListArticles(ctx context.Context, cursor string, limit int32)
 		l = defaultLimit
 	}
 
-	isFirstPage := cursor == "" && limit == defaultLimit
+	log := logger.Ctx(ctx)
+
+	gen, err := s.cacheRepo.FeedGeneration(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("cache generation get failed")
+
+		page, err := s.articleRepo.List(ctx, cursor, l)
+		if err != nil {
+			return nil, fmt.Errorf("list articles: %w", err)
+		}
+
+		return page, nil
+	}
+
+	page, err := s.cacheRepo.GetPage(ctx, gen, cursor, l)
+	if err != nil {
+		log.Warn().Err(err).Msg("cache get failed")
+	}
+	if page != nil {
+		return page, nil
+	}
+
+	// ключ singleflight совпадает с ключом страницы в кеше
+	key := s.cacheRepo.PageKey(gen, cursor, l)
+	rebuild := func() (any, error) {
+		return s.rebuildPage(ctx, gen, cursor, l)
+	}
 
-	if isFirstPage {
-		page, err := s.cacheRepo.Get(ctx)
+	// пока новое поколение пересобирается, отдаём страницу предыдущего
+	if gen > 0 {
+		stale, err := s.cacheRepo.GetPage(ctx, gen-1, cursor, l)
 		if err != nil {
-			log := logger.Ctx(ctx)
-			log.Warn().Err(err).Msg("cache get failed")
+			log.Warn().Err(err).Msg("cache stale get failed")
 		}
-		if page != nil {
-			return page, nil
+		if stale != nil {
+			s.group.DoChan(key, rebuild)
+			return stale, nil
 		}
 	}
 
-	page, err := s.articleRepo.List(ctx, cursor, l)
+	v, err, _ := s.group.Do(key, rebuild)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*model.ArticlePage), nil
+}
+
+// rebuildPage читает страницу из БД и кладёт её в кеш поколения gen.
+// Работает в отвязанном от запроса контексте: результат нужен всем ожидающим, а не только инициатору.
+func (s *Service) rebuildPage(ctx context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), refreshTimeout)
+	defer cancel()
+
+	page, err := s.articleRepo.List(ctx, cursor, limit)
 	if err != nil {
 		return nil, fmt.Errorf("list articles: %w", err)
 	}
 
-	if isFirstPage {
-		if err := s.cacheRepo.Set(ctx, page); err != nil {
-			log := logger.Ctx(ctx)
-			log.Warn().Err(err).Msg("cache set failed")
-		}
+	if err := s.cacheRepo.SetPage(ctx, gen, cursor, limit, page); err != nil {
+		log := logger.Ctx(ctx)
+		log.Warn().Err(err).Msg("cache set failed")
 	}
 
 	return page, nil
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -36,10 +38,13 @@ func TestListArticles_CacheHit(t *testing.T) {
 			return nil, nil
 		},
 	}
-	cache := &mockCacheRepo{
-		getFn:        func(_ context.Context) (*model.ArticlePage, error) { return cachedPage, nil },
-		setFn:        func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error { return nil },
+	cache := defaultCacheRepo()
+	cache.genFn = func(_ context.Context) (int64, error) { return 3, nil }
+	cache.getPageFn = func(_ context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error) {
+		if gen != 3 || cursor != "" || limit != 20 {
+			t.Errorf("GetPage(%d, %q, %d), want (3, \"\", 20)", gen, cursor, limit)
+		}
+		return cachedPage, nil
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -66,13 +71,14 @@ func TestListArticles_CacheMiss(t *testing.T) {
 			return dbPage, nil
 		},
 	}
-	cache := &mockCacheRepo{
-		getFn: func(_ context.Context) (*model.ArticlePage, error) { return nil, nil },
-		setFn: func(_ context.Context, _ *model.ArticlePage) error {
-			setCalled = true
-			return nil
-		},
-		invalidateFn: func(_ context.Context) error { return nil },
+	cache := defaultCacheRepo()
+	cache.genFn = func(_ context.Context) (int64, error) { return 0, nil }
+	cache.setPageFn = func(_ context.Context, gen int64, _ string, _ int, _ *model.ArticlePage) error {
+		if gen != 0 {
+			t.Errorf("SetPage gen = %d, want 0", gen)
+		}
+		setCalled = true
+		return nil
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -86,7 +92,7 @@ func TestListArticles_CacheMiss(t *testing.T) {
 	}
 
 	if !setCalled {
-		t.Error("cache.Set was not called on first page")
+		t.Error("cache.SetPage was not called on miss")
 	}
 
 	if len(page.Articles) != 1 {
@@ -102,12 +108,9 @@ func TestListArticles_CacheErrorFallbackToDB(t *testing.T) {
 			return dbPage, nil
 		},
 	}
-	cache := &mockCacheRepo{
-		getFn: func(_ context.Context) (*model.ArticlePage, error) {
-			return nil, errors.New("redis connection refused")
-		},
-		setFn:        func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error { return nil },
+	cache := defaultCacheRepo()
+	cache.getPageFn = func(_ context.Context, _ int64, _ string, _ int) (*model.ArticlePage, error) {
+		return nil, errors.New("redis connection refused")
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -125,10 +128,43 @@ func TestListArticles_CacheErrorFallbackToDB(t *testing.T) {
 	}
 }
 
-func TestListArticles_WithCursorSkipsCache(t *testing.T) {
+func TestListArticles_GenerationErrorFallbackToDB(t *testing.T) {
+	dbPage := testArticlePage()
+	var setCalled bool
+
+	repo := &mockArticleRepo{
+		listFn: func(_ context.Context, _ string, _ int) (*model.ArticlePage, error) {
+			return dbPage, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.genFn = func(_ context.Context) (int64, error) {
+		return 0, errors.New("redis connection refused")
+	}
+	cache.setPageFn = func(_ context.Context, _ int64, _ string, _ int, _ *model.ArticlePage) error {
+		setCalled = true
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	_, err := svc.ListArticles(context.Background(), "", 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !repo.listCalled {
+		t.Error("repo.List was not called after generation error")
+	}
+
+	if setCalled {
+		t.Error("cache.SetPage was called without known generation, want skipped")
+	}
+}
+
+func TestListArticles_WithCursorCached(t *testing.T) {
 	dbPage := testArticlePage()
 	dbPage.NextCursor = ""
-	var getCalled bool
+	var setCursor string
 
 	repo := &mockArticleRepo{
 		listFn: func(_ context.Context, cursor string, _ int) (*model.ArticlePage, error) {
@@ -138,13 +174,10 @@ func TestListArticles_WithCursorSkipsCache(t *testing.T) {
 			return dbPage, nil
 		},
 	}
-	cache := &mockCacheRepo{
-		getFn: func(_ context.Context) (*model.ArticlePage, error) {
-			getCalled = true
-			return nil, nil
-		},
-		setFn:        func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error { return nil },
+	cache := defaultCacheRepo()
+	cache.setPageFn = func(_ context.Context, _ int64, cursor string, _ int, _ *model.ArticlePage) error {
+		setCursor = cursor
+		return nil
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -153,8 +186,85 @@ func TestListArticles_WithCursorSkipsCache(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if getCalled {
-		t.Error("cache.Get was called with cursor, want skipped")
+	if setCursor != "some-cursor" {
+		t.Errorf("SetPage cursor = %q, want %q", setCursor, "some-cursor")
+	}
+}
+
+func TestListArticles_ServesStaleWhileRefreshing(t *testing.T) {
+	stalePage := testArticlePage()
+	stalePage.Articles[0].Title = "Stale Article"
+	dbPage := testArticlePage()
+	refreshed := make(chan int64, 1)
+
+	repo := &mockArticleRepo{
+		listFn: func(_ context.Context, _ string, _ int) (*model.ArticlePage, error) {
+			return dbPage, nil
+		},
+	}
+	cache := defaultCacheRepo()
+	cache.genFn = func(_ context.Context) (int64, error) { return 5, nil }
+	cache.getPageFn = func(_ context.Context, gen int64, _ string, _ int) (*model.ArticlePage, error) {
+		if gen == 4 {
+			return stalePage, nil
+		}
+		return nil, nil
+	}
+	cache.setPageFn = func(_ context.Context, gen int64, _ string, _ int, _ *model.ArticlePage) error {
+		refreshed <- gen
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	page, err := svc.ListArticles(context.Background(), "", 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if page.Articles[0].Title != "Stale Article" {
+		t.Errorf("title = %q, want stale page", page.Articles[0].Title)
+	}
+
+	select {
+	case gen := <-refreshed:
+		if gen != 5 {
+			t.Errorf("refreshed gen = %d, want 5", gen)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("background refresh did not set the current generation")
+	}
+}
+
+func TestListArticles_ConcurrentMissesCollapsed(t *testing.T) {
+	const callers = 10
+
+	var listCalls atomic.Int32
+	release := make(chan struct{})
+
+	repo := &mockArticleRepo{
+		listFn: func(_ context.Context, _ string, _ int) (*model.ArticlePage, error) {
+			listCalls.Add(1)
+			<-release
+			return testArticlePage(), nil
+		},
+	}
+	svc := newTestServiceWithCache(repo, defaultCacheRepo())
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Go(func() {
+			if _, err := svc.ListArticles(context.Background(), "", 20); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := listCalls.Load(); got != 1 {
+		t.Errorf("repo.List calls = %d, want 1", got)
 	}
 }
 
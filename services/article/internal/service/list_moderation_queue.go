@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+const (
+	defaultQueueLimit = 20
+	maxQueueLimit     = 100
+)
+
+func (s *Service) ListModerationQueue(ctx context.Context, limit int32) ([]*model.ReportedArticle, error) {
+	l := int(limit)
+	if l <= 0 {
+		l = defaultQueueLimit
+	}
+	if l > maxQueueLimit {
+		l = maxQueueLimit
+	}
+
+	queue, err := s.moderationRepo.ListQueue(ctx, l)
+	if err != nil {
+		return nil, fmt.Errorf("list moderation queue: %w", err)
+	}
+
+	return queue, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func TestListModerationQueue_LimitBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int32
+		want  int
+	}{
+		{"default", 0, defaultQueueLimit},
+		{"custom", 5, 5},
+		{"above max", 1000, maxQueueLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			moderation := defaultModerationRepo()
+			moderation.listQueueFn = func(_ context.Context, limit int) ([]*model.ReportedArticle, error) {
+				if limit != tt.want {
+					t.Errorf("limit = %d, want %d", limit, tt.want)
+				}
+				return nil, nil
+			}
+			svc := newTestServiceWithModeration(&mockArticleRepo{}, moderation)
+
+			if _, err := svc.ListModerationQueue(context.Background(), tt.limit); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+const (
+	defaultMostReadLimit = 10
+	maxMostReadLimit     = 100
+)
+
+func (s *Service) ListMostRead(ctx context.Context, limit int32) ([]*model.Article, error) {
+	l := int(limit)
+	if l <= 0 {
+		l = defaultMostReadLimit
+	}
+	if l > maxMostReadLimit {
+		l = maxMostReadLimit
+	}
+
+	articles, err := s.articleRepo.ListMostRead(ctx, l)
+	if err != nil {
+		return nil, fmt.Errorf("list most read: %w", err)
+	}
+
+	return articles, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func TestListMostRead_Success(t *testing.T) {
+	repo := &mockArticleRepo{
+		listMostReadFn: func(_ context.Context, limit int) ([]*model.Article, error) {
+			if limit != 5 {
+				t.Errorf("limit = %d, want 5", limit)
+			}
+			return []*model.Article{
+				{ID: uuid.Must(uuid.NewV7()), ViewCount: 42},
+			}, nil
+		},
+	}
+	svc := newTestService(repo)
+
+	articles, err := svc.ListMostRead(context.Background(), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(articles) != 1 || articles[0].ViewCount != 42 {
+		t.Errorf("articles = %v, want one article with 42 views", articles)
+	}
+}
+
+func TestListMostRead_LimitBounds(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int32
+		want  int
+	}{
+		{"default", 0, defaultMostReadLimit},
+		{"negative", -1, defaultMostReadLimit},
+		{"max", 100, maxMostReadLimit},
+		{"above max", 1000, maxMostReadLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockArticleRepo{
+				listMostReadFn: func(_ context.Context, limit int) ([]*model.Article, error) {
+					if limit != tt.want {
+						t.Errorf("limit = %d, want %d", limit, tt.want)
+					}
+					return nil, nil
+				},
+			}
+			svc := newTestService(repo)
+
+			if _, err := svc.ListMostRead(context.Background(), tt.limit); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestListMostRead_RepoError(t *testing.T) {
+	repoErr := errors.New("connection refused")
+	repo := &mockArticleRepo{
+		listMostReadFn: func(_ context.Context, _ int) ([]*model.Article, error) { return nil, repoErr },
+	}
+	svc := newTestService(repo)
+
+	_, err := svc.ListMostRead(context.Background(), 10)
+	if !errors.Is(err, repoErr) {
+		t.Errorf("error = %v, want %v", err, repoErr)
+	}
+}
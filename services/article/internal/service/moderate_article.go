@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+// ModerateArticle применяет действие модератора к любой статье. Действие, закрытие жалоб и запись
+// в журнал модерации идут одной транзакцией: без записи в журнале статья не скрывается и не удаляется.
+func (s *Service) ModerateArticle(ctx context.Context, id, moderatorID uuid.UUID, action model.ModerationAction, reason string) error {
+	record, err := model.NewModerationRecord(id, moderatorID, action, reason)
+	if err != nil {
+		return err
+	}
+
+	err = s.txManager.Wrap(ctx, func(ctx context.Context) error {
+		switch action {
+		case model.ModerationHide:
+			if err := s.articleRepo.Hide(ctx, id); err != nil {
+				return err
+			}
+		case model.ModerationDelete:
+			if err := s.articleRepo.ForceDelete(ctx, id); err != nil {
+				return err
+			}
+		case model.ModerationDismiss:
+			if err := s.articleRepo.Lock(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		if err := s.moderationRepo.ResolveReports(ctx, id); err != nil {
+			return err
+		}
+
+		return s.moderationRepo.CreateRecord(ctx, record)
+	})
+	if err != nil {
+		return fmt.Errorf("moderate article: %w", err)
+	}
+
+	if action != model.ModerationDismiss {
+		s.invalidateArticle(ctx, id)
+	}
+
+	return nil
+}
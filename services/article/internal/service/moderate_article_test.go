@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func TestModerateArticle_Hide(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	moderatorID := uuid.Must(uuid.NewV7())
+
+	hidden := false
+	repo := &mockArticleRepo{
+		hideFn: func(_ context.Context, id uuid.UUID) error {
+			if id != articleID {
+				t.Errorf("id = %v, want %v", id, articleID)
+			}
+			hidden = true
+			return nil
+		},
+	}
+
+	var record *model.ModerationRecord
+	resolved := false
+	moderation := defaultModerationRepo()
+	moderation.resolveReportsFn = func(_ context.Context, _ uuid.UUID) error {
+		resolved = true
+		return nil
+	}
+	moderation.createRecordFn = func(_ context.Context, r *model.ModerationRecord) error {
+		record = r
+		return nil
+	}
+	svc := newTestServiceWithModeration(repo, moderation)
+
+	err := svc.ModerateArticle(context.Background(), articleID, moderatorID, model.ModerationHide, "spam")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !hidden {
+		t.Error("repo.Hide was not called")
+	}
+
+	if !resolved {
+		t.Error("reports were not resolved")
+	}
+
+	if record == nil || record.ModeratorID != moderatorID || record.Action != model.ModerationHide || record.Reason != "spam" {
+		t.Errorf("record = %+v, want hide by moderator with reason", record)
+	}
+}
+
+func TestModerateArticle_DeleteAnyArticle(t *testing.T) {
+	deleted := false
+	repo := &mockArticleRepo{
+		forceDeleteFn: func(_ context.Context, _ uuid.UUID) error {
+			deleted = true
+			return nil
+		},
+	}
+	svc := newTestService(repo)
+
+	err := svc.ModerateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), model.ModerationDelete, "illegal content")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !deleted {
+		t.Error("repo.ForceDelete was not called")
+	}
+}
+
+func TestModerateArticle_DismissKeepsArticle(t *testing.T) {
+	cache := defaultCacheRepo()
+	cache.deleteArticleFn = func(_ context.Context, _ uuid.UUID) error {
+		t.Error("cache invalidated, want untouched on dismiss")
+		return nil
+	}
+	repo := &mockArticleRepo{
+		lockFn: func(_ context.Context, _ uuid.UUID) error { return nil },
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	err := svc.ModerateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), model.ModerationDismiss, "not a violation")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestModerateArticle_DismissNotFound(t *testing.T) {
+	repo := &mockArticleRepo{
+		lockFn: func(_ context.Context, _ uuid.UUID) error { return model.ErrArticleNotFound },
+	}
+	moderation := defaultModerationRepo()
+	moderation.createRecordFn = func(_ context.Context, _ *model.ModerationRecord) error {
+		t.Error("moderation record created for missing article")
+		return nil
+	}
+	svc := newTestServiceWithModeration(repo, moderation)
+
+	err := svc.ModerateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), model.ModerationDismiss, "not a violation")
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+}
+
+func TestModerateArticle_NotFound(t *testing.T) {
+	repo := &mockArticleRepo{
+		hideFn: func(_ context.Context, _ uuid.UUID) error { return model.ErrArticleNotFound },
+	}
+	moderation := defaultModerationRepo()
+	moderation.createRecordFn = func(_ context.Context, _ *model.ModerationRecord) error {
+		t.Error("moderation record created for missing article")
+		return nil
+	}
+	svc := newTestServiceWithModeration(repo, moderation)
+
+	err := svc.ModerateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), model.ModerationHide, "spam")
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+}
+
+func TestModerateArticle_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		action  model.ModerationAction
+		reason  string
+		wantErr error
+	}{
+		{"unknown action", "ban", "spam", model.ErrInvalidModerationAction},
+		{"empty reason", model.ModerationHide, "", model.ErrInvalidReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService(&mockArticleRepo{})
+
+			err := svc.ModerateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), tt.action, tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+// ReportArticle ставит статью в очередь модерации. Повторная жалоба того же пользователя не дублируется.
+func (s *Service) ReportArticle(ctx context.Context, articleID, reporterID uuid.UUID, reason string) error {
+	report, err := model.NewReport(articleID, reporterID, reason)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.getArticle(ctx, articleID); err != nil {
+		return err
+	}
+
+	if err := s.moderationRepo.CreateReport(ctx, report); err != nil {
+		return fmt.Errorf("report article: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
+)
+
+func TestReportArticle_Success(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	reporterID := uuid.Must(uuid.NewV7())
+
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*model.Article, error) {
+			return &model.Article{ID: id}, nil
+		},
+	}
+
+	var saved *model.Report
+	moderation := defaultModerationRepo()
+	moderation.createReportFn = func(_ context.Context, r *model.Report) error {
+		saved = r
+		return nil
+	}
+	svc := newTestServiceWithModeration(repo, moderation)
+
+	if err := svc.ReportArticle(context.Background(), articleID, reporterID, "spam"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if saved == nil || saved.ArticleID != articleID || saved.ReporterID != reporterID || saved.Reason != "spam" {
+		t.Errorf("report = %+v, want report on article by reporter", saved)
+	}
+}
+
+func TestReportArticle_NotFound(t *testing.T) {
+	repo := &mockArticleRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*model.Article, error) {
+			return nil, model.ErrArticleNotFound
+		},
+	}
+	moderation := defaultModerationRepo()
+	moderation.createReportFn = func(_ context.Context, _ *model.Report) error {
+		t.Error("report created for missing article")
+		return nil
+	}
+	svc := newTestServiceWithModeration(repo, moderation)
+
+	err := svc.ReportArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), "spam")
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+}
+
+func TestReportArticle_InvalidReason(t *testing.T) {
+	svc := newTestService(&mockArticleRepo{})
+
+	for _, reason := range []string{"", strings.Repeat("a", 1001)} {
+		err := svc.ReportArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), reason)
+		if !errors.Is(err, model.ErrInvalidReason) {
+			t.Errorf("reason len %d: error = %v, want ErrInvalidReason", len(reason), err)
+		}
+	}
+}
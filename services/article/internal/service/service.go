@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
 
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
@@ -14,12 +16,38 @@ type ArticleRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Article, error)
 	Update(ctx context.Context, article *model.Article) error
 	Delete(ctx context.Context, id, authorId uuid.UUID) error
+	ListMostRead(ctx context.Context, limit int) ([]*model.Article, error)
+	ListRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error)
+	Export(ctx context.Context, authorID uuid.UUID, fn func(*model.Article) error) error
+	CopyArticles(ctx context.Context, articles []*model.Article) error
+	Hide(ctx context.Context, id uuid.UUID) error
+	Lock(ctx context.Context, id uuid.UUID) error
+	ForceDelete(ctx context.Context, id uuid.UUID) error
 }
 
 type CacheRepository interface {
-	Get(ctx context.Context) (*model.ArticlePage, error)
-	Set(ctx context.Context, page *model.ArticlePage) error
-	Invalidate(ctx context.Context) error
+	FeedGeneration(ctx context.Context) (int64, error)
+	BumpFeedGeneration(ctx context.Context) error
+	GetPage(ctx context.Context, gen int64, cursor string, limit int) (*model.ArticlePage, error)
+	SetPage(ctx context.Context, gen int64, cursor string, limit int, page *model.ArticlePage) error
+	PageKey(gen int64, cursor string, limit int) string
+	GetArticle(ctx context.Context, id uuid.UUID) (*model.Article, error)
+	ArticleVersion(ctx context.Context, id uuid.UUID) (int64, error)
+	SetArticle(ctx context.Context, article *model.Article, version int64) error
+	DeleteArticle(ctx context.Context, id uuid.UUID) error
+	GetRelated(ctx context.Context, id uuid.UUID, limit int) ([]*model.Article, error)
+	SetRelated(ctx context.Context, id uuid.UUID, limit int, articles []*model.Article) error
+}
+
+type ViewRepository interface {
+	Record(ctx context.Context, articleID uuid.UUID, viewerID string) (bool, error)
+}
+
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, report *model.Report) error
+	ResolveReports(ctx context.Context, articleID uuid.UUID) error
+	CreateRecord(ctx context.Context, record *model.ModerationRecord) error
+	ListQueue(ctx context.Context, limit int) ([]*model.ReportedArticle, error)
 }
 
 type TxManager interface {
@@ -27,15 +55,45 @@ type TxManager interface {
 }
 
 type Service struct {
-	articleRepo ArticleRepository
-	cacheRepo   CacheRepository
-	txManager   TxManager
+	articleRepo    ArticleRepository
+	cacheRepo      CacheRepository
+	viewRepo       ViewRepository
+	moderationRepo ModerationRepository
+	txManager      TxManager
+
+	// схлопывает параллельные пересборки одной и той же страницы/статьи в один запрос к БД
+	group singleflight.Group
 }
 
-func New(articleRepo ArticleRepository, cacheRepo CacheRepository, txManager TxManager) *Service {
+func New(
+	articleRepo ArticleRepository,
+	cacheRepo CacheRepository,
+	viewRepo ViewRepository,
+	moderationRepo ModerationRepository,
+	txManager TxManager,
+) *Service {
 	return &Service{
-		articleRepo: articleRepo,
-		cacheRepo:   cacheRepo,
-		txManager:   txManager,
+		articleRepo:    articleRepo,
+		cacheRepo:      cacheRepo,
+		viewRepo:       viewRepo,
+		moderationRepo: moderationRepo,
+		txManager:      txManager,
+	}
+}
+
+// invalidateFeed переключает ленту на новое поколение. Ошибка кеша не ломает запись — ленту дочистит TTL.
+func (s *Service) invalidateFeed(ctx context.Context) {
+	if err := s.cacheRepo.BumpFeedGeneration(ctx); err != nil {
+		log := logger.Ctx(ctx)
+		log.Warn().Err(err).Msg("cache feed generation bump failed")
+	}
+}
+
+func (s *Service) invalidateArticle(ctx context.Context, id uuid.UUID) {
+	if err := s.cacheRepo.DeleteArticle(ctx, id); err != nil {
+		log := logger.Ctx(ctx)
+		log.Warn().Err(err).Msg("cache article delete failed")
 	}
+
+	s.invalidateFeed(ctx)
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/google/uuid"
 
-	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
 
@@ -25,10 +24,7 @@ func (s *Service) UpdateArticle(ctx context.Context, id, authorID uuid.UUID, tit
 		return nil, fmt.Errorf("update article: %w", err)
 	}
 
-	if err := s.cacheRepo.Invalidate(ctx); err != nil {
-		log := logger.Ctx(ctx)
-		log.Warn().Err(err).Msg("cache invalidate failed")
-	}
+	s.invalidateArticle(ctx, id)
 
 	return article, nil
 }
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/SonOfSteveJobs/habr/services/article/internal/model"
 )
@@ -183,6 +184,21 @@ func TestUpdateArticle_NotFound(t *testing.T) {
 	}
 }
 
+// скрытую статью репозиторий не находит так же, как чужую: pgx.ErrNoRows из RETURNING
+func TestUpdateArticle_HiddenNotFound(t *testing.T) {
+	repo := &mockArticleRepo{
+		updateFn: func(_ context.Context, _ *model.Article) error {
+			return pgx.ErrNoRows
+		},
+	}
+	svc := newTestService(repo)
+
+	_, err := svc.UpdateArticle(context.Background(), uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()), strPtr("title"), nil)
+	if !errors.Is(err, model.ErrArticleNotFound) {
+		t.Errorf("error = %v, want ErrArticleNotFound", err)
+	}
+}
+
 func TestUpdateArticle_RepoError(t *testing.T) {
 	repoErr := errors.New("connection refused")
 	repo := &mockArticleRepo{
@@ -200,12 +216,12 @@ func TestUpdateArticle_CacheInvalidateErrorNonFatal(t *testing.T) {
 	repo := &mockArticleRepo{
 		updateFn: func(_ context.Context, _ *model.Article) error { return nil },
 	}
-	cache := &mockCacheRepo{
-		getFn: func(_ context.Context) (*model.ArticlePage, error) { return nil, nil },
-		setFn: func(_ context.Context, _ *model.ArticlePage) error { return nil },
-		invalidateFn: func(_ context.Context) error {
-			return errors.New("redis connection refused")
-		},
+	cache := defaultCacheRepo()
+	cache.bumpFn = func(_ context.Context) error {
+		return errors.New("redis connection refused")
+	}
+	cache.deleteArticleFn = func(_ context.Context, _ uuid.UUID) error {
+		return errors.New("redis connection refused")
 	}
 	svc := newTestServiceWithCache(repo, cache)
 
@@ -218,3 +234,38 @@ func TestUpdateArticle_CacheInvalidateErrorNonFatal(t *testing.T) {
 		t.Error("article = nil, want non-nil")
 	}
 }
+
+func TestUpdateArticle_InvalidatesCache(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	var (
+		deletedID uuid.UUID
+		bumped    bool
+	)
+
+	repo := &mockArticleRepo{
+		updateFn: func(_ context.Context, _ *model.Article) error { return nil },
+	}
+	cache := defaultCacheRepo()
+	cache.deleteArticleFn = func(_ context.Context, id uuid.UUID) error {
+		deletedID = id
+		return nil
+	}
+	cache.bumpFn = func(_ context.Context) error {
+		bumped = true
+		return nil
+	}
+	svc := newTestServiceWithCache(repo, cache)
+
+	_, err := svc.UpdateArticle(context.Background(), articleID, uuid.Must(uuid.NewV7()), strPtr("title"), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if deletedID != articleID {
+		t.Errorf("cache.DeleteArticle id = %v, want %v", deletedID, articleID)
+	}
+
+	if !bumped {
+		t.Error("cache.BumpFeedGeneration was not called")
+	}
+}
@@ -0,0 +1,101 @@
+package views
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+)
+
+const flushTimeout = 10 * time.Second
+
+type ViewRepository interface {
+	Drain(ctx context.Context) (map[uuid.UUID]int64, error)
+	Restore(ctx context.Context, views map[uuid.UUID]int64) error
+}
+
+type ArticleRepository interface {
+	AddViews(ctx context.Context, views map[uuid.UUID]int64) error
+}
+
+type CacheRepository interface {
+	DropArticles(ctx context.Context, ids []uuid.UUID) error
+}
+
+// Flusher периодически переносит накопленные в Redis просмотры в Postgres,
+// чтобы чтение статьи не превращалось в запись в БД.
+type Flusher struct {
+	viewRepo    ViewRepository
+	articleRepo ArticleRepository
+	cacheRepo   CacheRepository
+	interval    time.Duration
+}
+
+func NewFlusher(viewRepo ViewRepository, articleRepo ArticleRepository, cacheRepo CacheRepository, interval time.Duration) *Flusher {
+	return &Flusher{
+		viewRepo:    viewRepo,
+		articleRepo: articleRepo,
+		cacheRepo:   cacheRepo,
+		interval:    interval,
+	}
+}
+
+// Run блокируется до отмены ctx. При остановке делает последний сброс, чтобы не держать просмотры в буфере до рестарта.
+func (f *Flusher) Run(ctx context.Context) {
+	log := logger.Logger()
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	log.Info().Msg("view flusher started")
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+			f.flush(flushCtx)
+			cancel()
+
+			log.Info().Msg("view flusher stopped")
+			return
+
+		case <-ticker.C:
+			f.flush(ctx)
+		}
+	}
+}
+
+func (f *Flusher) flush(ctx context.Context) {
+	log := logger.Logger()
+
+	views, err := f.viewRepo.Drain(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("views: drain failed")
+		return
+	}
+
+	if len(views) == 0 {
+		return
+	}
+
+	if err := f.articleRepo.AddViews(ctx, views); err != nil {
+		log.Error().Err(err).Int("articles", len(views)).Msg("views: flush to db failed")
+
+		if err := f.viewRepo.Restore(context.WithoutCancel(ctx), views); err != nil {
+			log.Error().Err(err).Int("articles", len(views)).Msg("views: restore failed, views lost")
+		}
+
+		return
+	}
+
+	// иначе GetArticle отдавал бы view_count из кеша, отстающий от БД на TTL кеша
+	if err := f.cacheRepo.DropArticles(ctx, slices.Collect(maps.Keys(views))); err != nil {
+		log.Warn().Err(err).Int("articles", len(views)).Msg("views: cache invalidation failed")
+	}
+
+	log.Debug().Int("articles", len(views)).Msg("views: flushed")
+}
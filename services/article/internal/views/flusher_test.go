@@ -0,0 +1,70 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type mockViewRepo struct {
+	views    map[uuid.UUID]int64
+	restored map[uuid.UUID]int64
+}
+
+func (m *mockViewRepo) Drain(context.Context) (map[uuid.UUID]int64, error) {
+	views := m.views
+	m.views = nil
+	return views, nil
+}
+
+func (m *mockViewRepo) Restore(_ context.Context, views map[uuid.UUID]int64) error {
+	m.restored = views
+	return nil
+}
+
+type mockArticleRepo struct {
+	err error
+}
+
+func (m *mockArticleRepo) AddViews(context.Context, map[uuid.UUID]int64) error {
+	return m.err
+}
+
+type mockCacheRepo struct {
+	dropped []uuid.UUID
+}
+
+func (m *mockCacheRepo) DropArticles(_ context.Context, ids []uuid.UUID) error {
+	m.dropped = append(m.dropped, ids...)
+	return nil
+}
+
+func TestFlush_DropsCachedArticles(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+	cache := &mockCacheRepo{}
+	f := NewFlusher(&mockViewRepo{views: map[uuid.UUID]int64{id: 3}}, &mockArticleRepo{}, cache, 0)
+
+	f.flush(context.Background())
+
+	if len(cache.dropped) != 1 || cache.dropped[0] != id {
+		t.Errorf("dropped = %v, want [%v]", cache.dropped, id)
+	}
+}
+
+func TestFlush_DBErrorKeepsCache(t *testing.T) {
+	id := uuid.Must(uuid.NewV7())
+	viewRepo := &mockViewRepo{views: map[uuid.UUID]int64{id: 3}}
+	cache := &mockCacheRepo{}
+	f := NewFlusher(viewRepo, &mockArticleRepo{err: errors.New("db error")}, cache, 0)
+
+	f.flush(context.Background())
+
+	if len(cache.dropped) != 0 {
+		t.Errorf("dropped = %v, want none when views are restored", cache.dropped)
+	}
+	if viewRepo.restored[id] != 3 {
+		t.Errorf("restored = %v, want views returned to redis", viewRepo.restored)
+	}
+}
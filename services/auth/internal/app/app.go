@@ -10,8 +10,10 @@ import (
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
 	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/grpcvalidate"
+	"github.com/SonOfSteveJobs/habr/pkg/health"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/pkg/requestid"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/config"
 )
@@ -19,6 +21,7 @@ import (
 type App struct {
 	infra   *infraContainer
 	service *serviceContainer
+	health  *health.Health
 
 	grpcServer *grpc.Server
 	listener   net.Listener
@@ -70,6 +73,7 @@ func (a *App) initDeps(ctx context.Context) error {
 		{"metrics", a.initMetrics},
 		{"infra: postgres, redis, kafka", a.initInfra},
 		{"service: repositories, services", a.initService},
+		{"health", a.initHealth},
 		{"gRPC server", a.initGRPCServer},
 		{"listener", a.initListener},
 	}
@@ -100,6 +104,20 @@ func (a *App) initService(_ context.Context) error {
 	return nil
 }
 
+// initHealth - readiness по зависимостям, отдаётся через grpc.health.v1 и падает с началом shutdown.
+func (a *App) initHealth(_ context.Context) error {
+	closer.SetDrainDelay(config.AppConfig().DrainDelay())
+	a.health = health.New(closer.Draining())
+	a.health.Add("postgres", health.Postgres(a.infra.PgPool()))
+	a.health.Add("redis", health.Redis(a.infra.RedisClient()))
+	// события уходят через outbox, без Kafka регистрация и логин работают
+	if !config.AppConfig().Kafka().InMemory() {
+		a.health.AddOptional("kafka", health.Kafka(config.AppConfig().Kafka().Brokers()))
+	}
+
+	return nil
+}
+
 func (a *App) initTracing(ctx context.Context) error {
 	if err := tracing.InitTracer(ctx, config.AppConfig().Tracing()); err != nil {
 		return err
@@ -134,11 +152,14 @@ func (a *App) initGRPCServer(_ context.Context) error {
 	a.grpcServer = grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			tracing.UnaryServerInterceptor(),
+			requestid.UnaryServerInterceptor(),
 			metrics.UnaryServerInterceptor(),
 			grpcvalidate.UnaryInterceptor(),
 		),
+		grpc.ChainStreamInterceptor(requestid.StreamServerInterceptor()),
 	)
 	authv1.RegisterAuthServiceServer(a.grpcServer, a.service.Handler())
+	a.health.RegisterGRPC(a.grpcServer)
 	reflection.Register(a.grpcServer)
 	closer.AddNamed("gRPC server", func(_ context.Context) error {
 		a.grpcServer.GracefulStop()
@@ -11,7 +11,9 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/memkafka"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/transaction"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/config"
 )
@@ -21,6 +23,8 @@ type infraContainer struct {
 	redisClient    *redis.Client
 	txManager      *transaction.Manager
 	saramaProducer sarama.AsyncProducer
+	// memBroker - вместо saramaProducer при KAFKA_IN_MEMORY
+	memBroker *memkafka.Broker
 }
 
 func newInfraContainer(ctx context.Context) (*infraContainer, error) {
@@ -36,7 +40,7 @@ func newInfraContainer(ctx context.Context) (*infraContainer, error) {
 
 	c.txManager = transaction.New(c.pgPool)
 
-	if err := c.initSaramaProducer(); err != nil {
+	if err := c.initKafka(); err != nil {
 		return nil, fmt.Errorf("kafka producer: %w", err)
 	}
 
@@ -47,6 +51,7 @@ func (c *infraContainer) PgPool() *pgxpool.Pool                { return c.pgPool
 func (c *infraContainer) RedisClient() *redis.Client           { return c.redisClient }
 func (c *infraContainer) TxManager() *transaction.Manager      { return c.txManager }
 func (c *infraContainer) SaramaProducer() sarama.AsyncProducer { return c.saramaProducer }
+func (c *infraContainer) MemBroker() *memkafka.Broker          { return c.memBroker }
 
 func (c *infraContainer) initPgPool(ctx context.Context) error {
 	pgCfg, err := pgxpool.ParseConfig(config.AppConfig().DBURI())
@@ -94,8 +99,17 @@ func (c *infraContainer) initRedisClient(ctx context.Context) error {
 	return nil
 }
 
-func (c *infraContainer) initSaramaProducer() error {
-	cfg := producer.NewAsyncConfig(producer.WithIdempotent())
+// initKafka - с KAFKA_IN_MEMORY события остаются в брокере в памяти процесса, Kafka для локального запуска не нужна.
+func (c *infraContainer) initKafka() error {
+	if config.AppConfig().Kafka().InMemory() {
+		log := logger.Logger()
+		log.Warn().Msg("kafka: in-memory broker, events stay inside the process")
+
+		c.memBroker = memkafka.NewBroker()
+		return nil
+	}
+
+	cfg := producer.NewAsyncConfig(producer.WithIdempotent(), producer.WithHashPartitioner())
 	p, err := sarama.NewAsyncProducer(config.AppConfig().Kafka().Brokers(), cfg)
 	if err != nil {
 		return err
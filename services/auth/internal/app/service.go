@@ -3,9 +3,8 @@ package app
 import (
 	"context"
 
-	"github.com/google/uuid"
-
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/memkafka"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/config"
@@ -25,7 +24,7 @@ type serviceContainer struct {
 	userRepo         *userrepo.Repository
 	tokenRepo        *tokenrepo.Repository
 	verificationRepo *verificationrepo.Repository
-	kafkaProducer    *producer.AsyncProducer
+	kafkaProducer    outbox.Producer
 	outboxRelay      *outbox.Relay
 	authService      *service.Service
 	handler          *authgrpc.Handler
@@ -67,30 +66,30 @@ func (c *serviceContainer) VerificationRepo() *verificationrepo.Repository {
 	return c.verificationRepo
 }
 
-func (c *serviceContainer) KafkaProducer() *producer.AsyncProducer {
+// KafkaProducer - подтверждения доставки получает outbox relay через SendAsync, он же помечает события.
+// С KAFKA_IN_MEMORY события пишутся в memkafka и сразу считаются доставленными.
+func (c *serviceContainer) KafkaProducer() outbox.Producer {
 	if c.kafkaProducer == nil {
-		log := logger.Logger()
-		repo := c.OutboxRepo()
+		topic := config.AppConfig().Kafka().Topic()
 
-		onSuccess := func(metadata any) {
-			eventID, ok := metadata.(string)
-			if !ok {
-				return
-			}
+		if broker := c.infra.MemBroker(); broker != nil {
+			p := memkafka.NewProducer(broker, topic)
+			closer.AddNamed("kafka producer", func(context.Context) error {
+				return p.Close()
+			})
+			c.kafkaProducer = p
 
-			uid, err := uuid.Parse(eventID)
-			if err != nil {
-				log.Error().Err(err).Str("event_id", eventID).Msg("outbox: invalid event_id in metadata")
-				return
-			}
+			return c.kafkaProducer
+		}
 
-			if err := repo.MarkSent(context.Background(), uid); err != nil {
-				log.Error().Err(err).Str("event_id", eventID).Msg("outbox: mark sent failed")
+		p := producer.NewAsync(c.infra.SaramaProducer(), topic)
+		closer.AddNamed("kafka producer", func(ctx context.Context) error {
+			// неподтверждённые к дедлайну события останутся в outbox и уйдут после рестарта
+			if err := p.Flush(ctx); err != nil {
+				log := logger.Logger()
+				log.Warn().Err(err).Msg("kafka producer: flush incomplete")
 			}
-		}
 
-		p := producer.NewAsync(c.infra.SaramaProducer(), config.AppConfig().Kafka().Topic(), onSuccess)
-		closer.AddNamed("kafka producer", func(_ context.Context) error {
 			return p.Close()
 		})
 
@@ -126,6 +125,7 @@ func (c *serviceContainer) AuthService() *service.Service {
 			c.infra.TxManager(),
 			cfg.JWTSecret(),
 			cfg.Kafka().Topic(),
+			cfg.Kafka().EventContentType(),
 			cfg.AccessTokenTTL(),
 			cfg.RefreshTokenTTL(),
 			cfg.VerificationCodeTTL(),
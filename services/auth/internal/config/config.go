@@ -26,6 +26,7 @@ type Config struct {
 	logger              LoggerConfig
 	kafka               KafkaConfig
 	tracing             *TracingConfig
+	drainDelay          time.Duration
 }
 
 func (c *Config) GRPCPort() string                   { return c.grpcPort }
@@ -39,6 +40,9 @@ func (c *Config) Logger() LoggerConfig               { return c.logger }
 func (c *Config) Kafka() KafkaConfig                 { return c.kafka }
 func (c *Config) Tracing() *TracingConfig            { return c.tracing }
 
+// DrainDelay - сколько gRPC сервер ещё принимает запросы после перехода readiness в NOT_SERVING.
+func (c *Config) DrainDelay() time.Duration { return c.drainDelay }
+
 //nolint:cyclop
 func Load(path ...string) error {
 	err := godotenv.Load(path...)
@@ -105,6 +109,20 @@ func Load(path ...string) error {
 		return err
 	}
 
+	// события в памяти не доходят до notification и теряются при рестарте
+	if kafka.InMemory() && tracing.Environment() == productionEnvironment {
+		return ErrKafkaInMemoryInProduction
+	}
+
+	var drainDelay time.Duration
+	if v := os.Getenv("SHUTDOWN_DRAIN_DELAY"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			return ErrInvalidDrainDelay
+		}
+		drainDelay = parsed
+	}
+
 	appConfig = &Config{
 		grpcPort:            grpcPort,
 		dbURI:               dbURI,
@@ -116,6 +134,7 @@ func Load(path ...string) error {
 		logger:              logger,
 		kafka:               kafka,
 		tracing:             tracing,
+		drainDelay:          drainDelay,
 	}
 
 	return nil
@@ -12,6 +12,10 @@ var (
 	ErrLoggerAsJsonInvalid        = errors.New("LOGGER_AS_JSON must be true or false")
 	ErrKafkaBrokersNotProvided    = errors.New("KAFKA_BROKERS is not provided")
 	ErrKafkaTopicNotProvided      = errors.New("KAFKA_TOPIC is not provided")
+	ErrKafkaInMemoryInvalid       = errors.New("KAFKA_IN_MEMORY must be true or false")
+	ErrKafkaInMemoryInProduction  = errors.New("KAFKA_IN_MEMORY is not allowed in production")
+	ErrKafkaEventFormatInvalid    = errors.New("KAFKA_EVENT_FORMAT must be json or protobuf")
 	ErrOtelEndpointNotProvided    = errors.New("OTEL_COLLECTOR_ENDPOINT is not provided")
 	ErrOtelServiceNameNotProvided = errors.New("OTEL_SERVICE_NAME is not provided")
+	ErrInvalidDrainDelay          = errors.New("SHUTDOWN_DRAIN_DELAY must be a non-negative duration")
 )
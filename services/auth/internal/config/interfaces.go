@@ -8,9 +8,11 @@ type LoggerConfig interface {
 }
 
 type KafkaConfig interface {
+	InMemory() bool
 	Brokers() []string
 	Topic() string
 	OutboxPollInterval() time.Duration
 	OutboxCleanupInterval() time.Duration
 	OutboxFetchLimit() int
+	EventContentType() string
 }
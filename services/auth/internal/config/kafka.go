@@ -5,8 +5,12 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 )
 
+const productionEnvironment = "production"
+
 const (
 	defaultOutboxPollInterval    = 2 * time.Second
 	defaultOutboxCleanupInterval = 60 * time.Second
@@ -14,22 +18,37 @@ const (
 )
 
 type kafkaConfig struct {
+	inMemory              bool
 	brokers               []string
 	topic                 string
 	outboxPollInterval    time.Duration
 	outboxCleanupInterval time.Duration
 	outboxFetchLimit      int
+	eventContentType      string
 }
 
+// InMemory - события пишутся в memkafka внутри процесса, брокер не нужен. Только для локального запуска.
+func (c *kafkaConfig) InMemory() bool { return c.inMemory }
+
 func (c *kafkaConfig) Brokers() []string                    { return c.brokers }
 func (c *kafkaConfig) Topic() string                        { return c.topic }
 func (c *kafkaConfig) OutboxPollInterval() time.Duration    { return c.outboxPollInterval }
 func (c *kafkaConfig) OutboxCleanupInterval() time.Duration { return c.outboxCleanupInterval }
 func (c *kafkaConfig) OutboxFetchLimit() int                { return c.outboxFetchLimit }
+func (c *kafkaConfig) EventContentType() string             { return c.eventContentType }
 
 func newKafkaConfig() (*kafkaConfig, error) {
+	inMemory := false
+	if v := os.Getenv("KAFKA_IN_MEMORY"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, ErrKafkaInMemoryInvalid
+		}
+		inMemory = parsed
+	}
+
 	brokersStr := os.Getenv("KAFKA_BROKERS")
-	if brokersStr == "" {
+	if brokersStr == "" && !inMemory {
 		return nil, ErrKafkaBrokersNotProvided
 	}
 
@@ -38,9 +57,12 @@ func newKafkaConfig() (*kafkaConfig, error) {
 		return nil, ErrKafkaTopicNotProvided
 	}
 
-	brokers := strings.Split(brokersStr, ",")
-	for i := range brokers {
-		brokers[i] = strings.TrimSpace(brokers[i])
+	var brokers []string
+	if brokersStr != "" {
+		brokers = strings.Split(brokersStr, ",")
+		for i := range brokers {
+			brokers[i] = strings.TrimSpace(brokers[i])
+		}
 	}
 
 	outboxPollInterval := defaultOutboxPollInterval
@@ -64,11 +86,23 @@ func newKafkaConfig() (*kafkaConfig, error) {
 		}
 	}
 
+	// формат публикуемых событий: json читается глазами в kafka-ui, protobuf компактнее
+	eventContentType := envelope.ContentTypeJSON
+	switch os.Getenv("KAFKA_EVENT_FORMAT") {
+	case "", "json":
+	case "protobuf":
+		eventContentType = envelope.ContentTypeProtobuf
+	default:
+		return nil, ErrKafkaEventFormatInvalid
+	}
+
 	return &kafkaConfig{
+		inMemory:              inMemory,
 		brokers:               brokers,
 		topic:                 topic,
 		outboxPollInterval:    outboxPollInterval,
 		outboxCleanupInterval: outboxCleanupInterval,
 		outboxFetchLimit:      outboxFetchLimit,
+		eventContentType:      eventContentType,
 	}, nil
 }
@@ -5,8 +5,8 @@ import (
 	"errors"
 
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
 )
@@ -14,40 +14,40 @@ import (
 func registerError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrInvalidEmail):
-		return status.Error(codes.InvalidArgument, "invalid email")
+		return grpcerr.Field(grpcerr.ReasonInvalidEmail, "email", "invalid email")
 	case errors.Is(err, model.ErrInvalidPassword):
-		return status.Error(codes.InvalidArgument, "password must contain only letters and digits")
+		return grpcerr.Field(grpcerr.ReasonInvalidPassword, "password", "password must contain only letters and digits")
 	case errors.Is(err, model.ErrEmailAlreadyExists):
-		return status.Error(codes.AlreadyExists, "user with this email already exists")
+		return grpcerr.New(codes.AlreadyExists, grpcerr.ReasonEmailTaken, "user with this email already exists")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("register: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func loginError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrInvalidCredentials):
-		return status.Error(codes.Unauthenticated, "invalid credentials")
+		return grpcerr.New(codes.Unauthenticated, grpcerr.ReasonInvalidCredentials, "invalid credentials")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("login: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
 func refreshTokenError(ctx context.Context, err error) error {
 	switch {
-	case errors.Is(err, model.ErrInvalidRefreshToken):
-		return status.Error(codes.Unauthenticated, "invalid refresh token")
+	case errors.Is(err, model.ErrInvalidRefreshToken), errors.Is(err, model.ErrUserNotFound):
+		return grpcerr.New(codes.Unauthenticated, grpcerr.ReasonInvalidRefreshToken, "invalid refresh token")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("refresh token: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
 	}
 }
 
@@ -55,19 +55,33 @@ func logoutError(ctx context.Context, err error) error {
 	log := logger.Ctx(ctx)
 	log.Error().Err(err).Msg("logout: internal error")
 
-	return status.Error(codes.Internal, "internal error")
+	return grpcerr.Internal()
 }
 
 func verifyEmailError(ctx context.Context, err error) error {
 	switch {
 	case errors.Is(err, model.ErrInvalidVerificationCode):
-		return status.Error(codes.InvalidArgument, "invalid verification code")
+		return grpcerr.Field(grpcerr.ReasonInvalidVerificationCode, "code", "invalid verification code")
 	case errors.Is(err, model.ErrUserNotFound):
-		return status.Error(codes.NotFound, "user not found")
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonUserNotFound, "user not found")
 	default:
 		log := logger.Ctx(ctx)
 		log.Error().Err(err).Msg("verify email: internal error")
 
-		return status.Error(codes.Internal, "internal error")
+		return grpcerr.Internal()
+	}
+}
+
+func setUserRolesError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidRole):
+		return grpcerr.Field(grpcerr.ReasonInvalidRole, "roles", "invalid role")
+	case errors.Is(err, model.ErrUserNotFound):
+		return grpcerr.New(codes.NotFound, grpcerr.ReasonUserNotFound, "user not found")
+	default:
+		log := logger.Ctx(ctx)
+		log.Error().Err(err).Msg("set user roles: internal error")
+
+		return grpcerr.Internal()
 	}
 }
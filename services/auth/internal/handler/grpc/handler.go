@@ -17,6 +17,7 @@ type AuthService interface {
 	RefreshToken(ctx context.Context, userID uuid.UUID, refreshToken string) (*model.TokenPair, error)
 	Logout(ctx context.Context, userID uuid.UUID) error
 	VerifyEmail(ctx context.Context, userID uuid.UUID, code string) error
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roles []string) error
 }
 
 type Handler struct {
@@ -91,3 +92,16 @@ func (h *Handler) VerifyEmail(ctx context.Context, req *authv1.VerifyEmailReques
 
 	return &authv1.VerifyEmailResponse{}, nil
 }
+
+func (h *Handler) SetUserRoles(ctx context.Context, req *authv1.SetUserRolesRequest) (*authv1.SetUserRolesResponse, error) {
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	if err := h.authService.SetUserRoles(ctx, userID, req.GetRoles()); err != nil {
+		return nil, setUserRolesError(ctx, err)
+	}
+
+	return &authv1.SetUserRolesResponse{}, nil
+}
@@ -11,4 +11,5 @@ var (
 	ErrInvalidRefreshToken     = errors.New("invalid refresh token")
 	ErrUserNotFound            = errors.New("user not found")
 	ErrInvalidVerificationCode = errors.New("invalid verification code")
+	ErrInvalidRole             = errors.New("invalid role")
 )
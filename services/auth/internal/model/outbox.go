@@ -7,9 +7,11 @@ import (
 )
 
 type OutboxEvent struct {
-	EventID   uuid.UUID
-	Topic     string
-	Key       []byte
-	Value     []byte
-	CreatedAt time.Time
+	EventID uuid.UUID
+	Topic   string
+	Key     []byte
+	Value   []byte
+	// ContentType - формат Value, уходит в заголовок content-type сообщения
+	ContentType string
+	CreatedAt   time.Time
 }
@@ -0,0 +1,42 @@
+package model
+
+import "slices"
+
+// Role - роль пользователя, попадает в claim roles access токена.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// ParseRoles проверяет набор ролей. Роль user есть у всех и добавляется всегда, дубликаты убираются.
+func ParseRoles(raw []string) ([]Role, error) {
+	roles := []Role{RoleUser}
+
+	for _, r := range raw {
+		role := Role(r)
+
+		switch role {
+		case RoleUser, RoleModerator, RoleAdmin:
+		default:
+			return nil, ErrInvalidRole
+		}
+
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles, nil
+}
+
+func RoleStrings(roles []Role) []string {
+	out := make([]string, len(roles))
+	for i, r := range roles {
+		out[i] = string(r)
+	}
+
+	return out
+}
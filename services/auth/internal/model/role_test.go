@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseRoles(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     []string
+		want    []Role
+		wantErr error
+	}{
+		{"empty gives user", nil, []Role{RoleUser}, nil},
+		{"user always first", []string{"admin"}, []Role{RoleUser, RoleAdmin}, nil},
+		{"duplicates removed", []string{"moderator", "user", "moderator"}, []Role{RoleUser, RoleModerator}, nil},
+		{"unknown role", []string{"root"}, nil, ErrInvalidRole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoles(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseRoles(%v) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
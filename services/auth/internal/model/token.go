@@ -16,13 +16,22 @@ type TokenPair struct {
 	RefreshToken string //nolint:gosec // возвращается на клиент, тут все ок
 }
 
-func NewTokenPair(userID uuid.UUID, secret string, accessTTL time.Duration) (*TokenPair, error) {
+// accessClaims - claims access токена. Роли читает gateway, не обращаясь в auth.
+type accessClaims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+func NewTokenPair(userID uuid.UUID, roles []Role, secret string, accessTTL time.Duration) (*TokenPair, error) {
 	now := time.Now()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   userID.String(),
-		IssuedAt:  jwt.NewNumericDate(now),
-		ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims{
+		Roles: RoleStrings(roles),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(accessTTL)),
+		},
 	})
 
 	accessToken, err := token.SignedString([]byte(secret))
@@ -14,7 +14,7 @@ func TestNewTokenPair_Success(t *testing.T) {
 	secret := "test-secret-key"
 	accessTTL := 10 * time.Minute
 
-	pair, err := NewTokenPair(userID, secret, accessTTL)
+	pair, err := NewTokenPair(userID, []Role{RoleUser}, secret, accessTTL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -43,7 +43,7 @@ func TestNewTokenPair_JWTClaims(t *testing.T) {
 	secret := "test-secret-key"
 	accessTTL := 10 * time.Minute
 
-	pair, err := NewTokenPair(userID, secret, accessTTL)
+	pair, err := NewTokenPair(userID, []Role{RoleUser}, secret, accessTTL)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -64,6 +64,16 @@ func TestNewTokenPair_JWTClaims(t *testing.T) {
 		t.Errorf("subject = %q, want %q", sub, userID.String())
 	}
 
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		t.Fatalf("claims type = %T, want jwt.MapClaims", token.Claims)
+	}
+
+	roles, ok := claims["roles"].([]any)
+	if !ok || len(roles) != 1 || roles[0] != string(RoleUser) {
+		t.Errorf("roles claim = %v, want [user]", claims["roles"])
+	}
+
 	exp, err := token.Claims.GetExpirationTime()
 	if err != nil {
 		t.Fatalf("failed to get expiration: %v", err)
@@ -79,12 +89,12 @@ func TestNewTokenPair_JWTClaims(t *testing.T) {
 func TestNewTokenPair_UniqueRefreshTokens(t *testing.T) {
 	userID := uuid.Must(uuid.NewV7())
 
-	p1, err := NewTokenPair(userID, "secret", 10*time.Minute)
+	p1, err := NewTokenPair(userID, []Role{RoleUser}, "secret", 10*time.Minute)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	p2, err := NewTokenPair(userID, "secret", 10*time.Minute)
+	p2, err := NewTokenPair(userID, []Role{RoleUser}, "secret", 10*time.Minute)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -20,6 +20,7 @@ type User struct {
 	Email            string
 	HashedPassword   string
 	IsEmailConfirmed bool
+	Roles            []Role
 	CreatedAt        time.Time
 }
 
@@ -46,6 +47,7 @@ func NewUser(email, password string) (*User, error) {
 		ID:             id,
 		Email:          email,
 		HashedPassword: string(hashedPassword),
+		Roles:          []Role{RoleUser},
 	}, nil
 }
 
@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
@@ -15,11 +17,12 @@ import (
 type OutboxRepository interface {
 	FetchUnsent(ctx context.Context, limit int) ([]model.OutboxEvent, error)
 	MarkSent(ctx context.Context, eventID uuid.UUID) error
+	MarkFailed(ctx context.Context, eventID uuid.UUID, sendErr error) error
 	DeleteSent(ctx context.Context) error
 }
 
 type Producer interface {
-	Send(ctx context.Context, msg kafka.Message) error
+	SendAsync(ctx context.Context, msg kafka.Message) (<-chan producer.Result, error)
 }
 
 type Relay struct {
@@ -66,6 +69,9 @@ func (r *Relay) Run(ctx context.Context) {
 	}
 }
 
+// poll отправляет пачку событий, не дожидаясь подтверждения каждого, затем по результатам доставки
+// помечает отправленные и неудачные. Следующий опрос начинается после всех подтверждений, поэтому
+// событие, которое ещё летит в Kafka, повторно не выбирается.
 func (r *Relay) poll(ctx context.Context) {
 	log := logger.Logger()
 
@@ -75,30 +81,76 @@ func (r *Relay) poll(ctx context.Context) {
 		return
 	}
 
+	type inflight struct {
+		event  model.OutboxEvent
+		span   trace.Span
+		result <-chan producer.Result
+	}
+
+	sent := make([]inflight, 0, len(events))
+
 	for _, event := range events {
 		eventCtx, span := tracing.StartSpan(ctx, "kafka.produce/outbox")
 
 		msg := kafka.Message{
 			Key:      event.Key,
 			Value:    event.Value,
+			Headers:  map[string][]byte{kafka.HeaderContentType: []byte(event.ContentType)},
 			Metadata: event.EventID.String(),
 		}
 
 		tracing.InjectToMessage(eventCtx, &msg)
 
-		log.Info().Str("event_id", event.EventID.String()).Msg("outbox: send to kafka")
-
-		if err := r.producer.Send(ctx, msg); err != nil {
+		result, err := r.producer.SendAsync(ctx, msg)
+		if err != nil {
 			span.RecordError(err)
 			span.End()
 
 			log.Error().Err(err).
 				Str("event_id", event.EventID.String()).
 				Msg("outbox: send to kafka failed")
+			break
+		}
+
+		sent = append(sent, inflight{event: event, span: span, result: result})
+	}
+
+	for i, s := range sent {
+		select {
+		case res := <-s.result:
+			r.complete(ctx, s.event, s.span, res.Err)
+
+		case <-ctx.Done():
+			// неподтверждённые события останутся неотправленными и уйдут после рестарта
+			for _, rest := range sent[i:] {
+				rest.span.End()
+			}
 			return
 		}
+	}
+}
+
+func (r *Relay) complete(ctx context.Context, event model.OutboxEvent, span trace.Span, sendErr error) {
+	log := logger.Logger()
+	defer span.End()
+
+	if sendErr != nil {
+		span.RecordError(sendErr)
+
+		log.Error().Err(sendErr).
+			Str("event_id", event.EventID.String()).
+			Msg("outbox: kafka delivery failed")
+
+		if err := r.repo.MarkFailed(ctx, event.EventID, sendErr); err != nil {
+			log.Error().Err(err).Str("event_id", event.EventID.String()).Msg("outbox: record delivery failure failed")
+		}
+		return
+	}
+
+	log.Info().Str("event_id", event.EventID.String()).Msg("outbox: delivered to kafka")
 
-		span.End()
+	if err := r.repo.MarkSent(ctx, event.EventID); err != nil {
+		log.Error().Err(err).Str("event_id", event.EventID.String()).Msg("outbox: mark sent failed")
 	}
 }
 
@@ -0,0 +1,156 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/memkafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
+)
+
+type mockRepo struct {
+	events []model.OutboxEvent
+	sent   []uuid.UUID
+	failed map[uuid.UUID]error
+}
+
+func (m *mockRepo) FetchUnsent(_ context.Context, _ int) ([]model.OutboxEvent, error) {
+	return m.events, nil
+}
+
+func (m *mockRepo) MarkSent(_ context.Context, eventID uuid.UUID) error {
+	m.sent = append(m.sent, eventID)
+	return nil
+}
+
+func (m *mockRepo) MarkFailed(_ context.Context, eventID uuid.UUID, sendErr error) error {
+	if m.failed == nil {
+		m.failed = make(map[uuid.UUID]error)
+	}
+	m.failed[eventID] = sendErr
+	return nil
+}
+
+func (m *mockRepo) DeleteSent(_ context.Context) error { return nil }
+
+// mockProducer - результат доставки по event ID из Metadata, отсутствие в results - ошибка постановки.
+type mockProducer struct {
+	results map[string]error
+	queued  []kafka.Message
+}
+
+func (m *mockProducer) SendAsync(_ context.Context, msg kafka.Message) (<-chan producer.Result, error) {
+	sendErr, ok := m.results[msg.Metadata.(string)]
+	if !ok {
+		return nil, producer.ErrProducerClosed
+	}
+
+	m.queued = append(m.queued, msg)
+
+	ch := make(chan producer.Result, 1)
+	ch <- producer.Result{Message: msg, Err: sendErr}
+
+	return ch, nil
+}
+
+func newEvents(n int) []model.OutboxEvent {
+	events := make([]model.OutboxEvent, n)
+	for i := range events {
+		events[i] = model.OutboxEvent{
+			EventID:     uuid.New(),
+			Topic:       "user-registered",
+			Value:       []byte("{}"),
+			ContentType: "application/json",
+			CreatedAt:   time.Now(),
+		}
+	}
+
+	return events
+}
+
+func TestRelayPoll_MarksByDeliveryResult(t *testing.T) {
+	events := newEvents(3)
+	deliveryErr := errors.New("kafka: client has run out of available brokers")
+
+	repo := &mockRepo{events: events}
+	prod := &mockProducer{results: map[string]error{
+		events[0].EventID.String(): nil,
+		events[1].EventID.String(): deliveryErr,
+		events[2].EventID.String(): nil,
+	}}
+
+	NewRelay(repo, prod, time.Second, time.Minute, 10).poll(context.Background())
+
+	if len(repo.sent) != 2 || repo.sent[0] != events[0].EventID || repo.sent[1] != events[2].EventID {
+		t.Errorf("sent = %v, want events 0 and 2", repo.sent)
+	}
+	if !errors.Is(repo.failed[events[1].EventID], deliveryErr) || len(repo.failed) != 1 {
+		t.Errorf("failed = %v, want only event 1", repo.failed)
+	}
+
+	for _, msg := range prod.queued {
+		if got := string(msg.Headers[kafka.HeaderContentType]); got != "application/json" {
+			t.Errorf("content-type = %q, want application/json", got)
+		}
+	}
+}
+
+func TestRelayPoll_StopsWhenNotQueued(t *testing.T) {
+	events := newEvents(3)
+
+	repo := &mockRepo{events: events}
+	prod := &mockProducer{results: map[string]error{
+		events[0].EventID.String(): nil,
+		events[2].EventID.String(): nil,
+	}}
+
+	NewRelay(repo, prod, time.Second, time.Minute, 10).poll(context.Background())
+
+	// после первой ошибки постановки остальные события ждут следующего опроса, порядок сохраняется
+	if len(prod.queued) != 1 {
+		t.Errorf("queued = %d, want 1", len(prod.queued))
+	}
+	if len(repo.sent) != 1 || repo.sent[0] != events[0].EventID {
+		t.Errorf("sent = %v, want only event 0", repo.sent)
+	}
+	if len(repo.failed) != 0 {
+		t.Errorf("failed = %v, want none", repo.failed)
+	}
+}
+
+func TestRelayPoll_MemKafka(t *testing.T) {
+	events := newEvents(4)
+	for i := range events {
+		events[i].Key = []byte(uuid.NewString())
+	}
+	events[3].Key = events[0].Key
+
+	broker := memkafka.NewBroker(memkafka.WithPartitions(3))
+	repo := &mockRepo{events: events}
+
+	NewRelay(repo, memkafka.NewProducer(broker, "user-registered"), time.Second, time.Minute, 10).poll(context.Background())
+
+	if len(repo.sent) != 4 || len(repo.failed) != 0 {
+		t.Fatalf("sent = %d, failed = %d, want 4 and 0", len(repo.sent), len(repo.failed))
+	}
+
+	msgs := broker.Messages("user-registered")
+	if len(msgs) != 4 {
+		t.Fatalf("messages = %d, want 4", len(msgs))
+	}
+	for _, msg := range msgs {
+		// события одного пользователя в одной партиции, как у продюсера с WithHashPartitioner
+		if want := producer.HashPartition(msg.Key, 3); msg.Partition != want {
+			t.Errorf("partition = %d, want %d", msg.Partition, want)
+		}
+		if msg.ContentType() != "application/json" {
+			t.Errorf("content-type = %q, want application/json", msg.ContentType())
+		}
+	}
+}
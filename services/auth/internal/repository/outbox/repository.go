@@ -20,13 +20,13 @@ func New(txManager *transaction.Manager) *Repository {
 
 func (r *Repository) Insert(ctx context.Context, event model.OutboxEvent) error {
 	const query = `
-		INSERT INTO outbox (event_id, topic, key, value, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO outbox (event_id, topic, key, value, content_type, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
 	_, err := r.txManager.ExtractExecutor(ctx).Exec(
 		ctx, query,
-		event.EventID, event.Topic, event.Key, event.Value, event.CreatedAt,
+		event.EventID, event.Topic, event.Key, event.Value, event.ContentType, event.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("outbox insert: %w", err)
@@ -38,7 +38,7 @@ func (r *Repository) Insert(ctx context.Context, event model.OutboxEvent) error
 // FOR UPDATE SKIP LOCKED - блокировка строк + скип заблокированных (возможность запустить несколько relay)
 func (r *Repository) FetchUnsent(ctx context.Context, limit int) ([]model.OutboxEvent, error) {
 	const query = `
-		SELECT event_id, topic, key, value, created_at
+		SELECT event_id, topic, key, value, content_type, created_at
 		FROM outbox
 		WHERE NOT is_sent
 		ORDER BY created_at
@@ -55,7 +55,7 @@ func (r *Repository) FetchUnsent(ctx context.Context, limit int) ([]model.Outbox
 	var events []model.OutboxEvent
 	for rows.Next() {
 		var e model.OutboxEvent
-		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.ContentType, &e.CreatedAt); err != nil {
 			return nil, fmt.Errorf("outbox scan: %w", err)
 		}
 		events = append(events, e)
@@ -75,6 +75,18 @@ func (r *Repository) MarkSent(ctx context.Context, eventID uuid.UUID) error {
 	return nil
 }
 
+// MarkFailed - брокер не подтвердил событие: оно остаётся неотправленным и уйдёт при следующем опросе.
+func (r *Repository) MarkFailed(ctx context.Context, eventID uuid.UUID, sendErr error) error {
+	const query = `UPDATE outbox SET attempts = attempts + 1, last_error = $2 WHERE event_id = $1`
+
+	_, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, eventID, sendErr.Error())
+	if err != nil {
+		return fmt.Errorf("outbox mark failed: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) DeleteSent(ctx context.Context) error {
 	const query = `DELETE FROM outbox WHERE is_sent`
 
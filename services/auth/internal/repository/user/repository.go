@@ -55,18 +55,51 @@ func (r *Repository) ConfirmEmail(ctx context.Context, userID uuid.UUID) error {
 
 func (r *Repository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
 	const query = `
-		SELECT id, email, hashed_password, is_email_confirmed, created_at
+		SELECT id, email, hashed_password, is_email_confirmed, roles, created_at
 		FROM users
 		WHERE email = $1
 	`
 
-	var user model.User
+	return scanUser(r.txManager.ExtractExecutor(ctx).QueryRow(ctx, query, email))
+}
+
+func (r *Repository) GetByID(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	const query = `
+		SELECT id, email, hashed_password, is_email_confirmed, roles, created_at
+		FROM users
+		WHERE id = $1
+	`
+
+	return scanUser(r.txManager.ExtractExecutor(ctx).QueryRow(ctx, query, userID))
+}
+
+func (r *Repository) SetRoles(ctx context.Context, userID uuid.UUID, roles []model.Role) error {
+	const query = `UPDATE users SET roles = $1 WHERE id = $2`
+
+	ct, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, model.RoleStrings(roles), userID)
+	if err != nil {
+		return err
+	}
 
-	err := r.txManager.ExtractExecutor(ctx).QueryRow(ctx, query, email).Scan(
+	if ct.RowsAffected() == 0 {
+		return model.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func scanUser(row pgx.Row) (*model.User, error) {
+	var (
+		user  model.User
+		roles []string
+	)
+
+	err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.HashedPassword,
 		&user.IsEmailConfirmed,
+		&roles,
 		&user.CreatedAt,
 	)
 	if err != nil {
@@ -77,5 +110,10 @@ func (r *Repository) GetByEmail(ctx context.Context, email string) (*model.User,
 		return nil, err
 	}
 
+	user.Roles = make([]model.Role, len(roles))
+	for i, r := range roles {
+		user.Roles[i] = model.Role(r)
+	}
+
 	return &user, nil
 }
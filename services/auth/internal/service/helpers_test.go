@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
 )
 
@@ -23,6 +24,8 @@ type mockUserRepo struct {
 	createFn       func(ctx context.Context, user *model.User) error
 	getByEmailFn   func(ctx context.Context, email string) (*model.User, error)
 	confirmEmailFn func(ctx context.Context, userID uuid.UUID) error
+	getByIDFn      func(ctx context.Context, userID uuid.UUID) (*model.User, error)
+	setRolesFn     func(ctx context.Context, userID uuid.UUID, roles []model.Role) error
 	createCalled   bool
 }
 
@@ -42,6 +45,17 @@ func (m *mockUserRepo) ConfirmEmail(ctx context.Context, userID uuid.UUID) error
 	return nil
 }
 
+func (m *mockUserRepo) GetByID(ctx context.Context, userID uuid.UUID) (*model.User, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, userID)
+	}
+	return &model.User{ID: userID, Roles: []model.Role{model.RoleUser}}, nil
+}
+
+func (m *mockUserRepo) SetRoles(ctx context.Context, userID uuid.UUID, roles []model.Role) error {
+	return m.setRolesFn(ctx, userID, roles)
+}
+
 type mockTokenRepo struct {
 	saveFn       func(ctx context.Context, refreshToken string, userID uuid.UUID, ttl time.Duration) error
 	validateFn   func(ctx context.Context, refreshToken string, userID uuid.UUID) error
@@ -111,7 +125,7 @@ func (m *mockTxManager) Wrap(ctx context.Context, fn func(ctx context.Context) e
 func newTestService(userRepo *mockUserRepo, tokenRepo *mockTokenRepo) *Service {
 	return New(
 		userRepo, tokenRepo, &mockVerificationRepo{}, &mockOutboxRepo{}, &mockTxManager{},
-		testJWTSecret, "test-topic",
+		testJWTSecret, "test-topic", envelope.ContentTypeJSON,
 		testAccessTTL, testRefreshTTL, testVerificationTTL,
 	)
 }
@@ -119,7 +133,7 @@ func newTestService(userRepo *mockUserRepo, tokenRepo *mockTokenRepo) *Service {
 func newTestServiceWithVerification(userRepo *mockUserRepo, tokenRepo *mockTokenRepo, verificationRepo *mockVerificationRepo) *Service {
 	return New(
 		userRepo, tokenRepo, verificationRepo, &mockOutboxRepo{}, &mockTxManager{},
-		testJWTSecret, "test-topic",
+		testJWTSecret, "test-topic", envelope.ContentTypeJSON,
 		testAccessTTL, testRefreshTTL, testVerificationTTL,
 	)
 }
@@ -136,5 +150,6 @@ func testUser(t *testing.T) *model.User {
 		ID:             uuid.Must(uuid.NewV7()),
 		Email:          "user@example.com",
 		HashedPassword: string(hash),
+		Roles:          []model.Role{model.RoleUser},
 	}
 }
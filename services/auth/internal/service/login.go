@@ -22,7 +22,7 @@ func (s *Service) Login(ctx context.Context, email, password string) (*model.Tok
 		return nil, fmt.Errorf("login error: %w", err)
 	}
 
-	pair, err := model.NewTokenPair(user.ID, s.jwtSecret, s.accessTTL)
+	pair, err := model.NewTokenPair(user.ID, user.Roles, s.jwtSecret, s.accessTTL)
 	if err != nil {
 		return nil, fmt.Errorf("login error: %w", err)
 	}
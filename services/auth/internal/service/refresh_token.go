@@ -14,11 +14,17 @@ func (s *Service) RefreshToken(ctx context.Context, userID uuid.UUID, oldRefresh
 		return nil, fmt.Errorf("token validate error: %w", err)
 	}
 
+	// роли читаются заново: изменение ролей вступает в силу при следующем refresh
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get user error: %w", err)
+	}
+
 	if err := s.tokenRepo.Delete(ctx, userID); err != nil {
 		return nil, fmt.Errorf("token delete error: %w", err)
 	}
 
-	pair, err := model.NewTokenPair(userID, s.jwtSecret, s.accessTTL)
+	pair, err := model.NewTokenPair(userID, user.Roles, s.jwtSecret, s.accessTTL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token pair: %w", err)
 	}
@@ -91,3 +91,26 @@ func TestRefreshToken_SaveError(t *testing.T) {
 		t.Errorf("error = %v, want %v", err, redisErr)
 	}
 }
+
+func TestRefreshToken_UserNotFound(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+
+	userRepo := &mockUserRepo{
+		getByIDFn: func(_ context.Context, _ uuid.UUID) (*model.User, error) {
+			return nil, model.ErrUserNotFound
+		},
+	}
+	tokenRepo := &mockTokenRepo{
+		validateFn: func(_ context.Context, _ string, _ uuid.UUID) error { return nil },
+	}
+	svc := newTestService(userRepo, tokenRepo)
+
+	_, err := svc.RefreshToken(context.Background(), userID, "old-token")
+	if !errors.Is(err, model.ErrUserNotFound) {
+		t.Errorf("error = %v, want ErrUserNotFound", err)
+	}
+
+	if tokenRepo.deleteCalled {
+		t.Error("tokenRepo.Delete was called, want old token kept")
+	}
+}
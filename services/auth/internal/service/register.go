@@ -2,22 +2,17 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
 )
 
-type UserRegisteredEvent struct {
-	EventID   string    `json:"event_id"`
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
-	Code      string    `json:"code"`
-	CreatedAt time.Time `json:"created_at"`
-}
+const eventProducer = "auth"
 
 func (s *Service) Register(ctx context.Context, email, password string) (uuid.UUID, error) {
 	user, err := model.NewUser(email, password)
@@ -54,24 +49,28 @@ func (s *Service) Register(ctx context.Context, email, password string) (uuid.UU
 }
 
 func (s *Service) buildOutboxEvent(user *model.User, code string) (model.OutboxEvent, error) {
-	event := UserRegisteredEvent{
-		EventID:   uuid.New().String(),
-		UserID:    user.ID.String(),
-		Email:     user.Email,
-		Code:      code,
-		CreatedAt: time.Now(),
-	}
+	eventID := uuid.New()
+	createdAt := time.Now()
 
-	value, err := json.Marshal(event)
+	value, err := envelope.Encode(&eventsv1.UserRegistered{
+		UserId: user.ID.String(),
+		Email:  user.Email,
+		Code:   code,
+	}, envelope.Meta{
+		ID:         eventID.String(),
+		OccurredAt: createdAt,
+		Producer:   eventProducer,
+	}, s.eventContentType)
 	if err != nil {
-		return model.OutboxEvent{}, fmt.Errorf("marshal event: %w", err)
+		return model.OutboxEvent{}, fmt.Errorf("encode event: %w", err)
 	}
 
 	return model.OutboxEvent{
-		EventID:   uuid.MustParse(event.EventID),
-		Topic:     s.kafkaTopic,
-		Key:       []byte(user.ID.String()),
-		Value:     value,
-		CreatedAt: event.CreatedAt,
+		EventID:     eventID,
+		Topic:       s.kafkaTopic,
+		Key:         []byte(user.ID.String()),
+		Value:       value,
+		ContentType: s.eventContentType,
+		CreatedAt:   createdAt,
 	}, nil
 }
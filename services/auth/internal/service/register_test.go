@@ -7,6 +7,9 @@ import (
 
 	"github.com/google/uuid"
 
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
 )
 
@@ -70,3 +73,50 @@ func TestRegister_RepoError(t *testing.T) {
 		t.Errorf("error = %v, want %v", err, repoErr)
 	}
 }
+
+func TestRegister_OutboxEventContract(t *testing.T) {
+	for _, contentType := range []string{envelope.ContentTypeJSON, envelope.ContentTypeProtobuf} {
+		t.Run(contentType, func(t *testing.T) {
+			var inserted model.OutboxEvent
+			outboxRepo := &mockOutboxRepo{
+				insertFn: func(_ context.Context, e model.OutboxEvent) error { inserted = e; return nil },
+			}
+			svc := New(
+				&mockUserRepo{createFn: func(_ context.Context, _ *model.User) error { return nil }},
+				&mockTokenRepo{}, &mockVerificationRepo{}, outboxRepo, &mockTxManager{},
+				testJWTSecret, "test-topic", contentType,
+				testAccessTTL, testRefreshTTL, testVerificationTTL,
+			)
+
+			userID, err := svc.Register(context.Background(), "user@example.com", "password")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if inserted.ContentType != contentType {
+				t.Errorf("content type = %q, want %q", inserted.ContentType, contentType)
+			}
+
+			msg := kafka.Message{
+				Value:   inserted.Value,
+				Headers: map[string][]byte{kafka.HeaderContentType: []byte(inserted.ContentType)},
+			}
+
+			var payload eventsv1.UserRegistered
+			env, err := envelope.Decode(msg, &payload)
+			if err != nil {
+				t.Fatalf("decode outbox event: %v", err)
+			}
+
+			if env.GetId() != inserted.EventID.String() {
+				t.Errorf("envelope id = %q, want %q", env.GetId(), inserted.EventID)
+			}
+			if env.GetProducer() != "auth" {
+				t.Errorf("producer = %q, want auth", env.GetProducer())
+			}
+			if payload.GetUserId() != userID.String() || payload.GetEmail() != "user@example.com" || payload.GetCode() == "" {
+				t.Errorf("payload = %v", &payload)
+			}
+		})
+	}
+}
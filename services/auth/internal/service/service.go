@@ -13,6 +13,8 @@ type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	ConfirmEmail(ctx context.Context, userID uuid.UUID) error
+	GetByID(ctx context.Context, userID uuid.UUID) (*model.User, error)
+	SetRoles(ctx context.Context, userID uuid.UUID, roles []model.Role) error
 }
 
 type TokenRepository interface {
@@ -43,6 +45,7 @@ type Service struct {
 	txManager        TxManager
 	jwtSecret        string
 	kafkaTopic       string
+	eventContentType string
 	accessTTL        time.Duration
 	refreshTTL       time.Duration
 	verificationTTL  time.Duration
@@ -56,6 +59,7 @@ func New(
 	txManager TxManager,
 	jwtSecret string,
 	kafkaTopic string,
+	eventContentType string,
 	accessTTL time.Duration,
 	refreshTTL time.Duration,
 	verificationTTL time.Duration,
@@ -68,6 +72,7 @@ func New(
 		txManager:        txManager,
 		jwtSecret:        jwtSecret,
 		kafkaTopic:       kafkaTopic,
+		eventContentType: eventContentType,
 		accessTTL:        accessTTL,
 		refreshTTL:       refreshTTL,
 		verificationTTL:  verificationTTL,
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
+)
+
+// SetUserRoles заменяет роли пользователя. Уже выданные access токены живут со старыми ролями до истечения.
+func (s *Service) SetUserRoles(ctx context.Context, userID uuid.UUID, rawRoles []string) error {
+	roles, err := model.ParseRoles(rawRoles)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.SetRoles(ctx, userID, roles); err != nil {
+		return fmt.Errorf("set user roles: %w", err)
+	}
+
+	return nil
+}
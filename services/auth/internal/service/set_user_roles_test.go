@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/auth/internal/model"
+)
+
+func TestSetUserRoles_Success(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+
+	var saved []model.Role
+	userRepo := &mockUserRepo{
+		setRolesFn: func(_ context.Context, id uuid.UUID, roles []model.Role) error {
+			if id != userID {
+				t.Errorf("userID = %v, want %v", id, userID)
+			}
+			saved = roles
+			return nil
+		},
+	}
+	svc := newTestService(userRepo, &mockTokenRepo{})
+
+	if err := svc.SetUserRoles(context.Background(), userID, []string{"moderator"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []model.Role{model.RoleUser, model.RoleModerator}
+	if !slices.Equal(saved, want) {
+		t.Errorf("roles = %v, want %v", saved, want)
+	}
+}
+
+func TestSetUserRoles_InvalidRole(t *testing.T) {
+	userRepo := &mockUserRepo{
+		setRolesFn: func(_ context.Context, _ uuid.UUID, _ []model.Role) error {
+			t.Error("userRepo.SetRoles was called, want skipped on invalid role")
+			return nil
+		},
+	}
+	svc := newTestService(userRepo, &mockTokenRepo{})
+
+	err := svc.SetUserRoles(context.Background(), uuid.Must(uuid.NewV7()), []string{"superuser"})
+	if !errors.Is(err, model.ErrInvalidRole) {
+		t.Errorf("error = %v, want ErrInvalidRole", err)
+	}
+}
+
+func TestSetUserRoles_UserNotFound(t *testing.T) {
+	userRepo := &mockUserRepo{
+		setRolesFn: func(_ context.Context, _ uuid.UUID, _ []model.Role) error {
+			return model.ErrUserNotFound
+		},
+	}
+	svc := newTestService(userRepo, &mockTokenRepo{})
+
+	err := svc.SetUserRoles(context.Background(), uuid.Must(uuid.NewV7()), []string{"admin"})
+	if !errors.Is(err, model.ErrUserNotFound) {
+		t.Errorf("error = %v, want ErrUserNotFound", err)
+	}
+}
@@ -3,26 +3,40 @@ package app
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	gatewayv2 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v2"
+	"github.com/SonOfSteveJobs/habr/pkg/health"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/pkg/requestid"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/config"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/httpcache"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/ratelimit"
 )
 
 type App struct {
 	infra   *infraContainer
 	service *serviceContainer
+	health  *health.Health
+
+	// общие для всех версий API: ключи кэша и лимитов не пересекаются, потому что содержат путь с версией
+	httpCache   *httpcache.LRU
+	rateLimiter ratelimit.Limiter
 
 	router     chi.Router
 	httpServer *http.Server
+	// healthServer - полный отчёт по зависимостям на внутреннем порту, на основном только статус
+	healthServer *http.Server
 }
 
 func New(ctx context.Context) (*App, error) {
@@ -47,6 +61,12 @@ func (a *App) Run() {
 		}
 	}()
 
+	go func() {
+		if err := a.healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("health HTTP server failed")
+		}
+	}()
+
 	closer.Wait()
 }
 
@@ -62,8 +82,9 @@ func (a *App) initDeps(ctx context.Context) error {
 		{"tracing", a.initTracing},
 		{"otel-logger", a.initOTelLogger},
 		{"metrics", a.initMetrics},
-		{"infra: grpc connections", a.initInfra},
+		{"infra: grpc connections, redis", a.initInfra},
 		{"service: clients", a.initService},
+		{"health", a.initHealth},
 		{"HTTP router", a.initRouter},
 		{"HTTP server", a.initHTTPServer},
 	}
@@ -79,8 +100,8 @@ func (a *App) initDeps(ctx context.Context) error {
 	return nil
 }
 
-func (a *App) initInfra(_ context.Context) error {
-	infra, err := newInfraContainer()
+func (a *App) initInfra(ctx context.Context) error {
+	infra, err := newInfraContainer(ctx)
 	if err != nil {
 		return err
 	}
@@ -93,6 +114,31 @@ func (a *App) initService(_ context.Context) error {
 	return nil
 }
 
+// initHealth - gateway готов, пока не начался shutdown. Upstream и Redis только в отчёте:
+// без них gateway отвечает 503 на часть запросов, а снятие всех подов с балансировки сделало бы хуже.
+// Отчёт с ошибками зависимостей отдаётся только на HEALTH_HTTP_PORT.
+func (a *App) initHealth(_ context.Context) error {
+	closer.SetDrainDelay(config.AppConfig().DrainDelay())
+	a.health = health.New(closer.Draining())
+	a.health.AddOptional("auth", health.GRPC(a.infra.AuthConn()))
+	a.health.AddOptional("article", health.GRPC(a.infra.ArticleConn()))
+	if client := a.infra.RedisClient(); client != nil {
+		a.health.AddOptional("redis", health.Redis(client))
+	}
+
+	a.healthServer = &http.Server{
+		Addr:              config.AppConfig().HealthHTTPPort(),
+		Handler:           a.health.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	closer.AddNamed("health HTTP server", func(ctx context.Context) error {
+		return a.healthServer.Shutdown(ctx)
+	})
+
+	return nil
+}
+
 func (a *App) initTracing(ctx context.Context) error {
 	if err := tracing.InitTracer(ctx, config.AppConfig().Tracing()); err != nil {
 		return err
@@ -123,24 +169,129 @@ func (a *App) initMetrics(ctx context.Context) error {
 	return nil
 }
 
-func (a *App) initRouter(_ context.Context) error {
+func (a *App) initRouter(ctx context.Context) error {
 	cfg := config.AppConfig()
 	r := chi.NewRouter()
 
 	r.Use(tracing.HTTPMiddleware())
 	r.Use(metrics.HTTPMiddleware())
+	r.Use(requestid.HTTPMiddleware())
+	r.Use(middleware.RealIP(cfg.Security().TrustedProxies()))
+	r.Use(middleware.AccessLog())
+	r.Use(middleware.SecurityHeaders(cfg.Security().HSTSMaxAge()))
+	r.Use(middleware.CORS(middleware.CORSOptions{
+		AllowedOrigins:   cfg.Security().CORSAllowedOrigins(),
+		AllowCredentials: cfg.Security().AuthCookie() != nil,
+		MaxAge:           cfg.Security().CORSMaxAge(),
+	}))
+	r.Use(middleware.NegotiateVersion(apiVersions, cfg.API().DefaultVersion(), r))
+
+	r.Get("/healthz", a.health.LivenessHandler())
+	r.Get("/readyz", a.health.ReadinessStatusHandler())
+
+	a.httpCache = httpCacheStore(cfg.HTTPCacheSize())
+	if cfg.RateLimit().Enabled() {
+		a.rateLimiter = a.newRateLimiter()
+	}
 
+	v1, err := a.apiMiddlewares(ctx, apiV1, gatewayv1.GetSwagger, v1Deprecations(cfg.API().V1Sunset()))
+	if err != nil {
+		return err
+	}
 	gatewayv1.HandlerWithOptions(a.service.Handler(), gatewayv1.ChiServerOptions{
-		BaseRouter: r,
-		Middlewares: []gatewayv1.MiddlewareFunc{
-			middleware.Auth(cfg.JWTSecret()),
-		},
+		BaseRouter:       r,
+		Middlewares:      asMiddlewares[gatewayv1.MiddlewareFunc](v1),
+		ErrorHandlerFunc: paramErrorHandler(v1),
+	})
+
+	v2, err := a.apiMiddlewares(ctx, apiV2, gatewayv2.GetSwagger, nil)
+	if err != nil {
+		return err
+	}
+	gatewayv2.HandlerWithOptions(a.service.HandlerV2(), gatewayv2.ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      asMiddlewares[gatewayv2.MiddlewareFunc](v2),
+		ErrorHandlerFunc: paramErrorHandler(v2),
 	})
 
+	if cfg.GraphQL().Enabled() {
+		if err := a.mountGraphQL(r); err != nil {
+			return err
+		}
+	}
+
 	a.router = r
 	return nil
 }
 
+// apiMiddlewares - цепочка одной версии API, порядок как в сгенерированном коде: последний middleware внешний.
+// Versioned снаружи всех, чтобы версия и Deprecation попадали и в ошибки Auth и RateLimit. Auth отрабатывает
+// раньше RateLimit, чтобы лимит считался по пользователю, а Cache после лимита - ответы из кэша тоже учитываются.
+// CSRF касается только мутирующих запросов, которые кэш не трогает, и проверяется уже после лимита.
+// Схема запроса проверяется последней, перед хендлером.
+func (a *App) apiMiddlewares(
+	ctx context.Context,
+	version string,
+	loadSpec func() (*openapi3.T, error),
+	deprecations middleware.Deprecations,
+) ([]func(http.Handler) http.Handler, error) {
+	cfg := config.AppConfig()
+
+	var middlewares []func(http.Handler) http.Handler
+	if cfg.OpenAPI().ValidateRequests() {
+		spec, err := openAPISpec(ctx, loadSpec)
+		if err != nil {
+			return nil, fmt.Errorf("api %s: %w", version, err)
+		}
+		middlewares = append(middlewares, middleware.OpenAPIValidator(spec, middleware.OpenAPIOptions{
+			ValidateResponses: cfg.OpenAPI().ValidateResponses(),
+		}))
+	}
+	var authOpts []middleware.AuthOption
+	if cfg.Security().AuthCookie() != nil {
+		middlewares = append(middlewares, middleware.CSRF())
+		authOpts = append(authOpts, middleware.WithCookieAuth())
+	}
+	middlewares = append(middlewares, middleware.Cache(a.httpCache, cachePolicies))
+	if cfg.RateLimit().Enabled() {
+		middlewares = append(middlewares, middleware.RateLimit(a.rateLimiter, rateLimitPolicies(cfg.RateLimit().DefaultPolicy())))
+	}
+	middlewares = append(middlewares,
+		middleware.Auth(cfg.JWTSecret(), authOpts...),
+		middleware.Versioned(version, deprecations),
+	)
+
+	return middlewares, nil
+}
+
+// mountGraphQL - свой набор middleware: спецификации OpenAPI и HTTP кэша у POST /graphql нет,
+// а Auth нужен только для пользователя в контексте - маршрут публичный, как GET статей.
+func (a *App) mountGraphQL(r chi.Router) error {
+	cfg := config.AppConfig()
+
+	h, err := a.service.GraphQLHandler()
+	if err != nil {
+		return err
+	}
+
+	var authOpts []middleware.AuthOption
+	if cfg.Security().AuthCookie() != nil {
+		authOpts = append(authOpts, middleware.WithCookieAuth())
+	}
+
+	// в r.With первый middleware внешний - порядок тот же, что у REST: Auth, RateLimit, CSRF
+	middlewares := []func(http.Handler) http.Handler{middleware.Auth(cfg.JWTSecret(), authOpts...)}
+	if cfg.RateLimit().Enabled() {
+		middlewares = append(middlewares, middleware.RateLimit(a.rateLimiter, rateLimitPolicies(cfg.RateLimit().DefaultPolicy())))
+	}
+	if cfg.Security().AuthCookie() != nil {
+		middlewares = append(middlewares, middleware.CSRF())
+	}
+
+	r.With(middlewares...).Post("/graphql", h.ServeHTTP)
+	return nil
+}
+
 func (a *App) initHTTPServer(_ context.Context) error {
 	cfg := config.AppConfig()
 
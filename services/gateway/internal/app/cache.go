@@ -0,0 +1,29 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/httpcache"
+)
+
+// cachePolicies - событий об изменении статей нет, поэтому свежесть держится короткими TTL.
+// Статья по id не хранится: каждый запрос должен дойти до article, иначе не засчитается просмотр.
+// Ей достаётся только ETag и 304.
+var cachePolicies = middleware.CachePolicies{
+	http.MethodGet + " /api/v1/articles":              {TTL: 5 * time.Second},
+	http.MethodGet + " /api/v1/articles/most-read":    {TTL: 30 * time.Second},
+	http.MethodGet + " /api/v1/articles/{id}/related": {TTL: time.Minute},
+	http.MethodGet + " /api/v1/articles/{id}":         {TTL: 0},
+	http.MethodGet + " /api/v2/articles":              {TTL: 5 * time.Second},
+	http.MethodGet + " /api/v2/articles/{id}":         {TTL: 0},
+}
+
+func httpCacheStore(size int) *httpcache.LRU {
+	if size == 0 {
+		return nil
+	}
+
+	return httpcache.NewLRU(size)
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
 	"github.com/SonOfSteveJobs/habr/pkg/grpclog"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/pkg/requestid"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/config"
 )
@@ -17,9 +20,10 @@ import (
 type infraContainer struct {
 	authConn    *grpc.ClientConn
 	articleConn *grpc.ClientConn
+	redisClient *redis.Client
 }
 
-func newInfraContainer() (*infraContainer, error) {
+func newInfraContainer(ctx context.Context) (*infraContainer, error) {
 	c := &infraContainer{}
 
 	if err := c.initAuthConn(); err != nil {
@@ -30,12 +34,19 @@ func newInfraContainer() (*infraContainer, error) {
 		return nil, fmt.Errorf("article grpc conn: %w", err)
 	}
 
+	if err := c.initRedisClient(ctx); err != nil {
+		return nil, fmt.Errorf("redis: %w", err)
+	}
+
 	return c, nil
 }
 
 func (c *infraContainer) AuthConn() *grpc.ClientConn    { return c.authConn }
 func (c *infraContainer) ArticleConn() *grpc.ClientConn { return c.articleConn }
 
+// RedisClient - nil, если REDIS_ADDR не задан.
+func (c *infraContainer) RedisClient() *redis.Client { return c.redisClient }
+
 func (c *infraContainer) initAuthConn() error {
 	conn, err := grpc.NewClient(
 		config.AppConfig().AuthGRPCAddr(),
@@ -43,8 +54,11 @@ func (c *infraContainer) initAuthConn() error {
 		grpc.WithChainUnaryInterceptor(
 			tracing.UnaryClientInterceptor(),
 			metrics.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
 			grpclog.UnaryClientInterceptor(),
 		),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(resilienceInterceptors("auth")...),
 	)
 	if err != nil {
 		return err
@@ -64,8 +78,11 @@ func (c *infraContainer) initArticleConn() error {
 		grpc.WithChainUnaryInterceptor(
 			tracing.UnaryClientInterceptor(),
 			metrics.UnaryClientInterceptor(),
+			requestid.UnaryClientInterceptor(),
 			grpclog.UnaryClientInterceptor(),
 		),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(resilienceInterceptors("article")...),
 	)
 	if err != nil {
 		return err
@@ -77,3 +94,32 @@ func (c *infraContainer) initArticleConn() error {
 	c.articleConn = conn
 	return nil
 }
+
+func (c *infraContainer) initRedisClient(ctx context.Context) error {
+	addr := config.AppConfig().RateLimit().RedisAddr()
+	if addr == "" {
+		return nil
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr: addr,
+	})
+
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		return err
+	}
+
+	if err := redisotel.InstrumentMetrics(client); err != nil {
+		return err
+	}
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return err
+	}
+	closer.AddNamed("redis", func(_ context.Context) error {
+		return client.Close()
+	})
+
+	c.redisClient = client
+	return nil
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// openAPISpec - спецификация, встроенная в сгенерированный код версии (GetSwagger).
+// Servers убираются: маршрут сверяется по шаблону chi, а не по хосту.
+func openAPISpec(ctx context.Context, load func() (*openapi3.T, error)) (*openapi3.T, error) {
+	spec, err := load()
+	if err != nil {
+		return nil, fmt.Errorf("load openapi spec: %w", err)
+	}
+	spec.Servers = nil
+
+	if err := spec.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("validate openapi spec: %w", err)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/ratelimit"
+)
+
+// rateLimitPolicies - строгие лимиты на перебор паролей, регистрацию и тяжёлые операции, остальное по умолчанию.
+func rateLimitPolicies(def ratelimit.Policy) middleware.RateLimitPolicies {
+	return middleware.RateLimitPolicies{
+		Default: def,
+		Routes: map[string]ratelimit.Policy{
+			http.MethodPost + " /api/v1/auth/login":           {Name: "login", Limit: 5, Window: time.Minute},
+			http.MethodPost + " /api/v1/auth/register":        {Name: "register", Limit: 3, Window: time.Minute},
+			http.MethodPost + " /api/v1/auth/refresh":         {Name: "refresh", Limit: 10, Window: time.Minute},
+			http.MethodPost + " /api/v1/auth/verify-email":    {Name: "verify-email", Limit: 5, Window: time.Minute},
+			http.MethodPost + " /api/v1/articles":             {Name: "create-article", Limit: 10, Window: time.Minute},
+			http.MethodPost + " /api/v1/articles/import":      {Name: "import", Limit: 2, Window: time.Minute},
+			http.MethodGet + " /api/v1/articles/export":       {Name: "export", Limit: 2, Window: time.Minute},
+			http.MethodPost + " /api/v1/articles/{id}/report": {Name: "report", Limit: 10, Window: time.Minute},
+			http.MethodPost + " /graphql":                     {Name: "graphql", Limit: 60, Window: time.Minute},
+		},
+	}
+}
+
+// newRateLimiter - общий лимит в Redis с откатом в память инстанса, без Redis - только память.
+func (a *App) newRateLimiter() ratelimit.Limiter {
+	memory := ratelimit.NewMemoryLimiter()
+
+	client := a.infra.RedisClient()
+	if client == nil {
+		return memory
+	}
+
+	return ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(client), memory)
+}
@@ -3,8 +3,11 @@ package app
 import (
 	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
 	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/config"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/graphql"
 	gatewayhttp "github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/article"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/articlev2"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/auth"
 )
 
@@ -14,6 +17,8 @@ type serviceContainer struct {
 	authClient    authv1.AuthServiceClient
 	articleClient articlev1.ArticleServiceClient
 	handler       *gatewayhttp.Handler
+	handlerV2     *articlev2.Handler
+	graphQL       *graphql.Handler
 }
 
 func newServiceContainer(infra *infraContainer) *serviceContainer {
@@ -39,10 +44,49 @@ func (c *serviceContainer) ArticleClient() articlev1.ArticleServiceClient {
 func (c *serviceContainer) Handler() *gatewayhttp.Handler {
 	if c.handler == nil {
 		c.handler = gatewayhttp.New(
-			auth.New(c.AuthClient()),
+			auth.New(c.AuthClient(), authCookies()),
 			article.New(c.ArticleClient()),
 		)
 	}
 
 	return c.handler
 }
+
+// HandlerV2 - в v2 пока только статьи, остальное клиенты берут из v1.
+func (c *serviceContainer) HandlerV2() *articlev2.Handler {
+	if c.handlerV2 == nil {
+		c.handlerV2 = articlev2.New(c.ArticleClient())
+	}
+
+	return c.handlerV2
+}
+
+func (c *serviceContainer) GraphQLHandler() (*graphql.Handler, error) {
+	if c.graphQL == nil {
+		cfg := config.AppConfig().GraphQL()
+		h, err := graphql.New(c.ArticleClient(), graphql.Options{
+			MaxDepth:      cfg.MaxDepth(),
+			MaxComplexity: cfg.MaxComplexity(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.graphQL = h
+	}
+
+	return c.graphQL, nil
+}
+
+func authCookies() *auth.CookieConfig {
+	cfg := config.AppConfig().Security().AuthCookie()
+	if cfg == nil {
+		return nil
+	}
+
+	return &auth.CookieConfig{
+		Domain:   cfg.Domain(),
+		Secure:   cfg.Secure(),
+		SameSite: cfg.SameSite(),
+		MaxAge:   cfg.MaxAge(),
+	}
+}
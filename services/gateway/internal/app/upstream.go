@@ -0,0 +1,50 @@
+package app
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/grpcresilience"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/config"
+)
+
+const (
+	retryBaseDelay = 50 * time.Millisecond
+	retryMaxDelay  = time.Second
+)
+
+// upstreamTimeouts - дедлайны методов, которым не подходит UPSTREAM_TIMEOUT.
+var upstreamTimeouts = map[string]time.Duration{
+	// bcrypt и запись в outbox
+	authv1.AuthService_Register_FullMethodName: 10 * time.Second,
+	authv1.AuthService_Login_FullMethodName:    10 * time.Second,
+	// чтение из кэша, дольше ждать нет смысла - лучше повторить
+	articlev1.ArticleService_GetArticle_FullMethodName:   2 * time.Second,
+	articlev1.ArticleService_ListArticles_FullMethodName: 2 * time.Second,
+	articlev1.ArticleService_ListMostRead_FullMethodName: 2 * time.Second,
+}
+
+// resilienceInterceptors - breaker на upstream, повторы идемпотентных вызовов и дедлайн на каждую попытку.
+// Порядок важен: серия повторов считается breaker одним отказом.
+func resilienceInterceptors(upstream string) []grpc.UnaryClientInterceptor {
+	cfg := config.AppConfig().Upstream()
+
+	breaker := grpcresilience.NewBreaker(grpcresilience.BreakerConfig{
+		Name:             upstream,
+		FailureThreshold: cfg.BreakerFailureThreshold(),
+		OpenTimeout:      cfg.BreakerOpenTimeout(),
+	})
+
+	return []grpc.UnaryClientInterceptor{
+		grpcresilience.BreakerUnaryClientInterceptor(breaker),
+		grpcresilience.RetryUnaryClientInterceptor(grpcresilience.RetryConfig{
+			MaxAttempts: cfg.MaxAttempts(),
+			BaseDelay:   retryBaseDelay,
+			MaxDelay:    retryMaxDelay,
+		}, grpcresilience.IsIdempotent),
+		grpcresilience.TimeoutUnaryClientInterceptor(cfg.Timeout(), upstreamTimeouts),
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+const (
+	apiV1 = "v1"
+	apiV2 = "v2"
+)
+
+// apiVersions - смонтированные версии API, каждая из своего gateway.yaml.
+var apiVersions = []string{apiV1, apiV2}
+
+// v2Released - с этой даты у статей v1 есть замена, от неё считается Deprecation.
+var v2Released = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// v1Deprecations - маршруты v1, которые v2 отдаёт в новой форме. Остальные v1 актуальны.
+func v1Deprecations(sunset time.Time) middleware.Deprecations {
+	return middleware.Deprecations{
+		http.MethodGet + " /api/v1/articles":      {Since: v2Released, Sunset: sunset, Successor: "/api/v2/articles"},
+		http.MethodGet + " /api/v1/articles/{id}": {Since: v2Released, Sunset: sunset, Successor: "/api/v2/articles/{id}"},
+	}
+}
+
+// asMiddlewares - у каждой версии свой именованный тип MiddlewareFunc с одинаковой сигнатурой.
+func asMiddlewares[M ~func(http.Handler) http.Handler](mws []func(http.Handler) http.Handler) []M {
+	out := make([]M, 0, len(mws))
+	for _, mw := range mws {
+		out = append(out, mw)
+	}
+
+	return out
+}
+
+// paramErrorHandler - ErrorHandlerFunc сгенерированной обёртки. Параметры пути и query она разбирает до своих
+// middleware, поэтому ответ пропускается через ту же цепочку версии: валидатор отдаёт ошибку по схеме,
+// а Versioned и RateLimit срабатывают как на обычном запросе.
+func paramErrorHandler(mws []func(http.Handler) http.Handler) func(http.ResponseWriter, *http.Request, error) {
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			utils.WriteProblem(w, r, utils.Problem{
+				Status: http.StatusBadRequest,
+				Detail: err.Error(),
+				Code:   utils.CodeValidationFailed,
+			})
+		})
+		for _, mw := range mws {
+			h = mw(h)
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
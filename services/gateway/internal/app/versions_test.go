@@ -0,0 +1,46 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func TestParamErrorHandler_MalformedID(t *testing.T) {
+	mws := []func(http.Handler) http.Handler{middleware.Versioned(apiV1, nil)}
+
+	r := chi.NewRouter()
+	gatewayv1.HandlerWithOptions(gatewayv1.Unimplemented{}, gatewayv1.ChiServerOptions{
+		BaseRouter:       r,
+		Middlewares:      asMiddlewares[gatewayv1.MiddlewareFunc](mws),
+		ErrorHandlerFunc: paramErrorHandler(mws),
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/not-a-uuid", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != utils.ProblemContentType {
+		t.Errorf("content type = %q, want %q", ct, utils.ProblemContentType)
+	}
+	if v := w.Header().Get("API-Version"); v != apiV1 {
+		t.Errorf("API-Version = %q, want %q", v, apiV1)
+	}
+
+	var p utils.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if p.Code != utils.CodeValidationFailed {
+		t.Errorf("code = %q, want %q", p.Code, utils.CodeValidationFailed)
+	}
+}
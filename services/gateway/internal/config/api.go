@@ -0,0 +1,45 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+const defaultAPIVersion = "v1"
+
+type APIConfig struct {
+	defaultVersion string
+	v1Sunset       time.Time
+}
+
+// DefaultVersion - версия для путей /api/... без /vN и без vendor media type в Accept.
+// Мобильные клиенты старых сборок ходят без версии, поэтому по умолчанию v1.
+func (c *APIConfig) DefaultVersion() string { return c.defaultVersion }
+
+// V1Sunset - дата отключения устаревших маршрутов v1, нулевая пока не назначена.
+func (c *APIConfig) V1Sunset() time.Time { return c.v1Sunset }
+
+func newAPIConfig() (*APIConfig, error) {
+	defaultVersion := os.Getenv("API_DEFAULT_VERSION")
+	switch defaultVersion {
+	case "":
+		defaultVersion = defaultAPIVersion
+	case "v1", "v2":
+	default:
+		return nil, ErrInvalidAPIDefaultVersion
+	}
+
+	var v1Sunset time.Time
+	if v := os.Getenv("API_V1_SUNSET"); v != "" {
+		parsed, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return nil, ErrInvalidAPIV1Sunset
+		}
+		v1Sunset = parsed
+	}
+
+	return &APIConfig{
+		defaultVersion: defaultVersion,
+		v1Sunset:       v1Sunset,
+	}, nil
+}
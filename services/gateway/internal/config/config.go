@@ -2,12 +2,18 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 var appConfig *Config
 
+const (
+	defaultHTTPCacheSize  = 1000
+	defaultHealthHTTPPort = ":8081"
+)
+
 type Config struct {
 	httpPort        string
 	authGRPCAddr    string
@@ -15,14 +21,38 @@ type Config struct {
 	jwtSecret       string
 	logger          LoggerConfig
 	tracing         *TracingConfig
+	rateLimit       *RateLimitConfig
+	upstream        *UpstreamConfig
+	httpCacheSize   int
+	security        *SecurityConfig
+	openAPI         *OpenAPIConfig
+	api             *APIConfig
+	graphQL         *GraphQLConfig
+	drainDelay      time.Duration
+	healthHTTPPort  string
 }
 
-func (c *Config) HTTPPort() string        { return c.httpPort }
-func (c *Config) AuthGRPCAddr() string    { return c.authGRPCAddr }
-func (c *Config) ArticleGRPCAddr() string { return c.articleGRPCAddr }
-func (c *Config) JWTSecret() string       { return c.jwtSecret }
-func (c *Config) Logger() LoggerConfig    { return c.logger }
-func (c *Config) Tracing() *TracingConfig { return c.tracing }
+func (c *Config) HTTPPort() string            { return c.httpPort }
+func (c *Config) AuthGRPCAddr() string        { return c.authGRPCAddr }
+func (c *Config) ArticleGRPCAddr() string     { return c.articleGRPCAddr }
+func (c *Config) JWTSecret() string           { return c.jwtSecret }
+func (c *Config) Logger() LoggerConfig        { return c.logger }
+func (c *Config) Tracing() *TracingConfig     { return c.tracing }
+func (c *Config) RateLimit() *RateLimitConfig { return c.rateLimit }
+func (c *Config) Upstream() *UpstreamConfig   { return c.upstream }
+func (c *Config) Security() *SecurityConfig   { return c.security }
+func (c *Config) OpenAPI() *OpenAPIConfig     { return c.openAPI }
+func (c *Config) API() *APIConfig             { return c.api }
+func (c *Config) GraphQL() *GraphQLConfig     { return c.graphQL }
+
+// HTTPCacheSize - сколько ответов держит LRU, 0 - без хранения, только ETag и 304.
+func (c *Config) HTTPCacheSize() int { return c.httpCacheSize }
+
+// DrainDelay - пауза при shutdown между 503 на /readyz и остановкой HTTP сервера, 0 - без паузы.
+func (c *Config) DrainDelay() time.Duration { return c.drainDelay }
+
+// HealthHTTPPort - внутренний порт с полным отчётом /readyz по зависимостям, наружу не публикуется.
+func (c *Config) HealthHTTPPort() string { return c.healthHTTPPort }
 
 func Load(path ...string) error {
 	err := godotenv.Load(path...)
@@ -60,6 +90,55 @@ func Load(path ...string) error {
 		return err
 	}
 
+	rateLimit, err := newRateLimitConfig()
+	if err != nil {
+		return err
+	}
+
+	upstream, err := newUpstreamConfig()
+	if err != nil {
+		return err
+	}
+
+	httpCacheSize, err := parseNonNegativeInt("HTTP_CACHE_SIZE", defaultHTTPCacheSize, ErrInvalidHTTPCacheSize)
+	if err != nil {
+		return err
+	}
+
+	security, err := newSecurityConfig()
+	if err != nil {
+		return err
+	}
+
+	openAPI, err := newOpenAPIConfig(tracing.Environment())
+	if err != nil {
+		return err
+	}
+
+	api, err := newAPIConfig()
+	if err != nil {
+		return err
+	}
+
+	graphQL, err := newGraphQLConfig()
+	if err != nil {
+		return err
+	}
+
+	var drainDelay time.Duration
+	if v := os.Getenv("SHUTDOWN_DRAIN_DELAY"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			return ErrInvalidDrainDelay
+		}
+		drainDelay = parsed
+	}
+
+	healthHTTPPort := defaultHealthHTTPPort
+	if v := os.Getenv("HEALTH_HTTP_PORT"); v != "" {
+		healthHTTPPort = v
+	}
+
 	appConfig = &Config{
 		httpPort:        httpPort,
 		authGRPCAddr:    authGRPCAddr,
@@ -67,6 +146,15 @@ func Load(path ...string) error {
 		jwtSecret:       jwtSecret,
 		logger:          logger,
 		tracing:         tracing,
+		rateLimit:       rateLimit,
+		upstream:        upstream,
+		httpCacheSize:   httpCacheSize,
+		security:        security,
+		openAPI:         openAPI,
+		api:             api,
+		graphQL:         graphQL,
+		drainDelay:      drainDelay,
+		healthHTTPPort:  healthHTTPPort,
 	}
 
 	return nil
@@ -3,13 +3,37 @@ package config
 import "errors"
 
 var (
-	ErrHTTPPortNotProvided        = errors.New("GATEWAY_HTTP_PORT is not provided")
-	ErrAuthGRPCAddrNotProvided    = errors.New("AUTH_GRPC_ADDR is not provided")
-	ErrArticleGRPCAddrNotProvided = errors.New("ARTICLE_GRPC_ADDR is not provided")
-	ErrJWTSecretNotProvided       = errors.New("JWT_SECRET is not provided")
-	ErrLoggerLevelNotProvided     = errors.New("LOGGER_LEVEL is not provided")
-	ErrLoggerAsJsonNotProvided    = errors.New("LOGGER_AS_JSON is not provided")
-	ErrLoggerAsJsonInvalid        = errors.New("LOGGER_AS_JSON must be true or false")
-	ErrOtelEndpointNotProvided    = errors.New("OTEL_COLLECTOR_ENDPOINT is not provided")
-	ErrOtelServiceNameNotProvided = errors.New("OTEL_SERVICE_NAME is not provided")
+	ErrHTTPPortNotProvided                  = errors.New("GATEWAY_HTTP_PORT is not provided")
+	ErrAuthGRPCAddrNotProvided              = errors.New("AUTH_GRPC_ADDR is not provided")
+	ErrArticleGRPCAddrNotProvided           = errors.New("ARTICLE_GRPC_ADDR is not provided")
+	ErrJWTSecretNotProvided                 = errors.New("JWT_SECRET is not provided")
+	ErrLoggerLevelNotProvided               = errors.New("LOGGER_LEVEL is not provided")
+	ErrLoggerAsJsonNotProvided              = errors.New("LOGGER_AS_JSON is not provided")
+	ErrLoggerAsJsonInvalid                  = errors.New("LOGGER_AS_JSON must be true or false")
+	ErrOtelEndpointNotProvided              = errors.New("OTEL_COLLECTOR_ENDPOINT is not provided")
+	ErrOtelServiceNameNotProvided           = errors.New("OTEL_SERVICE_NAME is not provided")
+	ErrRateLimitEnabledInvalid              = errors.New("RATE_LIMIT_ENABLED must be true or false")
+	ErrInvalidUpstreamTimeout               = errors.New("UPSTREAM_TIMEOUT is not a valid duration")
+	ErrInvalidUpstreamMaxAttempts           = errors.New("UPSTREAM_MAX_ATTEMPTS must be a positive integer")
+	ErrInvalidBreakerThreshold              = errors.New("BREAKER_FAILURE_THRESHOLD must be a positive integer")
+	ErrInvalidBreakerOpenTimeout            = errors.New("BREAKER_OPEN_TIMEOUT is not a valid duration")
+	ErrInvalidHTTPCacheSize                 = errors.New("HTTP_CACHE_SIZE must be a non-negative integer")
+	ErrInvalidCORSMaxAge                    = errors.New("CORS_MAX_AGE is not a valid duration")
+	ErrInvalidHSTSMaxAge                    = errors.New("SECURITY_HSTS_MAX_AGE is not a valid duration")
+	ErrAuthCookieEnabledInvalid             = errors.New("AUTH_COOKIE_ENABLED must be true or false")
+	ErrAuthCookieSecureInvalid              = errors.New("AUTH_COOKIE_SECURE must be true or false")
+	ErrInvalidAuthCookieSameSite            = errors.New("AUTH_COOKIE_SAMESITE must be lax, strict or none")
+	ErrInvalidAuthCookieMaxAge              = errors.New("AUTH_COOKIE_MAX_AGE is not a valid duration")
+	ErrOpenAPIValidateRequestsInvalid       = errors.New("OPENAPI_VALIDATE_REQUESTS must be true or false")
+	ErrOpenAPIValidateResponsesInvalid      = errors.New("OPENAPI_VALIDATE_RESPONSES must be true or false")
+	ErrOpenAPIValidateResponsesInProduction = errors.New("OPENAPI_VALIDATE_RESPONSES is not allowed in production")
+	ErrAuthCookieSameSiteNoneInsecure       = errors.New("AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+	ErrCORSWildcardWithAuthCookie           = errors.New("CORS_ALLOWED_ORIGINS=* is not allowed with AUTH_COOKIE_ENABLED=true")
+	ErrInvalidTrustedProxies                = errors.New("TRUSTED_PROXIES must be a comma separated list of IPs or CIDRs")
+	ErrInvalidAPIDefaultVersion             = errors.New("API_DEFAULT_VERSION must be v1 or v2")
+	ErrInvalidAPIV1Sunset                   = errors.New("API_V1_SUNSET must be a date in YYYY-MM-DD format")
+	ErrGraphQLEnabledInvalid                = errors.New("GRAPHQL_ENABLED must be true or false")
+	ErrInvalidGraphQLMaxDepth               = errors.New("GRAPHQL_MAX_DEPTH must be a positive integer")
+	ErrInvalidGraphQLMaxComplexity          = errors.New("GRAPHQL_MAX_COMPLEXITY must be a positive integer")
+	ErrInvalidDrainDelay                    = errors.New("SHUTDOWN_DRAIN_DELAY must be a non-negative duration")
 )
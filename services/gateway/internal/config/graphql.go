@@ -0,0 +1,42 @@
+package config
+
+const (
+	defaultGraphQLMaxDepth      = 8
+	defaultGraphQLMaxComplexity = 500
+)
+
+type GraphQLConfig struct {
+	enabled       bool
+	maxDepth      int
+	maxComplexity int
+}
+
+// Enabled - /graphql монтируется только по флагу, REST API от него не зависит.
+func (c *GraphQLConfig) Enabled() bool { return c.enabled }
+func (c *GraphQLConfig) MaxDepth() int { return c.maxDepth }
+
+// MaxComplexity - сколько статей один запрос может запросить у сервисов, включая вложенные related.
+func (c *GraphQLConfig) MaxComplexity() int { return c.maxComplexity }
+
+func newGraphQLConfig() (*GraphQLConfig, error) {
+	enabled, err := parseBool("GRAPHQL_ENABLED", false, ErrGraphQLEnabledInvalid)
+	if err != nil {
+		return nil, err
+	}
+
+	maxDepth, err := parsePositiveInt("GRAPHQL_MAX_DEPTH", defaultGraphQLMaxDepth, ErrInvalidGraphQLMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	maxComplexity, err := parsePositiveInt("GRAPHQL_MAX_COMPLEXITY", defaultGraphQLMaxComplexity, ErrInvalidGraphQLMaxComplexity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLConfig{
+		enabled:       enabled,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+	}, nil
+}
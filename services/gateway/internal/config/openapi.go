@@ -0,0 +1,35 @@
+package config
+
+const productionEnvironment = "production"
+
+type OpenAPIConfig struct {
+	validateRequests  bool
+	validateResponses bool
+}
+
+func (c *OpenAPIConfig) ValidateRequests() bool { return c.validateRequests }
+
+// ValidateResponses - сверка ответов со спецификацией, ловит расхождение контракта до production.
+func (c *OpenAPIConfig) ValidateResponses() bool { return c.validateResponses }
+
+func newOpenAPIConfig(environment string) (*OpenAPIConfig, error) {
+	validateRequests, err := parseBool("OPENAPI_VALIDATE_REQUESTS", true, ErrOpenAPIValidateRequestsInvalid)
+	if err != nil {
+		return nil, err
+	}
+
+	validateResponses, err := parseBool("OPENAPI_VALIDATE_RESPONSES", false, ErrOpenAPIValidateResponsesInvalid)
+	if err != nil {
+		return nil, err
+	}
+
+	// проверка буферизует каждый ответ - в production это лишняя задержка и память
+	if validateResponses && environment == productionEnvironment {
+		return nil, ErrOpenAPIValidateResponsesInProduction
+	}
+
+	return &OpenAPIConfig{
+		validateRequests:  validateRequests,
+		validateResponses: validateResponses,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// parseDuration читает положительную длительность из env, при отсутствии переменной возвращает def.
+func parseDuration(key string, def time.Duration, errInvalid error) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	parsed, err := time.ParseDuration(v)
+	if err != nil || parsed <= 0 {
+		return 0, errInvalid
+	}
+
+	return parsed, nil
+}
+
+// parseNonNegativeInt читает неотрицательное число из env, при отсутствии переменной возвращает def.
+func parseNonNegativeInt(key string, def int, errInvalid error) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed < 0 {
+		return 0, errInvalid
+	}
+
+	return parsed, nil
+}
+
+// parsePositiveInt читает положительное число из env, при отсутствии переменной возвращает def.
+func parsePositiveInt(key string, def int, errInvalid error) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed <= 0 {
+		return 0, errInvalid
+	}
+
+	return parsed, nil
+}
+
+// parseBool читает true/false из env, при отсутствии переменной возвращает def.
+func parseBool(key string, def bool, errInvalid error) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	parsed, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errInvalid
+	}
+
+	return parsed, nil
+}
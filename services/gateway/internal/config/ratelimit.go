@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/ratelimit"
+)
+
+const defaultRateLimit = "300/1m"
+
+type RateLimitConfig struct {
+	enabled   bool
+	redisAddr string
+	def       ratelimit.Policy
+}
+
+func (c *RateLimitConfig) Enabled() bool { return c.enabled }
+
+// RedisAddr - пустой адрес значит лимиты только в памяти инстанса.
+func (c *RateLimitConfig) RedisAddr() string               { return c.redisAddr }
+func (c *RateLimitConfig) DefaultPolicy() ratelimit.Policy { return c.def }
+
+func newRateLimitConfig() (*RateLimitConfig, error) {
+	enabled := true
+	if v := os.Getenv("RATE_LIMIT_ENABLED"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, ErrRateLimitEnabledInvalid
+		}
+		enabled = b
+	}
+
+	raw := defaultRateLimit
+	if v := os.Getenv("RATE_LIMIT_DEFAULT"); v != "" {
+		raw = v
+	}
+
+	def, err := ratelimit.ParsePolicy("default", raw)
+	if err != nil {
+		return nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	return &RateLimitConfig{
+		enabled:   enabled,
+		redisAddr: os.Getenv("REDIS_ADDR"),
+		def:       def,
+	}, nil
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"net/http"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCORSMaxAge       = 10 * time.Minute
+	defaultAuthCookieMaxAge = 30 * 24 * time.Hour
+)
+
+type SecurityConfig struct {
+	corsAllowedOrigins []string
+	corsMaxAge         time.Duration
+	hstsMaxAge         time.Duration
+	cookie             *AuthCookieConfig
+	trustedProxies     []netip.Prefix
+}
+
+// CORSAllowedOrigins - пустой список значит CORS выключен.
+func (c *SecurityConfig) CORSAllowedOrigins() []string { return c.corsAllowedOrigins }
+func (c *SecurityConfig) CORSMaxAge() time.Duration    { return c.corsMaxAge }
+
+// HSTSMaxAge - 0 (переменная не задана) значит без Strict-Transport-Security.
+func (c *SecurityConfig) HSTSMaxAge() time.Duration { return c.hstsMaxAge }
+
+// TrustedProxies - от кого принимать X-Forwarded-For и X-Real-IP. Пустой список - адрес клиента всегда RemoteAddr.
+func (c *SecurityConfig) TrustedProxies() []netip.Prefix { return c.trustedProxies }
+
+// AuthCookie - nil, если режим cookie выключен.
+func (c *SecurityConfig) AuthCookie() *AuthCookieConfig { return c.cookie }
+
+type AuthCookieConfig struct {
+	domain   string
+	secure   bool
+	sameSite http.SameSite
+	maxAge   time.Duration
+}
+
+func (c *AuthCookieConfig) Domain() string          { return c.domain }
+func (c *AuthCookieConfig) Secure() bool            { return c.secure }
+func (c *AuthCookieConfig) SameSite() http.SameSite { return c.sameSite }
+func (c *AuthCookieConfig) MaxAge() time.Duration   { return c.maxAge }
+
+func newSecurityConfig() (*SecurityConfig, error) {
+	var origins []string
+	for o := range strings.SplitSeq(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			origins = append(origins, o)
+		}
+	}
+
+	corsMaxAge, err := parseDuration("CORS_MAX_AGE", defaultCORSMaxAge, ErrInvalidCORSMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	hstsMaxAge, err := parseDuration("SECURITY_HSTS_MAX_AGE", 0, ErrInvalidHSTSMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	cookie, err := newAuthCookieConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// с cookie браузер шлёт токен сам, и "*" отдал бы сессию пользователя любому сайту
+	if cookie != nil && slices.Contains(origins, "*") {
+		return nil, ErrCORSWildcardWithAuthCookie
+	}
+
+	trustedProxies, err := parseTrustedProxies()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecurityConfig{
+		corsAllowedOrigins: origins,
+		corsMaxAge:         corsMaxAge,
+		hstsMaxAge:         hstsMaxAge,
+		cookie:             cookie,
+		trustedProxies:     trustedProxies,
+	}, nil
+}
+
+// parseTrustedProxies - TRUSTED_PROXIES через запятую: подсети (10.0.0.0/8) или отдельные адреса.
+func parseTrustedProxies() ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for v := range strings.SplitSeq(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(v); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, ErrInvalidTrustedProxies
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+func newAuthCookieConfig() (*AuthCookieConfig, error) {
+	enabled, err := parseBool("AUTH_COOKIE_ENABLED", false, ErrAuthCookieEnabledInvalid)
+	if err != nil || !enabled {
+		return nil, err
+	}
+
+	secure, err := parseBool("AUTH_COOKIE_SECURE", true, ErrAuthCookieSecureInvalid)
+	if err != nil {
+		return nil, err
+	}
+
+	var sameSite http.SameSite
+	switch strings.ToLower(os.Getenv("AUTH_COOKIE_SAMESITE")) {
+	case "", "lax":
+		sameSite = http.SameSiteLaxMode
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		// браузеры принимают SameSite=None только вместе с Secure
+		if !secure {
+			return nil, ErrAuthCookieSameSiteNoneInsecure
+		}
+		sameSite = http.SameSiteNoneMode
+	default:
+		return nil, ErrInvalidAuthCookieSameSite
+	}
+
+	maxAge, err := parseDuration("AUTH_COOKIE_MAX_AGE", defaultAuthCookieMaxAge, ErrInvalidAuthCookieMaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthCookieConfig{
+		domain:   os.Getenv("AUTH_COOKIE_DOMAIN"),
+		secure:   secure,
+		sameSite: sameSite,
+		maxAge:   maxAge,
+	}, nil
+}
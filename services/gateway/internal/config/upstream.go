@@ -0,0 +1,51 @@
+package config
+
+import "time"
+
+const (
+	defaultUpstreamTimeout         = 5 * time.Second
+	defaultUpstreamMaxAttempts     = 3
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerOpenTimeout      = 10 * time.Second
+)
+
+type UpstreamConfig struct {
+	timeout                 time.Duration
+	maxAttempts             int
+	breakerFailureThreshold int
+	breakerOpenTimeout      time.Duration
+}
+
+func (c *UpstreamConfig) Timeout() time.Duration            { return c.timeout }
+func (c *UpstreamConfig) MaxAttempts() int                  { return c.maxAttempts }
+func (c *UpstreamConfig) BreakerFailureThreshold() int      { return c.breakerFailureThreshold }
+func (c *UpstreamConfig) BreakerOpenTimeout() time.Duration { return c.breakerOpenTimeout }
+
+func newUpstreamConfig() (*UpstreamConfig, error) {
+	timeout, err := parseDuration("UPSTREAM_TIMEOUT", defaultUpstreamTimeout, ErrInvalidUpstreamTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts, err := parsePositiveInt("UPSTREAM_MAX_ATTEMPTS", defaultUpstreamMaxAttempts, ErrInvalidUpstreamMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold, err := parsePositiveInt("BREAKER_FAILURE_THRESHOLD", defaultBreakerFailureThreshold, ErrInvalidBreakerThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	openTimeout, err := parseDuration("BREAKER_OPEN_TIMEOUT", defaultBreakerOpenTimeout, ErrInvalidBreakerOpenTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpstreamConfig{
+		timeout:                 timeout,
+		maxAttempts:             maxAttempts,
+		breakerFailureThreshold: threshold,
+		breakerOpenTimeout:      openTimeout,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	gographql "github.com/graph-gophers/graphql-go"
+)
+
+type stateKey struct{}
+
+// requestState - загрузчики и бюджет сложности одного запроса.
+type requestState struct {
+	loaders *loaders
+	// budget - сколько статей ещё можно запросить у upstream
+	budget atomic.Int64
+	limit  int64
+}
+
+func withState(ctx context.Context, s *requestState) context.Context {
+	return context.WithValue(ctx, stateKey{}, s)
+}
+
+func stateFrom(ctx context.Context) *requestState {
+	s, _ := ctx.Value(stateKey{}).(*requestState)
+	return s
+}
+
+// chargeList - стоимость корневого поля: каждая статья стоит 1 и списывается сразу, а related под ними списывает
+// каждый Related при разрешении - так учитываются и несколько related под разными алиасами. Заранее по первому
+// вхождению пути оценивается цепочка related (first статей на каждую статью уровня): корневые поля разрешаются
+// раньше вложенных, поэтому явно дорогой запрос отклоняется до вызовов сервисов.
+func chargeList(ctx context.Context, first int32, relatedPath string) error {
+	estimate, items := int64(0), int64(first)
+	for path := relatedPath; ; path += ".related" {
+		estimate += items
+		if !gographql.HasSelectedField(ctx, path) {
+			break
+		}
+
+		var args struct{ First *int32 }
+		if _, err := gographql.DecodeSelectedFieldArgs(ctx, path, &args); err != nil {
+			return err
+		}
+		items *= int64(pageSize(args.First, defaultRelatedSize, maxRelatedSize))
+	}
+
+	s := stateFrom(ctx)
+	if estimate > s.budget.Load() {
+		return complexityError(s.limit)
+	}
+
+	return charge(ctx, int64(first))
+}
+
+// charge списывает n статей с бюджета запроса до обращения к upstream.
+func charge(ctx context.Context, n int64) error {
+	s := stateFrom(ctx)
+	if s.budget.Add(-n) < 0 {
+		return complexityError(s.limit)
+	}
+
+	return nil
+}
+
+func complexityError(limit int64) error {
+	return &queryError{
+		message: fmt.Sprintf("query complexity exceeds the limit of %d", limit),
+		code:    codeComplexityLimit,
+	}
+}
@@ -0,0 +1,31 @@
+package graphql
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+const codeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+
+// queryError - ошибка поля со стабильным кодом в extensions.code, те же коды, что в problem+json REST.
+type queryError struct {
+	message string
+	code    string
+}
+
+func (e *queryError) Error() string { return e.message }
+
+func (e *queryError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func upstreamError(err error) error {
+	p := utils.GRPCProblem(err)
+	return &queryError{message: p.Detail, code: p.Code}
+}
+
+func isNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
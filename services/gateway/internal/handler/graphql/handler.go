@@ -0,0 +1,80 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+
+	gographql "github.com/graph-gophers/graphql-go"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/article"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+const maxRequestBody = 1 << 20
+
+//go:embed schema.graphql
+var schemaSDL string
+
+type Options struct {
+	// MaxDepth - максимальная вложенность полей запроса, проверяется при валидации.
+	MaxDepth int
+	// MaxComplexity - сколько статей один запрос может запросить у сервисов, с учётом вложенных related.
+	MaxComplexity int
+}
+
+// Handler - POST /graphql поверх тех же gRPC клиентов, что и REST. Пользователь берётся из контекста,
+// поэтому перед хендлером должен стоять middleware.Auth.
+type Handler struct {
+	schema        *gographql.Schema
+	client        articlev1.ArticleServiceClient
+	maxComplexity int64
+}
+
+func New(client articlev1.ArticleServiceClient, opts Options) (*Handler, error) {
+	schema, err := gographql.ParseSchema(schemaSDL, &queryResolver{client: client},
+		gographql.MaxDepth(opts.MaxDepth),
+		gographql.MaxQueryLength(maxRequestBody),
+		gographql.Tracer(tracer{}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse graphql schema: %w", err)
+	}
+
+	return &Handler{
+		schema:        schema,
+		client:        client,
+		maxComplexity: int64(opts.MaxComplexity),
+	}, nil
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBody)
+
+	var req request
+	if err := utils.DecodeBody(r, &req); err != nil || req.Query == "" {
+		utils.WriteError(w, r, http.StatusBadRequest, "invalid graphql request")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	state := &requestState{
+		loaders: newLoaders(ctx, h.client, article.ViewerID(r)),
+		limit:   h.maxComplexity,
+	}
+	state.budget.Store(h.maxComplexity)
+
+	// по спецификации GraphQL over HTTP ошибки запроса - тоже 200, они в поле errors
+	resp := h.schema.Exec(withState(ctx, state), req.Query, req.OperationName, req.Variables)
+	utils.WriteJSON(w, http.StatusOK, resp)
+}
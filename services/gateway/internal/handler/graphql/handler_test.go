@@ -0,0 +1,250 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+)
+
+type mockArticleClient struct {
+	articlev1.ArticleServiceClient
+
+	relatedCalls atomic.Int32
+	getCalls     atomic.Int32
+}
+
+func testArticle(id string) *articlev1.Article {
+	return &articlev1.Article{
+		Id:        id,
+		AuthorId:  "019c0000-0000-7000-8000-000000000001",
+		Title:     "title " + id,
+		ViewCount: 7,
+		CreatedAt: timestamppb.Now(),
+		UpdatedAt: timestamppb.Now(),
+	}
+}
+
+func (m *mockArticleClient) ListArticles(_ context.Context, in *articlev1.ListArticlesRequest, _ ...grpc.CallOption) (*articlev1.ListArticlesResponse, error) {
+	articles := make([]*articlev1.Article, 0, in.GetLimit())
+	for i := range in.GetLimit() {
+		articles = append(articles, testArticle(string(rune('a'+i))))
+	}
+
+	return &articlev1.ListArticlesResponse{Articles: articles, NextCursor: "next"}, nil
+}
+
+func (m *mockArticleClient) GetRelatedArticles(_ context.Context, in *articlev1.GetRelatedArticlesRequest, _ ...grpc.CallOption) (*articlev1.GetRelatedArticlesResponse, error) {
+	m.relatedCalls.Add(1)
+	return &articlev1.GetRelatedArticlesResponse{Articles: []*articlev1.Article{testArticle("related-" + in.GetId())}}, nil
+}
+
+func (m *mockArticleClient) GetArticle(_ context.Context, in *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+	m.getCalls.Add(1)
+	if in.GetId() == "missing" {
+		return nil, status.Error(codes.NotFound, "article not found")
+	}
+
+	return &articlev1.GetArticleResponse{Article: testArticle(in.GetId())}, nil
+}
+
+type response struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execQuery(t *testing.T, h *Handler, query string) response {
+	t.Helper()
+
+	body, _ := json.Marshal(map[string]any{"query": query})
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var resp response
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	return resp
+}
+
+func newTestHandler(t *testing.T, client *mockArticleClient, opts Options) *Handler {
+	t.Helper()
+
+	h, err := New(client, opts)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	return h
+}
+
+func TestFeed_RelatedDeduplicated(t *testing.T) {
+	client := &mockArticleClient{}
+	h := newTestHandler(t, client, Options{MaxDepth: 10, MaxComplexity: 1000})
+
+	// один и тот же related запрошен двумя алиасами - в upstream по разу на статью
+	resp := execQuery(t, h, `{
+		feed(first: 3) {
+			items { id views author { id } a: related(first: 2) { id } b: related(first: 2) { title } }
+			nextCursor
+			hasMore
+		}
+	}`)
+
+	if len(resp.Errors) != 0 {
+		t.Fatalf("errors = %+v", resp.Errors)
+	}
+	if got := client.relatedCalls.Load(); got != 3 {
+		t.Errorf("related calls = %d, want 3", got)
+	}
+
+	var feed struct {
+		Items []struct {
+			ID    string `json:"id"`
+			Views int    `json:"views"`
+			A     []struct {
+				ID string `json:"id"`
+			} `json:"a"`
+		} `json:"items"`
+		HasMore bool `json:"hasMore"`
+	}
+	if err := json.Unmarshal(resp.Data["feed"], &feed); err != nil {
+		t.Fatalf("decode feed: %v", err)
+	}
+	if len(feed.Items) != 3 || !feed.HasMore {
+		t.Fatalf("feed = %+v", feed)
+	}
+	if feed.Items[0].A[0].ID != "related-"+feed.Items[0].ID {
+		t.Errorf("related = %+v", feed.Items[0].A)
+	}
+}
+
+func TestArticle_NotFoundIsNull(t *testing.T) {
+	client := &mockArticleClient{}
+	h := newTestHandler(t, client, Options{MaxDepth: 10, MaxComplexity: 1000})
+
+	resp := execQuery(t, h, `{ a: article(id: "missing") { id } b: article(id: "x") { id } c: article(id: "x") { title } }`)
+
+	if len(resp.Errors) != 0 {
+		t.Fatalf("errors = %+v", resp.Errors)
+	}
+	if string(resp.Data["a"]) != "null" {
+		t.Errorf("a = %s, want null", resp.Data["a"])
+	}
+	if got := client.getCalls.Load(); got != 2 {
+		t.Errorf("get calls = %d, want 2", got)
+	}
+}
+
+func TestComplexityLimit(t *testing.T) {
+	client := &mockArticleClient{}
+	h := newTestHandler(t, client, Options{MaxDepth: 10, MaxComplexity: 100})
+
+	// 50 статей + 50*10 related
+	resp := execQuery(t, h, `{ feed(first: 50) { items { related(first: 10) { id } } } }`)
+
+	if len(resp.Errors) != 1 {
+		t.Fatalf("errors = %+v, want one", resp.Errors)
+	}
+	if code := resp.Errors[0].Extensions["code"]; code != codeComplexityLimit {
+		t.Errorf("code = %v, want %s", code, codeComplexityLimit)
+	}
+	if got := client.relatedCalls.Load(); got != 0 {
+		t.Errorf("related calls = %d, want 0", got)
+	}
+}
+
+func TestComplexityLimit_AliasedRelated(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxComplexity int
+		wantLimit     bool
+	}{
+		// 10 статей + 3 алиаса по 10*5 related = 160
+		{name: "over limit", maxComplexity: 100, wantLimit: true},
+		{name: "within limit", maxComplexity: 200},
+	}
+
+	query := `{ feed(first: 10) { items {
+		a: related(first: 5) { id }
+		b: related(first: 5) { id }
+		c: related(first: 5) { id }
+	} } }`
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, &mockArticleClient{}, Options{MaxDepth: 10, MaxComplexity: tt.maxComplexity})
+			resp := execQuery(t, h, query)
+
+			limited := false
+			for _, e := range resp.Errors {
+				if e.Extensions["code"] == codeComplexityLimit {
+					limited = true
+				}
+			}
+			if limited != tt.wantLimit {
+				t.Errorf("complexity limit error = %v, want %v (errors %+v)", limited, tt.wantLimit, resp.Errors)
+			}
+			if !tt.wantLimit && len(resp.Errors) != 0 {
+				t.Errorf("errors = %+v, want none", resp.Errors)
+			}
+		})
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	client := &mockArticleClient{}
+	h := newTestHandler(t, client, Options{MaxDepth: 3, MaxComplexity: 1000})
+
+	resp := execQuery(t, h, `{ mostRead { related { related { related { id } } } } }`)
+
+	if len(resp.Errors) == 0 {
+		t.Fatal("expected depth error")
+	}
+	if resp.Data != nil {
+		t.Errorf("data = %v, want none", resp.Data)
+	}
+}
+
+func TestViewer_Anonymous(t *testing.T) {
+	h := newTestHandler(t, &mockArticleClient{}, Options{MaxDepth: 10, MaxComplexity: 1000})
+
+	resp := execQuery(t, h, `{ viewer { id } }`)
+
+	if string(resp.Data["viewer"]) != "null" {
+		t.Errorf("viewer = %s, want null", resp.Data["viewer"])
+	}
+}
+
+func TestInvalidBody(t *testing.T) {
+	h := newTestHandler(t, &mockArticleClient{}, Options{MaxDepth: 10, MaxComplexity: 1000})
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(`{"query":`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
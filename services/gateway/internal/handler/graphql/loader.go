@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type result[V any] struct {
+	value V
+	err   error
+}
+
+// batchFunc получает уникальные ключи батча и возвращает результаты в том же порядке.
+type batchFunc[K comparable, V any] func(ctx context.Context, keys []K) []result[V]
+
+type entry[V any] struct {
+	result[V]
+	done chan struct{}
+}
+
+// loader собирает ключи, запрошенные резолверами в пределах wait, и отдаёт их batchFunc одним вызовом.
+// Живёт один запрос: результаты кэшируются, повторный ключ не уходит в upstream.
+type loader[K comparable, V any] struct {
+	ctx      context.Context //nolint:containedctx // батч выполняется от имени запроса, а не резолвера, который его начал
+	fetch    batchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*entry[V]
+	pending []K
+}
+
+func newLoader[K comparable, V any](ctx context.Context, fetch batchFunc[K, V], wait time.Duration, maxBatch int) *loader[K, V] {
+	return &loader[K, V]{
+		ctx:      ctx,
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*entry[V]),
+	}
+}
+
+func (l *loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	e, ok := l.cache[key]
+	if !ok {
+		e = &entry[V]{done: make(chan struct{})}
+		l.cache[key] = e
+		l.pending = append(l.pending, key)
+
+		switch len(l.pending) {
+		case l.maxBatch:
+			go l.dispatch()
+		case 1:
+			time.AfterFunc(l.wait, l.dispatch)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.value, e.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatch забирает накопленные ключи. Таймер после отправки полного батча может сработать на пустом списке.
+func (l *loader[K, V]) dispatch() {
+	l.mu.Lock()
+	keys := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	if len(keys) == 0 {
+		return
+	}
+
+	results := l.fetch(l.ctx, keys)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, key := range keys {
+		e := l.cache[key]
+		e.result = results[i]
+		close(e.done)
+	}
+}
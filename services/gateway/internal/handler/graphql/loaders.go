@@ -0,0 +1,69 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/tracing"
+)
+
+const (
+	// loaderWait - окно, за которое резолверы соседних полей успевают добавить свои ключи
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+	// upstreamParallelism - у article нет батчевых RPC, батч раскладывается на параллельные вызовы
+	upstreamParallelism = 8
+)
+
+type relatedKey struct {
+	id    string
+	limit int32
+}
+
+// loaders - свои на каждый запрос, чтобы кэш не смешивал пользователей.
+type loaders struct {
+	articles *loader[string, *articlev1.Article]
+	related  *loader[relatedKey, []*articlev1.Article]
+}
+
+func newLoaders(ctx context.Context, client articlev1.ArticleServiceClient, viewerID string) *loaders {
+	return &loaders{
+		articles: newLoader(ctx, func(ctx context.Context, ids []string) []result[*articlev1.Article] {
+			return fanOut(ctx, "graphql.loader.articles", ids, func(ctx context.Context, id string) (*articlev1.Article, error) {
+				resp, err := client.GetArticle(ctx, &articlev1.GetArticleRequest{Id: id, ViewerId: viewerID})
+				return resp.GetArticle(), err
+			})
+		}, loaderWait, loaderMaxBatch),
+		related: newLoader(ctx, func(ctx context.Context, keys []relatedKey) []result[[]*articlev1.Article] {
+			return fanOut(ctx, "graphql.loader.related", keys, func(ctx context.Context, k relatedKey) ([]*articlev1.Article, error) {
+				resp, err := client.GetRelatedArticles(ctx, &articlev1.GetRelatedArticlesRequest{Id: k.id, Limit: k.limit})
+				return resp.GetArticles(), err
+			})
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+// fanOut выполняет батч параллельными вызовами под одним спаном. Ошибка ключа не роняет остальные.
+func fanOut[K comparable, V any](ctx context.Context, name string, keys []K, call func(context.Context, K) (V, error)) []result[V] {
+	ctx, span := tracing.StartSpan(ctx, name)
+	defer span.End()
+	span.SetAttributes(attribute.Int("graphql.batch.size", len(keys)))
+
+	results := make([]result[V], len(keys))
+	var g errgroup.Group
+	g.SetLimit(upstreamParallelism)
+	for i, key := range keys {
+		g.Go(func() error {
+			v, err := call(ctx, key)
+			results[i] = result[V]{value: v, err: err}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return results
+}
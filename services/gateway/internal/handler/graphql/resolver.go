@@ -0,0 +1,169 @@
+package graphql
+
+import (
+	"context"
+	"math"
+
+	gographql "github.com/graph-gophers/graphql-go"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+const (
+	defaultFeedSize     = 20
+	maxFeedSize         = 100
+	defaultMostReadSize = 10
+	defaultRelatedSize  = 5
+	maxRelatedSize      = 50
+)
+
+type queryResolver struct {
+	client articlev1.ArticleServiceClient
+}
+
+type feedArgs struct {
+	First *int32
+	After *string
+}
+
+func (q *queryResolver) Feed(ctx context.Context, args feedArgs) (*articlePageResolver, error) {
+	first := pageSize(args.First, defaultFeedSize, maxFeedSize)
+	if err := chargeList(ctx, first, "items.related"); err != nil {
+		return nil, err
+	}
+
+	req := &articlev1.ListArticlesRequest{Limit: first}
+	if args.After != nil {
+		req.Cursor = *args.After
+	}
+
+	resp, err := q.client.ListArticles(ctx, req)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	return &articlePageResolver{articles: resp.GetArticles(), nextCursor: resp.GetNextCursor()}, nil
+}
+
+func (q *queryResolver) Article(ctx context.Context, args struct{ ID gographql.ID }) (*articleResolver, error) {
+	if err := chargeList(ctx, 1, "related"); err != nil {
+		return nil, err
+	}
+
+	a, err := stateFrom(ctx).loaders.articles.Load(ctx, string(args.ID))
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil //nolint:nilnil // отсутствующая статья в GraphQL - null, а не ошибка
+		}
+		return nil, upstreamError(err)
+	}
+
+	return &articleResolver{a: a}, nil
+}
+
+func (q *queryResolver) MostRead(ctx context.Context, args struct{ First *int32 }) ([]*articleResolver, error) {
+	first := pageSize(args.First, defaultMostReadSize, maxFeedSize)
+	if err := chargeList(ctx, first, "related"); err != nil {
+		return nil, err
+	}
+
+	resp, err := q.client.ListMostRead(ctx, &articlev1.ListMostReadRequest{Limit: first})
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	return articleResolvers(resp.GetArticles()), nil
+}
+
+func (q *queryResolver) Viewer(ctx context.Context) *viewerResolver {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return &viewerResolver{id: gographql.ID(userID.String())}
+}
+
+type viewerResolver struct {
+	id gographql.ID
+}
+
+func (v *viewerResolver) ID() gographql.ID { return v.id }
+
+type articlePageResolver struct {
+	articles   []*articlev1.Article
+	nextCursor string
+}
+
+func (p *articlePageResolver) Items() []*articleResolver { return articleResolvers(p.articles) }
+func (p *articlePageResolver) HasMore() bool             { return p.nextCursor != "" }
+
+func (p *articlePageResolver) NextCursor() *string {
+	if p.nextCursor == "" {
+		return nil
+	}
+
+	return &p.nextCursor
+}
+
+type articleResolver struct {
+	a *articlev1.Article
+}
+
+func articleResolvers(articles []*articlev1.Article) []*articleResolver {
+	out := make([]*articleResolver, 0, len(articles))
+	for _, a := range articles {
+		out = append(out, &articleResolver{a: a})
+	}
+
+	return out
+}
+
+func (r *articleResolver) ID() gographql.ID        { return gographql.ID(r.a.GetId()) }
+func (r *articleResolver) Title() string           { return r.a.GetTitle() }
+func (r *articleResolver) Content() string         { return r.a.GetContent() }
+func (r *articleResolver) Author() *authorResolver { return &authorResolver{id: r.a.GetAuthorId()} }
+
+// Views - Int в GraphQL 32-битный, счётчик просмотров до него не дорастёт, но переполнение не отдаём.
+func (r *articleResolver) Views() int32 {
+	return int32(min(r.a.GetViewCount(), math.MaxInt32)) //nolint:gosec // ограничено выше
+}
+
+func (r *articleResolver) CreatedAt() gographql.Time {
+	return gographql.Time{Time: r.a.GetCreatedAt().AsTime()}
+}
+
+func (r *articleResolver) UpdatedAt() gographql.Time {
+	return gographql.Time{Time: r.a.GetUpdatedAt().AsTime()}
+}
+
+func (r *articleResolver) Related(ctx context.Context, args struct{ First *int32 }) ([]*articleResolver, error) {
+	key := relatedKey{id: r.a.GetId(), limit: pageSize(args.First, defaultRelatedSize, maxRelatedSize)}
+	if err := charge(ctx, int64(key.limit)); err != nil {
+		return nil, err
+	}
+
+	articles, err := stateFrom(ctx).loaders.related.Load(ctx, key)
+	if err != nil {
+		return nil, upstreamError(err)
+	}
+
+	return articleResolvers(articles), nil
+}
+
+// authorResolver - профилей пользователей в сервисах пока нет, автор отдаётся только идентификатором.
+type authorResolver struct {
+	id string
+}
+
+func (a *authorResolver) ID() gographql.ID { return gographql.ID(a.id) }
+
+// pageSize - first вне [1, max] заменяется на def, как limit в REST.
+func pageSize(first *int32, def, maxSize int32) int32 {
+	if first == nil || *first <= 0 || *first > maxSize {
+		return def
+	}
+
+	return *first
+}
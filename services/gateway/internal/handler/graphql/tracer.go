@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/introspection"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/SonOfSteveJobs/habr/pkg/tracing"
+)
+
+// tracer - спан на запрос, на валидацию и на каждый нетривиальный резолвер. Поля, которые просто читают
+// структуру, спанов не создают, иначе трасса ленты состояла бы из тысяч пустых спанов.
+type tracer struct{}
+
+func (tracer) TraceQuery(ctx context.Context, _ string, operationName string, _ map[string]any, _ map[string]*introspection.Type) (context.Context, func([]*errors.QueryError)) {
+	ctx, span := tracing.StartSpan(ctx, "graphql.query")
+	if operationName != "" {
+		span.SetAttributes(attribute.String("graphql.operation.name", operationName))
+	}
+
+	return ctx, func(errs []*errors.QueryError) {
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, errs[0].Error())
+		}
+		span.End()
+	}
+}
+
+func (tracer) TraceField(ctx context.Context, label, typeName, fieldName string, trivial bool, _ map[string]any) (context.Context, func(*errors.QueryError)) {
+	if trivial {
+		return ctx, func(*errors.QueryError) {}
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "graphql.resolve "+typeName+"."+fieldName)
+	span.SetAttributes(
+		attribute.String("graphql.field.path", label),
+		attribute.String("graphql.field.type", typeName),
+		attribute.String("graphql.field.name", fieldName),
+	)
+
+	return ctx, func(err *errors.QueryError) {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func (tracer) TraceValidation(ctx context.Context) func([]*errors.QueryError) {
+	_, span := tracing.StartSpan(ctx, "graphql.validate")
+
+	return func(errs []*errors.QueryError) {
+		if len(errs) > 0 {
+			span.SetStatus(codes.Error, errs[0].Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,261 @@
+package article
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	tarContentType    = "application/x-tar"
+
+	frontMatterDelimiter = "---"
+	// maxArchiveLine - предел одной строки NDJSON, чтобы не буферизовать бесконечную строку.
+	maxArchiveLine = 4 << 20
+)
+
+var errInvalidArchive = errors.New("invalid archive")
+
+// archiveWriter пишет статьи экспорта по одной.
+type archiveWriter interface {
+	Write(a gatewayv1.ArticleResponse) error
+	Close() error
+}
+
+// archiveReader читает статьи импорта по одной, io.EOF - конец архива.
+type archiveReader interface {
+	Next() (importItem, error)
+}
+
+type importItem struct {
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *ndjsonWriter) Write(a gatewayv1.ArticleResponse) error {
+	return w.enc.Encode(a)
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONReader(r io.Reader) *ndjsonReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxArchiveLine)
+
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) Next() (importItem, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var item importItem
+		if err := json.Unmarshal(line, &item); err != nil {
+			return importItem{}, fmt.Errorf("%w: line %d: %v", errInvalidArchive, r.line, err)
+		}
+
+		return item, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return importItem{}, fmt.Errorf("read ndjson: %w", err)
+	}
+
+	return importItem{}, io.EOF
+}
+
+// markdownWriter пишет tar-архив с файлом <id>.md на статью.
+type markdownWriter struct {
+	tw *tar.Writer
+}
+
+func newMarkdownWriter(w io.Writer) *markdownWriter {
+	return &markdownWriter{tw: tar.NewWriter(w)}
+}
+
+func (w *markdownWriter) Write(a gatewayv1.ArticleResponse) error {
+	data := formatMarkdown(a)
+
+	modTime := time.Now()
+	if a.UpdatedAt != nil {
+		modTime = *a.UpdatedAt
+	}
+
+	header := &tar.Header{
+		Name:    a.Id.String() + ".md",
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+
+	if err := w.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("write tar header: %w", err)
+	}
+
+	if _, err := w.tw.Write(data); err != nil {
+		return fmt.Errorf("write tar entry: %w", err)
+	}
+
+	return nil
+}
+
+func (w *markdownWriter) Close() error {
+	return w.tw.Close()
+}
+
+type markdownReader struct {
+	tr *tar.Reader
+}
+
+func newMarkdownReader(r io.Reader) *markdownReader {
+	return &markdownReader{tr: tar.NewReader(r)}
+}
+
+func (r *markdownReader) Next() (importItem, error) {
+	for {
+		header, err := r.tr.Next()
+		if errors.Is(err, io.EOF) {
+			return importItem{}, io.EOF
+		}
+		if err != nil {
+			return importItem{}, fmt.Errorf("%w: %w", errInvalidArchive, err)
+		}
+
+		if header.Typeflag != tar.TypeReg || path.Ext(header.Name) != ".md" {
+			continue
+		}
+
+		if header.Size > maxArchiveLine {
+			return importItem{}, fmt.Errorf("%w: %s: file too large", errInvalidArchive, header.Name)
+		}
+
+		data, err := io.ReadAll(r.tr)
+		if err != nil {
+			return importItem{}, fmt.Errorf("%w: %s: %w", errInvalidArchive, header.Name, err)
+		}
+
+		item, err := parseMarkdown(data)
+		if err != nil {
+			return importItem{}, fmt.Errorf("%w: %s: %w", errInvalidArchive, header.Name, err)
+		}
+
+		return item, nil
+	}
+}
+
+// formatMarkdown - статья с YAML front-matter. Заголовок в кавычках, чтобы двоеточия и переводы строк не ломали разметку.
+func formatMarkdown(a gatewayv1.ArticleResponse) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(frontMatterDelimiter + "\n")
+	if a.Id != nil {
+		fmt.Fprintf(&b, "id: %s\n", a.Id)
+	}
+	if a.AuthorId != nil {
+		fmt.Fprintf(&b, "author_id: %s\n", a.AuthorId)
+	}
+	if a.Title != nil {
+		fmt.Fprintf(&b, "title: %s\n", strconv.Quote(*a.Title))
+	}
+	if a.CreatedAt != nil {
+		fmt.Fprintf(&b, "created_at: %s\n", a.CreatedAt.Format(time.RFC3339Nano))
+	}
+	if a.UpdatedAt != nil {
+		fmt.Fprintf(&b, "updated_at: %s\n", a.UpdatedAt.Format(time.RFC3339Nano))
+	}
+	b.WriteString(frontMatterDelimiter + "\n")
+
+	if a.Content != nil {
+		b.WriteString(*a.Content)
+	}
+
+	return b.Bytes()
+}
+
+// parseMarkdown разбирает front-matter, записанный formatMarkdown. Неизвестные ключи игнорируются.
+func parseMarkdown(data []byte) (importItem, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+
+	rest, ok := strings.CutPrefix(text, frontMatterDelimiter+"\n")
+	if !ok {
+		return importItem{}, errors.New("missing front-matter")
+	}
+
+	frontMatter, content, ok := strings.Cut(rest, "\n"+frontMatterDelimiter+"\n")
+	if !ok {
+		frontMatter, ok = strings.CutSuffix(rest, "\n"+frontMatterDelimiter)
+		if !ok {
+			return importItem{}, errors.New("unterminated front-matter")
+		}
+	}
+
+	item := importItem{Content: content}
+
+	for line := range strings.SplitSeq(frontMatter, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "title":
+			title, err := unquote(value)
+			if err != nil {
+				return importItem{}, fmt.Errorf("title: %w", err)
+			}
+			item.Title = title
+		case "created_at":
+			createdAt, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return importItem{}, fmt.Errorf("created_at: %w", err)
+			}
+			item.CreatedAt = &createdAt
+		}
+	}
+
+	return item, nil
+}
+
+func unquote(value string) (string, error) {
+	if len(value) >= 2 && value[0] == '"' {
+		return strconv.Unquote(value)
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,120 @@
+package article
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+)
+
+func testArticleResponse(title, content string) gatewayv1.ArticleResponse {
+	id := uuid.Must(uuid.NewV7())
+	authorID := uuid.Must(uuid.NewV7())
+	createdAt := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	return gatewayv1.ArticleResponse{
+		Id:        &id,
+		AuthorId:  &authorID,
+		Title:     &title,
+		Content:   &content,
+		CreatedAt: &createdAt,
+		UpdatedAt: &createdAt,
+	}
+}
+
+func TestMarkdownArchive_RoundTrip(t *testing.T) {
+	articles := []gatewayv1.ArticleResponse{
+		testArticleResponse(`Заголовок: с "кавычками"`, "# Текст\n\n---\n\nпосле разделителя"),
+		testArticleResponse("Second", ""),
+	}
+
+	var buf bytes.Buffer
+	w := newMarkdownWriter(&buf)
+	for _, a := range articles {
+		if err := w.Write(a); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	r := newMarkdownReader(&buf)
+	for i, want := range articles {
+		item, err := r.Next()
+		if err != nil {
+			t.Fatalf("next %d: %v", i, err)
+		}
+
+		if item.Title != *want.Title {
+			t.Errorf("item[%d].Title = %q, want %q", i, item.Title, *want.Title)
+		}
+		if item.Content != *want.Content {
+			t.Errorf("item[%d].Content = %q, want %q", i, item.Content, *want.Content)
+		}
+		if item.CreatedAt == nil || !item.CreatedAt.Equal(*want.CreatedAt) {
+			t.Errorf("item[%d].CreatedAt = %v, want %v", i, item.CreatedAt, want.CreatedAt)
+		}
+	}
+
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("next after last = %v, want io.EOF", err)
+	}
+}
+
+func TestNDJSONArchive_RoundTrip(t *testing.T) {
+	article := testArticleResponse("Title", "line one\nline two")
+
+	var buf bytes.Buffer
+	w := newNDJSONWriter(&buf)
+	if err := w.Write(article); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("ndjson = %q, want exactly one line", buf.String())
+	}
+
+	r := newNDJSONReader(&buf)
+	item, err := r.Next()
+	if err != nil {
+		t.Fatalf("next: %v", err)
+	}
+
+	if item.Title != "Title" || item.Content != "line one\nline two" {
+		t.Errorf("item = %+v, want original title and content", item)
+	}
+
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("next after last = %v, want io.EOF", err)
+	}
+}
+
+func TestNDJSONReader_SkipsBlankLinesAndReportsBadLine(t *testing.T) {
+	r := newNDJSONReader(strings.NewReader("\n{\"title\":\"a\",\"content\":\"b\"}\n\nnot json\n"))
+
+	if _, err := r.Next(); err != nil {
+		t.Fatalf("first item: %v", err)
+	}
+
+	_, err := r.Next()
+	if !errors.Is(err, errInvalidArchive) {
+		t.Fatalf("error = %v, want errInvalidArchive", err)
+	}
+
+	if !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("error = %q, want line number 4", err.Error())
+	}
+}
+
+func TestParseMarkdown_MissingFrontMatter(t *testing.T) {
+	if _, err := parseMarkdown([]byte("just text")); err == nil {
+		t.Error("parseMarkdown() error = nil, want missing front-matter")
+	}
+}
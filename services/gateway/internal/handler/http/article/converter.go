@@ -25,10 +25,11 @@ func toArticleResponse(a *articlev1.Article) (gatewayv1.ArticleResponse, error)
 	}
 
 	resp := gatewayv1.ArticleResponse{
-		Id:       &id,
-		AuthorId: &authorID,
-		Title:    new(a.GetTitle()),
-		Content:  new(a.GetContent()),
+		Id:        &id,
+		AuthorId:  &authorID,
+		Title:     new(a.GetTitle()),
+		Content:   new(a.GetContent()),
+		ViewCount: new(a.GetViewCount()),
 	}
 
 	if a.GetCreatedAt() != nil {
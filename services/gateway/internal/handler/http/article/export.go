@@ -0,0 +1,98 @@
+package article
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func (h *Handler) ExportArticles(w http.ResponseWriter, r *http.Request, params gatewayv1.ExportArticlesParams) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := gatewayv1.ExportArticlesParamsFormatNdjson
+	if params.Format != nil {
+		format = *params.Format
+	}
+
+	var (
+		archive     archiveWriter
+		contentType string
+		filename    string
+	)
+
+	switch format {
+	case gatewayv1.ExportArticlesParamsFormatNdjson:
+		archive, contentType, filename = newNDJSONWriter(w), ndjsonContentType, "articles.ndjson"
+	case gatewayv1.ExportArticlesParamsFormatMarkdown:
+		archive, contentType, filename = newMarkdownWriter(w), tarContentType, "articles.tar"
+	default:
+		utils.WriteError(w, r, http.StatusBadRequest, "unknown format")
+		return
+	}
+
+	// пустой author_id - все статьи, это резервная выгрузка и доступна только администратору
+	req := &articlev1.ExportArticlesRequest{AuthorId: userID.String()}
+	if params.Scope != nil && *params.Scope == gatewayv1.ExportArticlesParamsScopeAll {
+		if !middleware.HasRole(r.Context(), middleware.RoleAdmin) {
+			utils.WriteError(w, r, http.StatusForbidden, "forbidden")
+			return
+		}
+		req.AuthorId = ""
+	}
+
+	stream, err := h.client.ExportArticles(r.Context(), req)
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	// Первое сообщение читается до заголовков: ошибка upstream ещё может стать нормальным HTTP-статусом.
+	msg, err := stream.Recv()
+	if err != nil && !errors.Is(err, io.EOF) {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.WriteHeader(http.StatusOK)
+
+	log := logger.Ctx(r.Context())
+	rc := http.NewResponseController(w)
+
+	for err == nil {
+		article, convErr := toArticleResponse(msg.GetArticle())
+		if convErr != nil {
+			log.Error().Err(convErr).Msg("export articles: convert article")
+			return
+		}
+
+		if err := archive.Write(article); err != nil {
+			log.Warn().Err(err).Msg("export articles: write")
+			return
+		}
+		_ = rc.Flush() //nolint:gosec
+
+		msg, err = stream.Recv()
+	}
+
+	// Заголовки уже отправлены: при обрыве потока архив остаётся без завершения, и клиент видит, что он неполный.
+	if !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Msg("export articles: stream interrupted")
+		return
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Warn().Err(err).Msg("export articles: close archive")
+	}
+}
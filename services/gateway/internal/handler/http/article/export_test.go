@@ -0,0 +1,162 @@
+package article
+
+import (
+	"archive/tar"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func testProtoArticle(authorID uuid.UUID, title string) *articlev1.Article {
+	return &articlev1.Article{
+		Id:       uuid.Must(uuid.NewV7()).String(),
+		AuthorId: authorID.String(),
+		Title:    title,
+		Content:  "content",
+	}
+}
+
+func TestExportArticles_NDJSON(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		exportFn: func(_ context.Context, in *articlev1.ExportArticlesRequest, _ ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error) {
+			if in.GetAuthorId() != userID.String() {
+				t.Errorf("author_id = %q, want %q", in.GetAuthorId(), userID.String())
+			}
+			return &mockExportStream{articles: []*articlev1.Article{
+				testProtoArticle(userID, "First"),
+				testProtoArticle(userID, "Second"),
+			}}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/export", "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), userID))
+
+	h.ExportArticles(w, r, gatewayv1.ExportArticlesParams{})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ndjsonContentType)
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"First"`) || !strings.Contains(lines[1], `"Second"`) {
+		t.Errorf("body = %q, want two lines in order", w.Body.String())
+	}
+}
+
+func TestExportArticles_Markdown(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	article := testProtoArticle(userID, "Only")
+
+	client := &mockArticleClient{
+		exportFn: func(_ context.Context, _ *articlev1.ExportArticlesRequest, _ ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error) {
+			return &mockExportStream{articles: []*articlev1.Article{article}}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/export?format=markdown", "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), userID))
+
+	h.ExportArticles(w, r, gatewayv1.ExportArticlesParams{Format: new(gatewayv1.ExportArticlesParamsFormatMarkdown)})
+
+	if ct := w.Header().Get("Content-Type"); ct != tarContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, tarContentType)
+	}
+
+	header, err := tar.NewReader(w.Body).Next()
+	if err != nil {
+		t.Fatalf("read tar: %v", err)
+	}
+
+	if header.Name != article.GetId()+".md" {
+		t.Errorf("entry name = %q, want %q", header.Name, article.GetId()+".md")
+	}
+}
+
+func TestExportArticles_Unauthorized(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/export", "")
+
+	h.ExportArticles(w, r, gatewayv1.ExportArticlesParams{})
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestExportArticles_UpstreamErrorBeforeFirstArticle(t *testing.T) {
+	client := &mockArticleClient{
+		exportFn: func(_ context.Context, _ *articlev1.ExportArticlesRequest, _ ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error) {
+			return &mockExportStream{err: status.Error(codes.Internal, "internal error")}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/export", "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ExportArticles(w, r, gatewayv1.ExportArticlesParams{})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestExportArticles_ScopeAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		roles      []string
+		wantStatus int
+	}{
+		{name: "admin", roles: []string{middleware.RoleAdmin}, wantStatus: http.StatusOK},
+		{name: "moderator", roles: []string{middleware.RoleModerator}, wantStatus: http.StatusForbidden},
+		{name: "user", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			client := &mockArticleClient{
+				exportFn: func(_ context.Context, in *articlev1.ExportArticlesRequest, _ ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error) {
+					called = true
+					if in.GetAuthorId() != "" {
+						t.Errorf("author_id = %q, want empty", in.GetAuthorId())
+					}
+					return &mockExportStream{}, nil
+				},
+			}
+			h := newTestHandler(client)
+
+			w, r := makeRequest(http.MethodGet, "/api/v1/articles/export?scope=all", "")
+			r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), tt.roles...))
+
+			h.ExportArticles(w, r, gatewayv1.ExportArticlesParams{Scope: new(gatewayv1.ExportArticlesParamsScopeAll)})
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("export called = %v", called)
+			}
+		})
+	}
+}
@@ -6,11 +6,13 @@ import (
 	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
 )
 
 func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request, id gatewayv1.ArticleID) {
 	resp, err := h.client.GetArticle(r.Context(), &articlev1.GetArticleRequest{
-		Id: id.String(),
+		Id:       id.String(),
+		ViewerId: ViewerID(r),
 	})
 	if err != nil {
 		utils.HandleGRPCError(w, r, err)
@@ -25,3 +27,12 @@ func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request, id gatewayv
 
 	utils.WriteJSON(w, http.StatusOK, article)
 }
+
+// ViewerID - ключ дедупликации просмотров: пользователь, если токен передан, иначе IP
+func ViewerID(r *http.Request) string {
+	if userID, ok := middleware.UserIDFromContext(r.Context()); ok {
+		return "user:" + userID.String()
+	}
+
+	return "ip:" + utils.ClientIP(r)
+}
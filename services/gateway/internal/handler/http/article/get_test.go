@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/netip"
 	"testing"
 
 	"github.com/google/uuid"
@@ -14,6 +15,7 @@ import (
 
 	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
 )
 
 func TestGetArticle_Success(t *testing.T) {
@@ -91,3 +93,73 @@ func TestGetArticle_GRPCError(t *testing.T) {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
 	}
 }
+
+func TestGetArticle_ViewerFromIP(t *testing.T) {
+	client := &mockArticleClient{
+		getArticleFn: func(_ context.Context, in *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+			if in.GetViewerId() != "ip:203.0.113.7" {
+				t.Errorf("viewer_id = %q, want %q", in.GetViewerId(), "ip:203.0.113.7")
+			}
+			return &articlev1.GetArticleResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/"+uuid.Must(uuid.NewV7()).String(), "")
+	r.RemoteAddr = "10.0.0.1:5000"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	middleware.RealIP(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.GetArticle(w, r, uuid.Must(uuid.NewV7()))
+	})).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestGetArticle_SpoofedViewerIgnored(t *testing.T) {
+	client := &mockArticleClient{
+		getArticleFn: func(_ context.Context, in *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+			if in.GetViewerId() != "ip:198.51.100.1" {
+				t.Errorf("viewer_id = %q, want %q", in.GetViewerId(), "ip:198.51.100.1")
+			}
+			return &articlev1.GetArticleResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	// клиент не за trusted прокси: свой X-Forwarded-For не даёт новый просмотр
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/"+uuid.Must(uuid.NewV7()).String(), "")
+	r.RemoteAddr = "198.51.100.1:5000"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+	middleware.RealIP(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.GetArticle(w, r, uuid.Must(uuid.NewV7()))
+	})).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestGetArticle_ViewerFromUser(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		getArticleFn: func(_ context.Context, in *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+			if in.GetViewerId() != "user:"+userID.String() {
+				t.Errorf("viewer_id = %q, want %q", in.GetViewerId(), "user:"+userID.String())
+			}
+			return &articlev1.GetArticleResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/"+uuid.Must(uuid.NewV7()).String(), "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), userID))
+	h.GetArticle(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -3,6 +3,7 @@ package article
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 
@@ -17,6 +18,13 @@ type mockArticleClient struct {
 	updateArticleFn func(ctx context.Context, in *articlev1.UpdateArticleRequest, opts ...grpc.CallOption) (*articlev1.UpdateArticleResponse, error)
 	deleteArticleFn func(ctx context.Context, in *articlev1.DeleteArticleRequest, opts ...grpc.CallOption) (*articlev1.DeleteArticleResponse, error)
 	listArticlesFn  func(ctx context.Context, in *articlev1.ListArticlesRequest, opts ...grpc.CallOption) (*articlev1.ListArticlesResponse, error)
+	listMostReadFn  func(ctx context.Context, in *articlev1.ListMostReadRequest, opts ...grpc.CallOption) (*articlev1.ListMostReadResponse, error)
+	getRelatedFn    func(ctx context.Context, in *articlev1.GetRelatedArticlesRequest, opts ...grpc.CallOption) (*articlev1.GetRelatedArticlesResponse, error)
+	exportFn        func(ctx context.Context, in *articlev1.ExportArticlesRequest, opts ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error)
+	importFn        func(ctx context.Context, opts ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error)
+	reportFn        func(ctx context.Context, in *articlev1.ReportArticleRequest, opts ...grpc.CallOption) (*articlev1.ReportArticleResponse, error)
+	moderateFn      func(ctx context.Context, in *articlev1.ModerateArticleRequest, opts ...grpc.CallOption) (*articlev1.ModerateArticleResponse, error)
+	queueFn         func(ctx context.Context, in *articlev1.ListModerationQueueRequest, opts ...grpc.CallOption) (*articlev1.ListModerationQueueResponse, error)
 }
 
 func (m *mockArticleClient) CreateArticle(ctx context.Context, in *articlev1.CreateArticleRequest, opts ...grpc.CallOption) (*articlev1.CreateArticleResponse, error) {
@@ -39,6 +47,72 @@ func (m *mockArticleClient) ListArticles(ctx context.Context, in *articlev1.List
 	return m.listArticlesFn(ctx, in, opts...)
 }
 
+func (m *mockArticleClient) ListMostRead(ctx context.Context, in *articlev1.ListMostReadRequest, opts ...grpc.CallOption) (*articlev1.ListMostReadResponse, error) {
+	return m.listMostReadFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) GetRelatedArticles(ctx context.Context, in *articlev1.GetRelatedArticlesRequest, opts ...grpc.CallOption) (*articlev1.GetRelatedArticlesResponse, error) {
+	return m.getRelatedFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) ExportArticles(ctx context.Context, in *articlev1.ExportArticlesRequest, opts ...grpc.CallOption) (articlev1.ArticleService_ExportArticlesClient, error) {
+	return m.exportFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) ImportArticles(ctx context.Context, opts ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error) {
+	return m.importFn(ctx, opts...)
+}
+
+func (m *mockArticleClient) ReportArticle(ctx context.Context, in *articlev1.ReportArticleRequest, opts ...grpc.CallOption) (*articlev1.ReportArticleResponse, error) {
+	return m.reportFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) ModerateArticle(ctx context.Context, in *articlev1.ModerateArticleRequest, opts ...grpc.CallOption) (*articlev1.ModerateArticleResponse, error) {
+	return m.moderateFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) ListModerationQueue(ctx context.Context, in *articlev1.ListModerationQueueRequest, opts ...grpc.CallOption) (*articlev1.ListModerationQueueResponse, error) {
+	return m.queueFn(ctx, in, opts...)
+}
+
+// mockExportStream отдаёт articles по одной, затем err (io.EOF, если nil).
+type mockExportStream struct {
+	grpc.ClientStream
+	articles []*articlev1.Article
+	err      error
+}
+
+func (m *mockExportStream) Recv() (*articlev1.ExportArticlesResponse, error) {
+	if len(m.articles) == 0 {
+		if m.err != nil {
+			return nil, m.err
+		}
+		return nil, io.EOF
+	}
+
+	a := m.articles[0]
+	m.articles = m.articles[1:]
+
+	return &articlev1.ExportArticlesResponse{Article: a}, nil
+}
+
+// mockImportStream копит отправленные запросы и отвечает resp/err на CloseAndRecv.
+type mockImportStream struct {
+	grpc.ClientStream
+	sent []*articlev1.ImportArticlesRequest
+	resp *articlev1.ImportArticlesResponse
+	err  error
+}
+
+func (m *mockImportStream) Send(req *articlev1.ImportArticlesRequest) error {
+	m.sent = append(m.sent, req)
+	return nil
+}
+
+func (m *mockImportStream) CloseAndRecv() (*articlev1.ImportArticlesResponse, error) {
+	return m.resp, m.err
+}
+
 func newTestHandler(client *mockArticleClient) *Handler {
 	return New(client)
 }
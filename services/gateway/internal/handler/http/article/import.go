@@ -0,0 +1,155 @@
+package article
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+// maxImportSize - предел тела запроса импорта. Архив читается потоком, предел защищает article от бесконечной загрузки.
+const maxImportSize = 32 << 20
+
+func (h *Handler) ImportArticles(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	defer func() {
+		_ = body.Close() //nolint:gosec
+	}()
+
+	var archive archiveReader
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case ndjsonContentType:
+		archive = newNDJSONReader(body)
+	case tarContentType:
+		archive = newMarkdownReader(body)
+	default:
+		utils.WriteError(w, r, http.StatusUnsupportedMediaType, "unsupported content type")
+		return
+	}
+
+	// Отмена контекста обрывает поток: article откатывает незавершённую пачку и не отвечает.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stream, err := h.client.ImportArticles(ctx)
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	var (
+		sent       int
+		archiveErr error
+	)
+
+	for {
+		item, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if sent == 0 {
+				cancel()
+				writeArchiveError(w, r, err)
+				return
+			}
+
+			// article уже мог сохранить пачки из отправленных статей: поток закрывается штатно,
+			// остаток сохраняется, и клиент получает отчёт по всему, что дошло до ошибки.
+			archiveErr = err
+			break
+		}
+
+		req := &articlev1.ImportArticlesRequest{
+			AuthorId: userID.String(),
+			Title:    item.Title,
+			Content:  item.Content,
+		}
+		if item.CreatedAt != nil {
+			req.CreatedAt = timestamppb.New(*item.CreatedAt)
+		}
+
+		// io.EOF от Send - сервер закрыл поток, настоящая ошибка придёт из CloseAndRecv.
+		if err := stream.Send(req); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			utils.HandleGRPCError(w, r, err)
+			return
+		}
+		sent++
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	out := toImportArticlesResponse(resp)
+	if archiveErr != nil {
+		_, detail := archiveError(archiveErr)
+		out.Error = &detail
+	}
+
+	utils.WriteJSON(w, http.StatusOK, out)
+}
+
+func writeArchiveError(w http.ResponseWriter, r *http.Request, err error) {
+	code, detail := archiveError(err)
+	utils.WriteError(w, r, code, detail)
+}
+
+// archiveError - статус и текст ошибки чтения архива.
+func archiveError(err error) (int, string) {
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge, "archive too large"
+	case errors.Is(err, errInvalidArchive):
+		return http.StatusBadRequest, err.Error()
+	default:
+		return http.StatusBadRequest, "invalid request body"
+	}
+}
+
+func toImportArticlesResponse(resp *articlev1.ImportArticlesResponse) gatewayv1.ImportArticlesResponse {
+	results := make([]gatewayv1.ImportArticleResult, len(resp.GetResults()))
+	for i, res := range resp.GetResults() {
+		results[i].Index = new(int(res.GetIndex()))
+
+		if res.GetError() != "" {
+			results[i].Error = new(res.GetError())
+			continue
+		}
+
+		if id, err := uuid.Parse(res.GetId()); err == nil {
+			results[i].Id = &id
+		}
+	}
+
+	return gatewayv1.ImportArticlesResponse{
+		Imported:         new(int(resp.GetImported())),
+		Failed:           new(int(resp.GetFailed())),
+		Results:          &results,
+		ResultsTruncated: new(resp.GetResultsTruncated()),
+	}
+}
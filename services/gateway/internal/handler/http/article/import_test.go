@@ -0,0 +1,199 @@
+package article
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func TestImportArticles_NDJSON(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	createdID := uuid.Must(uuid.NewV7())
+
+	stream := &mockImportStream{
+		resp: &articlev1.ImportArticlesResponse{
+			Imported: 1,
+			Failed:   1,
+			Results: []*articlev1.ImportArticleResult{
+				{Index: 0, Id: createdID.String()},
+				{Index: 1, Error: "invalid title"},
+			},
+		},
+	}
+	client := &mockArticleClient{
+		importFn: func(_ context.Context, _ ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error) {
+			return stream, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	body := `{"title":"First","content":"text","created_at":"2024-01-02T03:04:05Z"}` + "\n" + `{"title":"","content":"text"}` + "\n"
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/import", body)
+	r.Header.Set("Content-Type", ndjsonContentType)
+	r = r.WithContext(middleware.WithUserID(r.Context(), userID))
+
+	h.ImportArticles(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	if len(stream.sent) != 2 {
+		t.Fatalf("sent = %d requests, want 2", len(stream.sent))
+	}
+
+	if stream.sent[0].GetAuthorId() != userID.String() {
+		t.Errorf("author_id = %q, want %q", stream.sent[0].GetAuthorId(), userID.String())
+	}
+
+	if stream.sent[0].GetCreatedAt() == nil || stream.sent[1].GetCreatedAt() != nil {
+		t.Error("created_at must be forwarded only when present")
+	}
+
+	var resp gatewayv1.ImportArticlesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if *resp.Imported != 1 || *resp.Failed != 1 {
+		t.Errorf("imported/failed = %d/%d, want 1/1", *resp.Imported, *resp.Failed)
+	}
+
+	results := *resp.Results
+	if results[0].Id == nil || *results[0].Id != createdID {
+		t.Errorf("results[0].Id = %v, want %v", results[0].Id, createdID)
+	}
+	if results[1].Error == nil || *results[1].Error != "invalid title" {
+		t.Errorf("results[1].Error = %v, want invalid title", results[1].Error)
+	}
+}
+
+func TestImportArticles_Markdown(t *testing.T) {
+	stream := &mockImportStream{resp: &articlev1.ImportArticlesResponse{}}
+	client := &mockArticleClient{
+		importFn: func(_ context.Context, _ ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error) {
+			return stream, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	data := []byte("---\ntitle: \"Из архива\"\n---\nТекст")
+	_ = tw.WriteHeader(&tar.Header{Name: "a.md", Mode: 0o644, Size: int64(len(data))})
+	_, _ = tw.Write(data)
+	_ = tw.WriteHeader(&tar.Header{Name: "README.txt", Mode: 0o644, Size: 1})
+	_, _ = tw.Write([]byte("x"))
+	_ = tw.Close()
+
+	r, _ := http.NewRequest(http.MethodPost, "/api/v1/articles/import", &buf)
+	r.Header.Set("Content-Type", tarContentType)
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+	w, _ := makeRequest(http.MethodPost, "/", "")
+
+	h.ImportArticles(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if len(stream.sent) != 1 || stream.sent[0].GetTitle() != "Из архива" || stream.sent[0].GetContent() != "Текст" {
+		t.Errorf("sent = %v, want one article from a.md", stream.sent)
+	}
+}
+
+func TestImportArticles_InvalidLine(t *testing.T) {
+	client := &mockArticleClient{
+		importFn: func(_ context.Context, _ ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error) {
+			return &mockImportStream{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/import", "not json\n")
+	r.Header.Set("Content-Type", ndjsonContentType)
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ImportArticles(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestImportArticles_InvalidLineAfterSent(t *testing.T) {
+	createdID := uuid.Must(uuid.NewV7())
+	stream := &mockImportStream{
+		resp: &articlev1.ImportArticlesResponse{
+			Imported: 1,
+			Results:  []*articlev1.ImportArticleResult{{Index: 0, Id: createdID.String()}},
+		},
+	}
+	client := &mockArticleClient{
+		importFn: func(_ context.Context, _ ...grpc.CallOption) (articlev1.ArticleService_ImportArticlesClient, error) {
+			return stream, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	body := `{"title":"First","content":"text"}` + "\n" + "not json\n"
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/import", body)
+	r.Header.Set("Content-Type", ndjsonContentType)
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ImportArticles(w, r)
+
+	// первая статья уже сохранена - клиент получает отчёт, а не 400
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if len(stream.sent) != 1 {
+		t.Errorf("sent = %d requests, want 1", len(stream.sent))
+	}
+
+	var resp gatewayv1.ImportArticlesResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if *resp.Imported != 1 || *resp.Failed != 0 {
+		t.Errorf("imported/failed = %d/%d, want 1/0", *resp.Imported, *resp.Failed)
+	}
+	if resp.Error == nil || *resp.Error == "" {
+		t.Error("error must explain why the import stopped")
+	}
+}
+
+func TestImportArticles_UnsupportedContentType(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/import", `{"title":"a"}`)
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ImportArticles(w, r)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+	}
+}
+
+func TestImportArticles_Unauthorized(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/import", "")
+
+	h.ImportArticles(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
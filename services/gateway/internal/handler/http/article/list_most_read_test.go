@@ -0,0 +1,76 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+)
+
+func TestListMostReadArticles_Success(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		listMostReadFn: func(_ context.Context, in *articlev1.ListMostReadRequest, _ ...grpc.CallOption) (*articlev1.ListMostReadResponse, error) {
+			if in.GetLimit() != 5 {
+				t.Errorf("limit = %d, want 5", in.GetLimit())
+			}
+			return &articlev1.ListMostReadResponse{
+				Articles: []*articlev1.Article{
+					{
+						Id:        articleID.String(),
+						AuthorId:  uuid.Must(uuid.NewV7()).String(),
+						Title:     "Popular",
+						ViewCount: 42,
+					},
+				},
+			}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/most-read?limit=5", "")
+	h.ListMostReadArticles(w, r, gatewayv1.ListMostReadArticlesParams{Limit: new(5)})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp gatewayv1.ArticleListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Articles == nil || len(*resp.Articles) != 1 {
+		t.Fatalf("articles = %v, want 1 article", resp.Articles)
+	}
+
+	article := (*resp.Articles)[0]
+	if article.ViewCount == nil || *article.ViewCount != 42 {
+		t.Errorf("view_count = %v, want 42", article.ViewCount)
+	}
+}
+
+func TestListMostReadArticles_GRPCError(t *testing.T) {
+	client := &mockArticleClient{
+		listMostReadFn: func(_ context.Context, _ *articlev1.ListMostReadRequest, _ ...grpc.CallOption) (*articlev1.ListMostReadResponse, error) {
+			return nil, status.Error(codes.Internal, "database error")
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/most-read", "")
+	h.ListMostReadArticles(w, r, gatewayv1.ListMostReadArticlesParams{})
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
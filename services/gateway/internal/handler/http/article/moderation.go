@@ -0,0 +1,108 @@
+package article
+
+import (
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func (h *Handler) ModerateArticle(w http.ResponseWriter, r *http.Request, id gatewayv1.ArticleID) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !middleware.HasRole(r.Context(), middleware.RoleModerator) {
+		utils.WriteError(w, r, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	var req gatewayv1.ModerateArticleRequest
+	if err := utils.DecodeBody(r, &req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	action, ok := toProtoModerationAction(req.Action)
+	if !ok {
+		utils.WriteError(w, r, http.StatusBadRequest, "invalid moderation action")
+		return
+	}
+
+	_, err := h.client.ModerateArticle(r.Context(), &articlev1.ModerateArticleRequest{
+		Id:          id.String(),
+		ModeratorId: userID.String(),
+		Action:      action,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListModerationQueue(w http.ResponseWriter, r *http.Request, params gatewayv1.ListModerationQueueParams) {
+	if _, ok := middleware.UserIDFromContext(r.Context()); !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !middleware.HasRole(r.Context(), middleware.RoleModerator) {
+		utils.WriteError(w, r, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	req := &articlev1.ListModerationQueueRequest{}
+	if params.Limit != nil && *params.Limit > 0 && *params.Limit <= 100 {
+		req.Limit = int32(*params.Limit)
+	}
+
+	resp, err := h.client.ListModerationQueue(r.Context(), req)
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	items := make([]gatewayv1.ReportedArticleResponse, 0, len(resp.GetItems()))
+	for _, item := range resp.GetItems() {
+		article, err := toArticleResponse(item.GetArticle())
+		if err != nil {
+			utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		reported := gatewayv1.ReportedArticleResponse{
+			Article:     &article,
+			ReportCount: new(int(item.GetReportCount())),
+			Reasons:     new(item.GetReasons()),
+		}
+		if item.GetFirstReportedAt() != nil {
+			reported.FirstReportedAt = new(item.GetFirstReportedAt().AsTime())
+		}
+
+		items = append(items, reported)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, gatewayv1.ModerationQueueResponse{
+		Items: &items,
+	})
+}
+
+func toProtoModerationAction(action gatewayv1.ModerateArticleRequestAction) (articlev1.ModerationAction, bool) {
+	switch action {
+	case gatewayv1.ModerateArticleRequestActionHide:
+		return articlev1.ModerationAction_MODERATION_ACTION_HIDE, true
+	case gatewayv1.ModerateArticleRequestActionDelete:
+		return articlev1.ModerationAction_MODERATION_ACTION_DELETE, true
+	case gatewayv1.ModerateArticleRequestActionDismiss:
+		return articlev1.ModerationAction_MODERATION_ACTION_DISMISS, true
+	default:
+		return articlev1.ModerationAction_MODERATION_ACTION_UNSPECIFIED, false
+	}
+}
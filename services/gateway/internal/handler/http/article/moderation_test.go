@@ -0,0 +1,125 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func TestModerateArticle_Success(t *testing.T) {
+	moderatorID := uuid.Must(uuid.NewV7())
+	articleID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		moderateFn: func(_ context.Context, in *articlev1.ModerateArticleRequest, _ ...grpc.CallOption) (*articlev1.ModerateArticleResponse, error) {
+			if in.GetModeratorId() != moderatorID.String() {
+				t.Errorf("moderator_id = %q, want %q", in.GetModeratorId(), moderatorID.String())
+			}
+			if in.GetAction() != articlev1.ModerationAction_MODERATION_ACTION_HIDE {
+				t.Errorf("action = %v, want HIDE", in.GetAction())
+			}
+			return &articlev1.ModerateArticleResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/moderation/articles/"+articleID.String(), `{"action":"hide","reason":"spam"}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), moderatorID, middleware.RoleModerator))
+
+	h.ModerateArticle(w, r, articleID)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestModerateArticle_Forbidden(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/moderation/articles/x", `{"action":"delete","reason":"spam"}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), "user"))
+
+	h.ModerateArticle(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestModerateArticle_InvalidAction(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/moderation/articles/x", `{"action":"ban","reason":"spam"}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), middleware.RoleAdmin))
+
+	h.ModerateArticle(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListModerationQueue_Success(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		queueFn: func(_ context.Context, _ *articlev1.ListModerationQueueRequest, _ ...grpc.CallOption) (*articlev1.ListModerationQueueResponse, error) {
+			return &articlev1.ListModerationQueueResponse{
+				Items: []*articlev1.ReportedArticle{
+					{
+						Article: &articlev1.Article{
+							Id:       articleID.String(),
+							AuthorId: uuid.Must(uuid.NewV7()).String(),
+							Title:    "Reported",
+						},
+						ReportCount:     3,
+						Reasons:         []string{"spam", "ads"},
+						FirstReportedAt: timestamppb.Now(),
+					},
+				},
+			}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/moderation/queue", "")
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), middleware.RoleModerator))
+
+	h.ListModerationQueue(w, r, gatewayv1.ListModerationQueueParams{})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp gatewayv1.ModerationQueueResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	items := *resp.Items
+	if len(items) != 1 || *items[0].ReportCount != 3 || *items[0].Article.Id != articleID {
+		t.Errorf("items = %+v, want one article with 3 reports", items)
+	}
+}
+
+func TestListModerationQueue_Forbidden(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/moderation/queue", "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ListModerationQueue(w, r, gatewayv1.ListModerationQueueParams{})
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
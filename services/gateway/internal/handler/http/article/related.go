@@ -0,0 +1,38 @@
+package article
+
+import (
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+func (h *Handler) GetRelatedArticles(w http.ResponseWriter, r *http.Request, id gatewayv1.ArticleID, params gatewayv1.GetRelatedArticlesParams) {
+	req := &articlev1.GetRelatedArticlesRequest{
+		Id: id.String(),
+	}
+	if params.Limit != nil && *params.Limit > 0 && *params.Limit <= 50 {
+		req.Limit = int32(*params.Limit)
+	}
+
+	resp, err := h.client.GetRelatedArticles(r.Context(), req)
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	articles := make([]gatewayv1.ArticleResponse, 0, len(resp.GetArticles()))
+	for _, a := range resp.GetArticles() {
+		article, err := toArticleResponse(a)
+		if err != nil {
+			utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+		articles = append(articles, article)
+	}
+
+	utils.WriteJSON(w, http.StatusOK, gatewayv1.ArticleListResponse{
+		Articles: &articles,
+	})
+}
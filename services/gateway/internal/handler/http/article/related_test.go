@@ -0,0 +1,74 @@
+package article
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+)
+
+func TestGetRelatedArticles_Success(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	relatedID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		getRelatedFn: func(_ context.Context, in *articlev1.GetRelatedArticlesRequest, _ ...grpc.CallOption) (*articlev1.GetRelatedArticlesResponse, error) {
+			if in.GetId() != articleID.String() {
+				t.Errorf("id = %q, want %q", in.GetId(), articleID.String())
+			}
+			if in.GetLimit() != 3 {
+				t.Errorf("limit = %d, want 3", in.GetLimit())
+			}
+			return &articlev1.GetRelatedArticlesResponse{
+				Articles: []*articlev1.Article{
+					{Id: relatedID.String(), AuthorId: uuid.Must(uuid.NewV7()).String(), Title: "Related"},
+				},
+			}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/"+articleID.String()+"/related?limit=3", "")
+	h.GetRelatedArticles(w, r, articleID, gatewayv1.GetRelatedArticlesParams{Limit: new(3)})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp gatewayv1.ArticleListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	if resp.Articles == nil || len(*resp.Articles) != 1 {
+		t.Fatalf("articles = %v, want 1 article", resp.Articles)
+	}
+	if got := (*resp.Articles)[0].Id; got == nil || *got != relatedID {
+		t.Errorf("id = %v, want %v", got, relatedID)
+	}
+}
+
+func TestGetRelatedArticles_NotFound(t *testing.T) {
+	client := &mockArticleClient{
+		getRelatedFn: func(_ context.Context, _ *articlev1.GetRelatedArticlesRequest, _ ...grpc.CallOption) (*articlev1.GetRelatedArticlesResponse, error) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		},
+	}
+	h := newTestHandler(client)
+
+	id := uuid.Must(uuid.NewV7())
+	w, r := makeRequest(http.MethodGet, "/api/v1/articles/"+id.String()+"/related", "")
+	h.GetRelatedArticles(w, r, id, gatewayv1.GetRelatedArticlesParams{})
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,36 @@
+package article
+
+import (
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func (h *Handler) ReportArticle(w http.ResponseWriter, r *http.Request, id gatewayv1.ArticleID) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req gatewayv1.ReportArticleRequest
+	if err := utils.DecodeBody(r, &req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	_, err := h.client.ReportArticle(r.Context(), &articlev1.ReportArticleRequest{
+		Id:         id.String(),
+		ReporterId: userID.String(),
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package article
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func TestReportArticle_Success(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	articleID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		reportFn: func(_ context.Context, in *articlev1.ReportArticleRequest, _ ...grpc.CallOption) (*articlev1.ReportArticleResponse, error) {
+			if in.GetId() != articleID.String() || in.GetReporterId() != userID.String() {
+				t.Errorf("request = %v, want article %s reported by %s", in, articleID, userID)
+			}
+			if in.GetReason() != "spam" {
+				t.Errorf("reason = %q, want %q", in.GetReason(), "spam")
+			}
+			return &articlev1.ReportArticleResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/"+articleID.String()+"/report", `{"reason":"spam"}`)
+	r = r.WithContext(middleware.WithUserID(r.Context(), userID))
+
+	h.ReportArticle(w, r, articleID)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestReportArticle_Unauthorized(t *testing.T) {
+	h := newTestHandler(&mockArticleClient{})
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/x/report", `{"reason":"spam"}`)
+
+	h.ReportArticle(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestReportArticle_NotFound(t *testing.T) {
+	client := &mockArticleClient{
+		reportFn: func(_ context.Context, _ *articlev1.ReportArticleRequest, _ ...grpc.CallOption) (*articlev1.ReportArticleResponse, error) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest(http.MethodPost, "/api/v1/articles/x/report", `{"reason":"spam"}`)
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+
+	h.ReportArticle(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
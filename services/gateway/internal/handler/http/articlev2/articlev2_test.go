@@ -0,0 +1,122 @@
+package articlev2
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv2 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v2"
+)
+
+func testArticle(id, authorID uuid.UUID) *articlev1.Article {
+	return &articlev1.Article{
+		Id:        id.String(),
+		AuthorId:  authorID.String(),
+		Title:     "Test Title",
+		Content:   "Test Content",
+		ViewCount: 42,
+		CreatedAt: timestamppb.Now(),
+		UpdatedAt: timestamppb.Now(),
+	}
+}
+
+func TestGetArticle_Shape(t *testing.T) {
+	articleID := uuid.Must(uuid.NewV7())
+	authorID := uuid.Must(uuid.NewV7())
+
+	client := &mockArticleClient{
+		getArticleFn: func(_ context.Context, _ *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+			return &articlev1.GetArticleResponse{Article: testArticle(articleID, authorID)}, nil
+		},
+	}
+	h := New(client)
+
+	w, r := makeGetRequest("/api/v2/articles/" + articleID.String())
+	h.GetArticle(w, r, articleID)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp gatewayv2.Article
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Id != articleID || resp.Author.Id != authorID {
+		t.Errorf("id = %v, author = %v", resp.Id, resp.Author.Id)
+	}
+	if resp.Stats.Views != 42 {
+		t.Errorf("views = %d, want 42", resp.Stats.Views)
+	}
+}
+
+func TestGetArticle_NotFound(t *testing.T) {
+	client := &mockArticleClient{
+		getArticleFn: func(_ context.Context, _ *articlev1.GetArticleRequest, _ ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+			return nil, status.Error(codes.NotFound, "article not found")
+		},
+	}
+	h := New(client)
+
+	id := uuid.Must(uuid.NewV7())
+	w, r := makeGetRequest("/api/v2/articles/" + id.String())
+	h.GetArticle(w, r, id)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestListArticles_Page(t *testing.T) {
+	tests := []struct {
+		name        string
+		nextCursor  string
+		wantHasMore bool
+	}{
+		{name: "more pages", nextCursor: "cursor-2", wantHasMore: true},
+		{name: "last page", nextCursor: "", wantHasMore: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockArticleClient{
+				listArticlesFn: func(_ context.Context, _ *articlev1.ListArticlesRequest, _ ...grpc.CallOption) (*articlev1.ListArticlesResponse, error) {
+					return &articlev1.ListArticlesResponse{
+						Articles:   []*articlev1.Article{testArticle(uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7()))},
+						NextCursor: tt.nextCursor,
+					}, nil
+				},
+			}
+			h := New(client)
+
+			w, r := makeGetRequest("/api/v2/articles")
+			h.ListArticles(w, r, gatewayv2.ListArticlesParams{})
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+
+			var resp gatewayv2.ArticleList
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if len(resp.Items) != 1 {
+				t.Errorf("items = %d, want 1", len(resp.Items))
+			}
+			if resp.Page.HasMore != tt.wantHasMore {
+				t.Errorf("has_more = %v, want %v", resp.Page.HasMore, tt.wantHasMore)
+			}
+			if tt.wantHasMore && (resp.Page.NextCursor == nil || *resp.Page.NextCursor != tt.nextCursor) {
+				t.Errorf("next_cursor = %v, want %q", resp.Page.NextCursor, tt.nextCursor)
+			}
+		})
+	}
+}
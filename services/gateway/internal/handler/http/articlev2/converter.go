@@ -0,0 +1,32 @@
+package articlev2
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv2 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v2"
+)
+
+func toArticle(a *articlev1.Article) (gatewayv2.Article, error) {
+	id, err := uuid.Parse(a.GetId())
+	if err != nil {
+		return gatewayv2.Article{}, fmt.Errorf("parse article id: %w", err)
+	}
+
+	authorID, err := uuid.Parse(a.GetAuthorId())
+	if err != nil {
+		return gatewayv2.Article{}, fmt.Errorf("parse author id: %w", err)
+	}
+
+	return gatewayv2.Article{
+		Id:        id,
+		Title:     a.GetTitle(),
+		Content:   a.GetContent(),
+		Author:    gatewayv2.ArticleAuthor{Id: authorID},
+		Stats:     gatewayv2.ArticleStats{Views: a.GetViewCount()},
+		CreatedAt: a.GetCreatedAt().AsTime(),
+		UpdatedAt: a.GetUpdatedAt().AsTime(),
+	}, nil
+}
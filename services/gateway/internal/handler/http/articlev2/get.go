@@ -0,0 +1,29 @@
+package articlev2
+
+import (
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv2 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v2"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/article"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+func (h *Handler) GetArticle(w http.ResponseWriter, r *http.Request, id gatewayv2.ArticleID) {
+	resp, err := h.client.GetArticle(r.Context(), &articlev1.GetArticleRequest{
+		Id:       id.String(),
+		ViewerId: article.ViewerID(r),
+	})
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	a, err := toArticle(resp.GetArticle())
+	if err != nil {
+		utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, a)
+}
@@ -0,0 +1,14 @@
+package articlev2
+
+import (
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+)
+
+// Handler - статьи API v2. Ходит в тот же Article Service, меняется только форма ответа.
+type Handler struct {
+	client articlev1.ArticleServiceClient
+}
+
+func New(client articlev1.ArticleServiceClient) *Handler {
+	return &Handler{client: client}
+}
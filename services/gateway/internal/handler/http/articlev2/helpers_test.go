@@ -0,0 +1,31 @@
+package articlev2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"google.golang.org/grpc"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+)
+
+// mockArticleClient - v2 вызывает только чтение, остальные методы достаются от nil интерфейса и не должны вызываться.
+type mockArticleClient struct {
+	articlev1.ArticleServiceClient
+
+	getArticleFn   func(ctx context.Context, in *articlev1.GetArticleRequest, opts ...grpc.CallOption) (*articlev1.GetArticleResponse, error)
+	listArticlesFn func(ctx context.Context, in *articlev1.ListArticlesRequest, opts ...grpc.CallOption) (*articlev1.ListArticlesResponse, error)
+}
+
+func (m *mockArticleClient) GetArticle(ctx context.Context, in *articlev1.GetArticleRequest, opts ...grpc.CallOption) (*articlev1.GetArticleResponse, error) {
+	return m.getArticleFn(ctx, in, opts...)
+}
+
+func (m *mockArticleClient) ListArticles(ctx context.Context, in *articlev1.ListArticlesRequest, opts ...grpc.CallOption) (*articlev1.ListArticlesResponse, error) {
+	return m.listArticlesFn(ctx, in, opts...)
+}
+
+func makeGetRequest(path string) (*httptest.ResponseRecorder, *http.Request) {
+	return httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil)
+}
@@ -0,0 +1,42 @@
+package articlev2
+
+import (
+	"net/http"
+
+	articlev1 "github.com/SonOfSteveJobs/habr/pkg/gen/article/v1"
+	gatewayv2 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v2"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+func (h *Handler) ListArticles(w http.ResponseWriter, r *http.Request, params gatewayv2.ListArticlesParams) {
+	req := &articlev1.ListArticlesRequest{}
+	if params.Cursor != nil {
+		req.Cursor = *params.Cursor
+	}
+	if params.Limit != nil && *params.Limit > 0 && *params.Limit <= 100 {
+		req.Limit = int32(*params.Limit)
+	}
+
+	resp, err := h.client.ListArticles(r.Context(), req)
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	items := make([]gatewayv2.Article, 0, len(resp.GetArticles()))
+	for _, a := range resp.GetArticles() {
+		item, err := toArticle(a)
+		if err != nil {
+			utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+		items = append(items, item)
+	}
+
+	page := gatewayv2.Page{HasMore: resp.GetNextCursor() != ""}
+	if page.HasMore {
+		page.NextCursor = new(resp.GetNextCursor())
+	}
+
+	utils.WriteJSON(w, http.StatusOK, gatewayv2.ArticleList{Items: items, Page: page})
+}
@@ -250,3 +250,60 @@ func TestVerifyEmail_GRPCError(t *testing.T) {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
 	}
 }
+
+func TestSetUserRoles_Success(t *testing.T) {
+	targetID := uuid.Must(uuid.NewV7())
+
+	client := &mockAuthClient{
+		setUserRolesFn: func(_ context.Context, in *authv1.SetUserRolesRequest, _ ...grpc.CallOption) (*authv1.SetUserRolesResponse, error) {
+			if in.GetUserId() != targetID.String() {
+				t.Errorf("user_id = %q, want %q", in.GetUserId(), targetID.String())
+			}
+			if len(in.GetRoles()) != 1 || in.GetRoles()[0] != "moderator" {
+				t.Errorf("roles = %v, want [moderator]", in.GetRoles())
+			}
+			return &authv1.SetUserRolesResponse{}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest("/api/v1/admin/users/"+targetID.String()+"/roles", `{"roles":["moderator"]}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), middleware.RoleAdmin))
+
+	h.SetUserRoles(w, r, targetID)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestSetUserRoles_NotAdmin(t *testing.T) {
+	h := newTestHandler(&mockAuthClient{})
+
+	w, r := makeRequest("/api/v1/admin/users/x/roles", `{"roles":["admin"]}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), middleware.RoleModerator))
+
+	h.SetUserRoles(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestSetUserRoles_UserNotFound(t *testing.T) {
+	client := &mockAuthClient{
+		setUserRolesFn: func(_ context.Context, _ *authv1.SetUserRolesRequest, _ ...grpc.CallOption) (*authv1.SetUserRolesResponse, error) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest("/api/v1/admin/users/x/roles", `{"roles":["moderator"]}`)
+	r = r.WithContext(middleware.WithUser(r.Context(), uuid.Must(uuid.NewV7()), middleware.RoleAdmin))
+
+	h.SetUserRoles(w, r, uuid.Must(uuid.NewV7()))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
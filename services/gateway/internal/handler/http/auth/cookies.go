@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+const (
+	accessCookiePath = "/api"
+	// refresh token нужен только ручкам auth, на остальные запросы он не уходит
+	refreshCookiePath = "/api/v1/auth"
+	csrfTokenBytes    = 32
+)
+
+// CookieConfig - режим cookie: login и refresh кроме тела ответа ставят HttpOnly cookie с токенами
+// и читаемую JS cookie с CSRF токеном.
+type CookieConfig struct {
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+	MaxAge   time.Duration
+}
+
+func (c *CookieConfig) cookie(name, value, path string, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   c.Domain,
+		MaxAge:   int(c.MaxAge.Seconds()),
+		Secure:   c.Secure,
+		HttpOnly: httpOnly,
+		SameSite: c.SameSite,
+	}
+}
+
+// setAuthCookies ставит токены и новый CSRF токен. В refresh cookie рядом с токеном лежит user_id:
+// refresh без него не выполнить, а подделать пару не выйдет - Auth Service проверяет, что токен принадлежит пользователю.
+func (h *Handler) setAuthCookies(w http.ResponseWriter, accessToken, refreshToken string) error {
+	userID, err := middleware.TokenSubject(accessToken)
+	if err != nil {
+		return fmt.Errorf("access token subject: %w", err)
+	}
+
+	csrf := make([]byte, csrfTokenBytes)
+	if _, err := rand.Read(csrf); err != nil {
+		return fmt.Errorf("csrf token: %w", err)
+	}
+
+	http.SetCookie(w, h.cookies.cookie(middleware.AccessTokenCookie, accessToken, accessCookiePath, true))
+	http.SetCookie(w, h.cookies.cookie(middleware.RefreshTokenCookie, userID.String()+":"+refreshToken, refreshCookiePath, true))
+	http.SetCookie(w, h.cookies.cookie(middleware.CSRFTokenCookie, base64.RawURLEncoding.EncodeToString(csrf), "/", false))
+
+	return nil
+}
+
+func (h *Handler) clearAuthCookies(w http.ResponseWriter) {
+	for _, c := range []*http.Cookie{
+		h.cookies.cookie(middleware.AccessTokenCookie, "", accessCookiePath, true),
+		h.cookies.cookie(middleware.RefreshTokenCookie, "", refreshCookiePath, true),
+		h.cookies.cookie(middleware.CSRFTokenCookie, "", "/", false),
+	} {
+		c.MaxAge = -1
+		http.SetCookie(w, c)
+	}
+}
+
+func refreshFromCookie(r *http.Request) (uuid.UUID, string, bool) {
+	c, err := r.Cookie(middleware.RefreshTokenCookie)
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+
+	rawUserID, token, ok := strings.Cut(c.Value, ":")
+	if !ok || token == "" {
+		return uuid.Nil, "", false
+	}
+
+	userID, err := uuid.Parse(rawUserID)
+	if err != nil {
+		return uuid.Nil, "", false
+	}
+
+	return userID, token, true
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func TestLogin_SetsCookies(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	access := testAccessToken(userID)
+	client := &mockAuthClient{
+		loginFn: func(_ context.Context, _ *authv1.LoginRequest, _ ...grpc.CallOption) (*authv1.LoginResponse, error) {
+			return &authv1.LoginResponse{AccessToken: access, RefreshToken: "refresh-token"}, nil
+		},
+	}
+	h := newCookieTestHandler(client)
+
+	w, r := makeRequest("/api/v1/auth/login", `{"email":"user@example.com","password":"pass123"}`)
+	h.Login(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	cookies := responseCookies(w)
+
+	accessCookie := cookies[middleware.AccessTokenCookie]
+	if accessCookie == nil || accessCookie.Value != access || !accessCookie.HttpOnly || !accessCookie.Secure {
+		t.Errorf("access cookie = %+v", accessCookie)
+	}
+
+	refreshCookie := cookies[middleware.RefreshTokenCookie]
+	if refreshCookie == nil || refreshCookie.Value != userID.String()+":refresh-token" || refreshCookie.Path != refreshCookiePath {
+		t.Errorf("refresh cookie = %+v", refreshCookie)
+	}
+
+	csrfCookie := cookies[middleware.CSRFTokenCookie]
+	if csrfCookie == nil || csrfCookie.Value == "" || csrfCookie.HttpOnly {
+		t.Errorf("csrf cookie = %+v", csrfCookie)
+	}
+}
+
+func TestLogin_NoCookiesByDefault(t *testing.T) {
+	client := &mockAuthClient{
+		loginFn: func(_ context.Context, _ *authv1.LoginRequest, _ ...grpc.CallOption) (*authv1.LoginResponse, error) {
+			return &authv1.LoginResponse{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil
+		},
+	}
+	h := newTestHandler(client)
+
+	w, r := makeRequest("/api/v1/auth/login", `{"email":"user@example.com","password":"pass123"}`)
+	h.Login(w, r)
+
+	if cookies := w.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("cookies = %v, want none", cookies)
+	}
+}
+
+func TestRefreshToken_FromCookie(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	var got *authv1.RefreshTokenRequest
+	client := &mockAuthClient{
+		refreshTokenFn: func(_ context.Context, in *authv1.RefreshTokenRequest, _ ...grpc.CallOption) (*authv1.RefreshTokenResponse, error) {
+			got = in
+			return &authv1.RefreshTokenResponse{AccessToken: testAccessToken(userID), RefreshToken: "new-refresh"}, nil
+		},
+	}
+	h := newCookieTestHandler(client)
+
+	w, r := makeRequest("/api/v1/auth/refresh", "")
+	r.AddCookie(&http.Cookie{Name: middleware.RefreshTokenCookie, Value: userID.String() + ":old-refresh"})
+	h.RefreshToken(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got == nil || got.GetUserId() != userID.String() || got.GetRefreshToken() != "old-refresh" {
+		t.Errorf("refresh request = %v", got)
+	}
+	if c := responseCookies(w)[middleware.RefreshTokenCookie]; c == nil || c.Value != userID.String()+":new-refresh" {
+		t.Errorf("refresh cookie = %+v", c)
+	}
+}
+
+func TestRefreshToken_NoBodyNoCookie(t *testing.T) {
+	h := newCookieTestHandler(&mockAuthClient{})
+
+	w, r := makeRequest("/api/v1/auth/refresh", "")
+	h.RefreshToken(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestLogout_ClearsCookies(t *testing.T) {
+	client := &mockAuthClient{
+		logoutFn: func(_ context.Context, _ *authv1.LogoutRequest, _ ...grpc.CallOption) (*authv1.LogoutResponse, error) {
+			return &authv1.LogoutResponse{}, nil
+		},
+	}
+	h := newCookieTestHandler(client)
+
+	w, r := makeRequest("/api/v1/auth/logout", "")
+	r = r.WithContext(middleware.WithUserID(r.Context(), uuid.Must(uuid.NewV7())))
+	h.Logout(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	for _, name := range []string{middleware.AccessTokenCookie, middleware.RefreshTokenCookie, middleware.CSRFTokenCookie} {
+		if c := responseCookies(w)[name]; c == nil || c.MaxAge >= 0 {
+			t.Errorf("cookie %s = %+v, want expired", name, c)
+		}
+	}
+}
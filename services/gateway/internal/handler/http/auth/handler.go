@@ -5,9 +5,11 @@ import (
 )
 
 type Handler struct {
-	client authv1.AuthServiceClient
+	client  authv1.AuthServiceClient
+	cookies *CookieConfig
 }
 
-func New(client authv1.AuthServiceClient) *Handler {
-	return &Handler{client: client}
+// New - cookies == nil выключает режим cookie, токены отдаются только в теле ответа.
+func New(client authv1.AuthServiceClient, cookies *CookieConfig) *Handler {
+	return &Handler{client: client, cookies: cookies}
 }
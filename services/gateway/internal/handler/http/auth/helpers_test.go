@@ -3,9 +3,12 @@ package auth
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 
 	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
@@ -17,6 +20,7 @@ type mockAuthClient struct {
 	refreshTokenFn func(ctx context.Context, in *authv1.RefreshTokenRequest, opts ...grpc.CallOption) (*authv1.RefreshTokenResponse, error)
 	logoutFn       func(ctx context.Context, in *authv1.LogoutRequest, opts ...grpc.CallOption) (*authv1.LogoutResponse, error)
 	verifyEmailFn  func(ctx context.Context, in *authv1.VerifyEmailRequest, opts ...grpc.CallOption) (*authv1.VerifyEmailResponse, error)
+	setUserRolesFn func(ctx context.Context, in *authv1.SetUserRolesRequest, opts ...grpc.CallOption) (*authv1.SetUserRolesResponse, error)
 }
 
 func (m *mockAuthClient) Register(ctx context.Context, in *authv1.RegisterRequest, opts ...grpc.CallOption) (*authv1.RegisterResponse, error) {
@@ -39,8 +43,31 @@ func (m *mockAuthClient) VerifyEmail(ctx context.Context, in *authv1.VerifyEmail
 	return m.verifyEmailFn(ctx, in, opts...)
 }
 
+func (m *mockAuthClient) SetUserRoles(ctx context.Context, in *authv1.SetUserRolesRequest, opts ...grpc.CallOption) (*authv1.SetUserRolesResponse, error) {
+	return m.setUserRolesFn(ctx, in, opts...)
+}
+
 func newTestHandler(client *mockAuthClient) *Handler {
-	return New(client)
+	return New(client, nil)
+}
+
+func newCookieTestHandler(client *mockAuthClient) *Handler {
+	return New(client, &CookieConfig{Secure: true, SameSite: http.SameSiteLaxMode, MaxAge: time.Hour})
+}
+
+// testAccessToken - JWT с нужным sub, подпись handler не проверяет.
+func testAccessToken(userID uuid.UUID) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(`{"sub":"`+userID.String()+`"}`)) + ".sig"
+}
+
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	return cookies
 }
 
 func makeRequest(path, body string) (*httptest.ResponseRecorder, *http.Request) {
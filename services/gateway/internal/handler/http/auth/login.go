@@ -5,6 +5,7 @@ import (
 
 	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
 )
 
@@ -24,6 +25,14 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.cookies != nil {
+		if err := h.setAuthCookies(w, resp.AccessToken, resp.RefreshToken); err != nil {
+			logger.Ctx(r.Context()).Err(err).Msg("set auth cookies")
+			utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+	}
+
 	utils.WriteJSON(w, http.StatusOK, gatewayv1.TokenPairResponse{
 		AccessToken:  &resp.AccessToken,
 		RefreshToken: &resp.RefreshToken,
@@ -23,5 +23,9 @@ func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.cookies != nil {
+		h.clearAuthCookies(w)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -5,27 +5,55 @@ import (
 
 	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
 )
 
 func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	var req gatewayv1.RefreshTokenRequest
-	if err := utils.DecodeBody(r, &req); err != nil {
+	req, ok := h.refreshRequest(r)
+	if !ok {
 		utils.WriteError(w, r, http.StatusBadRequest, "invalid request body")
 		return
 	}
 
-	resp, err := h.client.RefreshToken(r.Context(), &authv1.RefreshTokenRequest{
-		UserId:       req.UserId.String(),
-		RefreshToken: req.RefreshToken,
-	})
+	resp, err := h.client.RefreshToken(r.Context(), req)
 	if err != nil {
 		utils.HandleGRPCError(w, r, err)
 		return
 	}
 
+	if h.cookies != nil {
+		if err := h.setAuthCookies(w, resp.AccessToken, resp.RefreshToken); err != nil {
+			logger.Ctx(r.Context()).Err(err).Msg("set auth cookies")
+			utils.WriteError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+	}
+
 	utils.WriteJSON(w, http.StatusOK, gatewayv1.TokenPairResponse{
 		AccessToken:  &resp.AccessToken,
 		RefreshToken: &resp.RefreshToken,
 	})
 }
+
+// refreshRequest - в режиме cookie запрос без тела берёт user_id и refresh token из cookie.
+func (h *Handler) refreshRequest(r *http.Request) (*authv1.RefreshTokenRequest, bool) {
+	if h.cookies != nil && r.ContentLength == 0 {
+		userID, token, ok := refreshFromCookie(r)
+		if !ok {
+			return nil, false
+		}
+
+		return &authv1.RefreshTokenRequest{UserId: userID.String(), RefreshToken: token}, true
+	}
+
+	var req gatewayv1.RefreshTokenRequest
+	if err := utils.DecodeBody(r, &req); err != nil {
+		return nil, false
+	}
+
+	return &authv1.RefreshTokenRequest{
+		UserId:       req.UserId.String(),
+		RefreshToken: req.RefreshToken,
+	}, true
+}
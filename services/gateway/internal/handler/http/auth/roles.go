@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"net/http"
+
+	authv1 "github.com/SonOfSteveJobs/habr/pkg/gen/auth/v1"
+	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/middleware"
+)
+
+func (h *Handler) SetUserRoles(w http.ResponseWriter, r *http.Request, id gatewayv1.UserID) {
+	if _, ok := middleware.UserIDFromContext(r.Context()); !ok {
+		utils.WriteError(w, r, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	if !middleware.HasRole(r.Context(), middleware.RoleAdmin) {
+		utils.WriteError(w, r, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	var req gatewayv1.SetUserRolesRequest
+	if err := utils.DecodeBody(r, &req); err != nil {
+		utils.WriteError(w, r, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	roles := make([]string, len(req.Roles))
+	for i, role := range req.Roles {
+		roles[i] = string(role)
+	}
+
+	_, err := h.client.SetUserRoles(r.Context(), &authv1.SetUserRolesRequest{
+		UserId: id.String(),
+		Roles:  roles,
+	})
+	if err != nil {
+		utils.HandleGRPCError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
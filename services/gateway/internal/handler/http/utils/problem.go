@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+	problemTypePrefix  = "/problems/"
+)
+
+// Коды ошибок, которые выставляет сам gateway. Коды сервисов - grpcerr.Reason*.
+const (
+	CodeValidationFailed    = grpcerr.ReasonValidationFailed
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeUnauthenticated     = "UNAUTHENTICATED"
+	CodeForbidden           = "FORBIDDEN"
+	CodeCSRFTokenInvalid    = "CSRF_TOKEN_INVALID"
+	CodeNotFound            = "NOT_FOUND"
+	CodeConflict            = "CONFLICT"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeUnsupportedVersion  = "UNSUPPORTED_API_VERSION"
+	CodeUpstreamUnavailable = "UPSTREAM_UNAVAILABLE"
+	CodeUpstreamTimeout     = "UPSTREAM_TIMEOUT"
+	CodeInternal            = grpcerr.ReasonInternal
+)
+
+// Problem - тело ошибки по RFC 7807. Code - стабильный машиночитаемый код, клиенты разбирают его, а не Detail.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError - ошибка конкретного поля. Field - путь через точку для тела, имя параметра для остального.
+// In пуст, если место поля неизвестно.
+type FieldError struct {
+	Field  string `json:"field"`
+	In     string `json:"in,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// WriteProblem дописывает незаполненные поля: code по статусу, type по code, title по статусу, instance - путь запроса.
+func WriteProblem(w http.ResponseWriter, r *http.Request, p Problem) {
+	if p.Code == "" {
+		p.Code = statusCode(p.Status)
+	}
+	if p.Type == "" {
+		p.Type = ProblemType(p.Code)
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+
+	log := logger.Ctx(r.Context())
+	if p.Status >= http.StatusInternalServerError {
+		log.Error().Int("status", p.Status).Str("code", p.Code).Str("error", p.Detail).Msg("request error")
+	} else {
+		log.Warn().Int("status", p.Status).Str("code", p.Code).Str("error", p.Detail).Msg("request error")
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p) //nolint:gosec
+}
+
+// ProblemType - URI типа ошибки, относительный к API: EMAIL_TAKEN -> /problems/email-taken.
+func ProblemType(code string) string {
+	return problemTypePrefix + strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+func statusCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUpstreamUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeUpstreamTimeout
+	default:
+		return CodeInternal
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
+)
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) Problem {
+	t.Helper()
+
+	if ct := w.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+
+	var p Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	return p
+}
+
+func TestHandleGRPCError_Reason(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", nil)
+
+	HandleGRPCError(w, r, grpcerr.New(codes.AlreadyExists, grpcerr.ReasonEmailTaken, "user with this email already exists"))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	p := decodeProblem(t, w)
+	if p.Code != grpcerr.ReasonEmailTaken {
+		t.Errorf("code = %q, want %q", p.Code, grpcerr.ReasonEmailTaken)
+	}
+	if p.Type != "/problems/email-taken" {
+		t.Errorf("type = %q, want %q", p.Type, "/problems/email-taken")
+	}
+	if p.Status != http.StatusConflict || p.Title != "Conflict" || p.Instance != "/api/v1/auth/register" {
+		t.Errorf("problem = %+v", p)
+	}
+}
+
+func TestHandleGRPCError_FieldViolations(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/articles?cursor=abc", nil)
+
+	HandleGRPCError(w, r, grpcerr.Field(grpcerr.ReasonInvalidCursor, "cursor", "invalid cursor"))
+
+	p := decodeProblem(t, w)
+	if w.Code != http.StatusBadRequest || p.Code != grpcerr.ReasonInvalidCursor {
+		t.Errorf("status = %d, code = %q", w.Code, p.Code)
+	}
+	if len(p.Errors) != 1 || p.Errors[0].Field != "cursor" || p.Errors[0].Reason != "invalid cursor" {
+		t.Errorf("errors = %+v", p.Errors)
+	}
+	if p.Errors[0].In != "query" {
+		t.Errorf("in = %q, want query", p.Errors[0].In)
+	}
+}
+
+func TestHandleGRPCError_FieldLocation(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   string
+	}{
+		{name: "query", method: http.MethodGet, target: "/api/v1/articles?cursor=abc", want: "query"},
+		{name: "body", method: http.MethodPost, target: "/api/v1/articles", want: "body"},
+		{name: "unknown", method: http.MethodGet, target: "/api/v1/articles", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.target, nil)
+
+			HandleGRPCError(w, r, grpcerr.Field(grpcerr.ReasonInvalidCursor, "cursor", "invalid cursor"))
+
+			p := decodeProblem(t, w)
+			if len(p.Errors) != 1 || p.Errors[0].In != tt.want {
+				t.Errorf("errors = %+v, want in %q", p.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGRPCError_NoDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+
+	HandleGRPCError(w, r, status.Error(codes.Unavailable, "dial tcp 10.0.0.5:50051: connection refused"))
+
+	p := decodeProblem(t, w)
+	if w.Code != http.StatusServiceUnavailable || p.Code != CodeUpstreamUnavailable {
+		t.Errorf("status = %d, code = %q", w.Code, p.Code)
+	}
+	if p.Detail != "service unavailable" {
+		t.Errorf("detail = %q, want generic", p.Detail)
+	}
+}
+
+func TestWriteError_DefaultCode(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/articles", nil)
+
+	WriteError(w, r, http.StatusBadRequest, "invalid request body")
+
+	p := decodeProblem(t, w)
+	if p.Code != CodeBadRequest || p.Detail != "invalid request body" || p.Type != "/problems/bad-request" {
+		t.Errorf("problem = %+v", p)
+	}
+}
@@ -1,14 +1,18 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 
-	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
+	"github.com/SonOfSteveJobs/habr/pkg/grpcerr"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 )
 
@@ -18,16 +22,9 @@ func WriteJSON(w http.ResponseWriter, code int, v any) {
 	_ = json.NewEncoder(w).Encode(v) //nolint:gosec
 }
 
+// WriteError - ошибка без причины от сервиса, code в ответе выводится из HTTP статуса.
 func WriteError(w http.ResponseWriter, r *http.Request, code int, msg string) {
-	log := logger.Ctx(r.Context())
-
-	if code >= http.StatusInternalServerError {
-		log.Error().Int("status", code).Str("error", msg).Msg("request error")
-	} else if code >= http.StatusBadRequest {
-		log.Warn().Int("status", code).Str("error", msg).Msg("request error")
-	}
-
-	WriteJSON(w, code, gatewayv1.ErrorResponse{Error: &msg})
+	WriteProblem(w, r, Problem{Status: code, Detail: msg})
 }
 
 func DecodeBody(r *http.Request, v any) error {
@@ -38,6 +35,86 @@ func DecodeBody(r *http.Request, v any) error {
 	return json.NewDecoder(r.Body).Decode(v)
 }
 
+type clientIPKey struct{}
+
+// WithClientIP - адрес клиента, который определил middleware.RealIP.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP - адрес клиента из middleware.RealIP, без него - RemoteAddr. Заголовкам X-Forwarded-For и X-Real-IP
+// здесь не верим: их может прислать любой клиент и подобрать себе ключ лимита или дедупликации просмотров.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok && ip != "" {
+		return ip
+	}
+
+	return remoteHost(r)
+}
+
+// ResolveClientIP - заголовки прокси учитываются, только если запрос пришёл от trusted прокси. X-Forwarded-For
+// разбирается справа налево: каждый trusted прокси дописывает адрес, от которого получил запрос, поэтому клиент -
+// первый адрес не из trusted. Всё левее него прислал сам клиент. X-Real-IP - если X-Forwarded-For нет.
+func ResolveClientIP(r *http.Request, trusted []netip.Prefix) string {
+	remote := remoteHost(r)
+	if !isTrusted(remote, trusted) {
+		return remote
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+
+	if len(hops) == 0 {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return ip.Unmap().String()
+		}
+		return remote
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// мусор в цепочке: дальше него адресам верить нельзя, клиент - последний разобранный
+			break
+		}
+
+		client = ip.Unmap().String()
+		if !isTrusted(client, trusted) {
+			break
+		}
+	}
+
+	return client
+}
+
+func isTrusted(ip string, trusted []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, p := range trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
 func grpcToHTTP(code codes.Code) int {
 	switch code {
 	case codes.InvalidArgument:
@@ -50,19 +127,68 @@ func grpcToHTTP(code codes.Code) int {
 		return http.StatusNotFound
 	case codes.AlreadyExists:
 		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+// HandleGRPCError отдаёт ошибку сервиса как problem+json: code - причина из google.rpc.ErrorInfo,
+// errors - нарушения полей из google.rpc.BadRequest. Без ErrorInfo code выводится из HTTP статуса.
 func HandleGRPCError(w http.ResponseWriter, r *http.Request, err error) {
-	log := logger.Logger()
-	st, ok := status.FromError(err)
+	log := logger.Ctx(r.Context())
+
+	p := GRPCProblem(err)
+	for i := range p.Errors {
+		p.Errors[i].In = fieldLocation(r, p.Errors[i].Field)
+	}
+	WriteProblem(w, r, p)
+	log.Err(err).Msg("handleGRPC Error")
+}
+
+// GRPCProblem - problem по ошибке upstream без записи ответа. Code заполнен всегда: GraphQL отдаёт его в extensions.
+// Message сервиса попадает в Detail только вместе с причиной из ErrorInfo: без неё это может быть внутренний текст.
+// In у нарушений полей не заполняется - место поля знает только HTTP запрос.
+func GRPCProblem(err error) Problem {
+	d, ok := grpcerr.FromError(err)
 	if !ok {
-		WriteError(w, r, http.StatusInternalServerError, "internal error")
-		return
+		return Problem{Status: http.StatusInternalServerError, Detail: "internal error", Code: CodeInternal}
 	}
 
-	WriteError(w, r, grpcToHTTP(st.Code()), st.Message())
-	log.Err(err).Msg("handleGRPC Error")
+	p := Problem{
+		Status: grpcToHTTP(d.Code),
+		Detail: d.Message,
+		Code:   d.Reason,
+	}
+	if p.Code == "" {
+		p.Code = statusCode(p.Status)
+		p.Detail = strings.ToLower(http.StatusText(p.Status))
+	}
+	for _, f := range d.Fields {
+		p.Errors = append(p.Errors, FieldError{Field: f.Field, Reason: f.Description})
+	}
+
+	return p
+}
+
+// fieldLocation - где в запросе передано поле из нарушения сервиса, пусто если его там нет.
+func fieldLocation(r *http.Request, field string) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.URLParam(field) != "" {
+		return "path"
+	}
+	if r.URL.Query().Has(field) {
+		return "query"
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		return "body"
+	default:
+		return ""
+	}
 }
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"net/netip"
 	"testing"
 
 	"google.golang.org/grpc/codes"
@@ -18,9 +20,11 @@ func TestGrpcToHTTP(t *testing.T) {
 		{"PermissionDenied", codes.PermissionDenied, http.StatusForbidden},
 		{"NotFound", codes.NotFound, http.StatusNotFound},
 		{"AlreadyExists", codes.AlreadyExists, http.StatusConflict},
+		{"ResourceExhausted", codes.ResourceExhausted, http.StatusTooManyRequests},
 		{"Internal", codes.Internal, http.StatusInternalServerError},
 		{"Unknown", codes.Unknown, http.StatusInternalServerError},
-		{"Unavailable", codes.Unavailable, http.StatusInternalServerError},
+		{"Unavailable", codes.Unavailable, http.StatusServiceUnavailable},
+		{"DeadlineExceeded", codes.DeadlineExceeded, http.StatusGatewayTimeout},
 	}
 
 	for _, tt := range tests {
@@ -32,3 +36,57 @@ func TestGrpcToHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveClientIP(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name     string
+		remote   string
+		xff      []string
+		realIP   string
+		expected string
+	}{
+		{"direct client spoofs xff", "198.51.100.1:5000", []string{"203.0.113.7"}, "", "198.51.100.1"},
+		{"direct client spoofs x-real-ip", "198.51.100.1:5000", nil, "203.0.113.7", "198.51.100.1"},
+		{"via trusted proxy", "10.0.0.2:5000", []string{"203.0.113.7"}, "", "203.0.113.7"},
+		{"spoofed prefix via proxy", "10.0.0.2:5000", []string{"1.1.1.1, 203.0.113.7"}, "", "203.0.113.7"},
+		{"two trusted hops", "10.0.0.2:5000", []string{"203.0.113.7, 10.0.0.3"}, "", "203.0.113.7"},
+		{"several headers", "10.0.0.2:5000", []string{"1.1.1.1", "203.0.113.7"}, "", "203.0.113.7"},
+		{"garbage before trusted hop", "10.0.0.2:5000", []string{"not-an-ip, 10.0.0.3"}, "", "10.0.0.3"},
+		{"x-real-ip from trusted proxy", "10.0.0.2:5000", nil, "203.0.113.7", "203.0.113.7"},
+		{"trusted proxy without headers", "10.0.0.2:5000", nil, "", "10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			if got := ResolveClientIP(r, trusted); got != tt.expected {
+				t.Errorf("ResolveClientIP() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClientIP_IgnoresHeadersWithoutRealIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "198.51.100.1:5000"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7")
+
+	if got := ClientIP(r); got != "198.51.100.1" {
+		t.Errorf("ClientIP() = %q, want remote address", got)
+	}
+
+	r = r.WithContext(WithClientIP(r.Context(), "203.0.113.9"))
+	if got := ClientIP(r); got != "203.0.113.9" {
+		t.Errorf("ClientIP() = %q, want address from context", got)
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+)
+
+type accessLogKey struct{}
+
+// accessEntry заполняется ниже по цепочке: пользователя Auth узнаёт уже после AccessLog.
+type accessEntry struct {
+	userID uuid.UUID
+}
+
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *accessLogWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessLogWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+func (w *accessLogWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// AccessLog пишет одну запись на запрос. Ставится через r.Use после requestid, чтобы запись содержала request_id.
+func AccessLog() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &accessEntry{}
+			lw := &accessLogWriter{ResponseWriter: w}
+
+			next.ServeHTTP(lw, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry)))
+
+			status := lw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+
+			log := logger.Ctx(r.Context())
+			event := log.Info()
+			switch {
+			case status >= http.StatusInternalServerError:
+				event = log.Error()
+			case status >= http.StatusBadRequest:
+				event = log.Warn()
+			}
+
+			event = event.
+				Str("method", r.Method).
+				Str("route", route).
+				Int("status", status).
+				Int("bytes", lw.bytes).
+				Dur("latency", time.Since(start))
+			if entry.userID != uuid.Nil {
+				event = event.Str("user_id", entry.userID.String())
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				event = event.Str("trace_id", sc.TraceID().String())
+			}
+			event.Msg("http request")
+		})
+	}
+}
+
+func recordAccessUser(ctx context.Context, userID uuid.UUID) {
+	if entry, ok := ctx.Value(accessLogKey{}).(*accessEntry); ok {
+		entry.userID = userID
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestAccessLogWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	lw := &accessLogWriter{ResponseWriter: w}
+
+	if _, err := lw.Write([]byte("hello")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := lw.Write([]byte(" world")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	if lw.status != http.StatusOK {
+		t.Errorf("status = %d, want %d", lw.status, http.StatusOK)
+	}
+	if lw.bytes != len("hello world") {
+		t.Errorf("bytes = %d, want %d", lw.bytes, len("hello world"))
+	}
+}
+
+func TestAccessLog_PassesResponse(t *testing.T) {
+	handler := AccessLog()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTeapot)
+	}
+}
+
+func TestRecordAccessUser(t *testing.T) {
+	entry := &accessEntry{}
+	ctx := context.WithValue(context.Background(), accessLogKey{}, entry)
+	userID := uuid.Must(uuid.NewV7())
+
+	_ = withUser(ctx, userID, nil)
+
+	if entry.userID != userID {
+		t.Errorf("userID = %v, want %v", entry.userID, userID)
+	}
+}
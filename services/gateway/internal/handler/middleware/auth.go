@@ -7,6 +7,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,38 +15,74 @@ import (
 
 	gatewayv1 "github.com/SonOfSteveJobs/habr/pkg/gen/gateway/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
 )
 
 type contextKey string
 
-const userIDKey contextKey = "user_id"
+const (
+	userIDKey contextKey = "user_id"
+	rolesKey  contextKey = "roles"
+)
+
+// Роли из claim roles access токена. Роль user есть у всех, отдельно не проверяется.
+const (
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
 
 const jwtPartsLen = 3
 
+// Cookie режима, в котором токены хранит браузер, а не JS приложения.
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFTokenCookie    = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
 type jwtPayload struct {
-	UserID string `json:"sub"`
-	Exp    int64  `json:"exp"`
+	UserID string   `json:"sub"`
+	Roles  []string `json:"roles"`
+	Exp    int64    `json:"exp"`
+}
+
+type authOptions struct {
+	cookie bool
 }
 
-func Auth(secret string) func(http.Handler) http.Handler {
+type AuthOption func(*authOptions)
+
+// WithCookieAuth - без заголовка Authorization access token берётся из cookie. Мутирующие запросы с cookie защищает CSRF.
+func WithCookieAuth() AuthOption {
+	return func(o *authOptions) { o.cookie = true }
+}
+
+func Auth(secret string, opts ...AuthOption) func(http.Handler) http.Handler {
 	secretBytes := []byte(secret)
 
+	var o authOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if r.Context().Value(gatewayv1.BearerScopes) == nil {
-				next.ServeHTTP(w, r)
-				return
-			}
+				// публичный маршрут: токен не обязателен, но валидный токен даёт пользователя в контексте
+				if token, errMsg := o.token(r); errMsg == "" {
+					if userID, roles, ok := optionalUser(token, secretBytes); ok {
+						r = r.WithContext(withUser(r.Context(), userID, roles))
+					}
+				}
 
-			header := r.Header.Get("Authorization")
-			if header == "" {
-				writeAuthError(w, r, "missing authorization header")
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			token, ok := strings.CutPrefix(header, "Bearer ")
-			if !ok {
-				writeAuthError(w, r, "invalid authorization header")
+			token, errMsg := o.token(r)
+			if errMsg != "" {
+				writeAuthError(w, r, errMsg)
 				return
 			}
 			log := logger.Ctx(r.Context())
@@ -63,8 +100,7 @@ func Auth(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
-			ctx := context.WithValue(r.Context(), userIDKey, userID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, r.WithContext(withUser(r.Context(), userID, payload.Roles)))
 		})
 	}
 }
@@ -74,6 +110,54 @@ func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	return id, ok
 }
 
+// HasRole проверяет роль пользователя из токена. Администратору доступно всё, что доступно модератору.
+func HasRole(ctx context.Context, role string) bool {
+	roles, _ := ctx.Value(rolesKey).([]string)
+
+	return slices.Contains(roles, role) || slices.Contains(roles, RoleAdmin)
+}
+
+func withUser(ctx context.Context, userID uuid.UUID, roles []string) context.Context {
+	recordAccessUser(ctx, userID)
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return context.WithValue(ctx, rolesKey, roles)
+}
+
+// token достаёт access token из заголовка, а в режиме cookie - из cookie. Вторым значением - ошибка для ответа 401.
+func (o authOptions) token(r *http.Request) (string, string) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		if o.cookie {
+			if c, err := r.Cookie(AccessTokenCookie); err == nil && c.Value != "" {
+				return c.Value, ""
+			}
+		}
+
+		return "", "missing authorization header"
+	}
+
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", "invalid authorization header"
+	}
+
+	return token, ""
+}
+
+func optionalUser(token string, secret []byte) (uuid.UUID, []string, bool) {
+	payload, err := validateJWT(token, secret)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+
+	userID, err := uuid.Parse(payload.UserID)
+	if err != nil {
+		return uuid.Nil, nil, false
+	}
+
+	return userID, payload.Roles, true
+}
+
 func writeAuthError(w http.ResponseWriter, r *http.Request, msg string) {
 	log := logger.Ctx(r.Context())
 	log.Warn().
@@ -82,9 +166,11 @@ func writeAuthError(w http.ResponseWriter, r *http.Request, msg string) {
 		Str("error", msg).
 		Msg("auth failed")
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg}) //nolint:gosec
+	utils.WriteProblem(w, r, utils.Problem{
+		Status: http.StatusUnauthorized,
+		Detail: msg,
+		Code:   utils.CodeUnauthenticated,
+	})
 }
 
 func validateJWT(token string, secret []byte) (*jwtPayload, error) {
@@ -109,7 +195,7 @@ func validateJWT(token string, secret []byte) (*jwtPayload, error) {
 		return nil, errInvalidToken
 	}
 
-	// json вида {"user_id":"uuid","exp":1234567890}
+	// json вида {"sub":"uuid","roles":["user"],"exp":1234567890}
 	var payload jwtPayload
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return nil, errInvalidToken
@@ -122,7 +208,33 @@ func validateJWT(token string, secret []byte) (*jwtPayload, error) {
 	return &payload, nil
 }
 
+// TokenSubject достаёт user_id из access token без проверки подписи.
+// Только для токенов, которые gateway сам только что получил от Auth Service.
+func TokenSubject(token string) (uuid.UUID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != jwtPartsLen {
+		return uuid.Nil, errInvalidToken
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return uuid.Nil, errInvalidToken
+	}
+
+	var payload jwtPayload
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return uuid.Nil, errInvalidToken
+	}
+
+	return uuid.Parse(payload.UserID)
+}
+
 // WithUserID - чисто для тестов
 func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
 	return context.WithValue(ctx, userIDKey, userID)
 }
+
+// WithUser - чисто для тестов, пользователь с ролями
+func WithUser(ctx context.Context, userID uuid.UUID, roles ...string) context.Context {
+	return withUser(ctx, userID, roles)
+}
@@ -157,3 +157,165 @@ func TestAuth_ExpiredToken(t *testing.T) {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
 	}
 }
+
+func TestAuth_NoScopesOptionalToken(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	token := buildJWT(t, jwtPayload{
+		UserID: userID.String(),
+		Exp:    time.Now().Add(10 * time.Minute).Unix(),
+	}, testSecret)
+
+	var gotUserID uuid.UUID
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID, _ = UserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	Auth(testSecret)(next).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotUserID != userID {
+		t.Errorf("userID = %s, want %s", gotUserID, userID)
+	}
+}
+
+func TestAuth_NoScopesInvalidTokenIgnored(t *testing.T) {
+	token := buildJWT(t, jwtPayload{
+		UserID: uuid.Must(uuid.NewV7()).String(),
+		Exp:    time.Now().Add(10 * time.Minute).Unix(),
+	}, "wrong-secret")
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := UserIDFromContext(r.Context()); ok {
+			t.Error("user ID in context, want anonymous request")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	Auth(testSecret)(next).ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestAuth_RolesInContext(t *testing.T) {
+	token := buildJWT(t, jwtPayload{
+		UserID: uuid.Must(uuid.NewV7()).String(),
+		Roles:  []string{"user", RoleModerator},
+		Exp:    time.Now().Add(10 * time.Minute).Unix(),
+	}, testSecret)
+
+	var isModerator, isAdmin bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		isModerator = HasRole(r.Context(), RoleModerator)
+		isAdmin = HasRole(r.Context(), RoleAdmin)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r = withBearerScopes(r)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	Auth(testSecret)(next).ServeHTTP(httptest.NewRecorder(), r)
+
+	if !isModerator {
+		t.Error("HasRole(moderator) = false, want true")
+	}
+	if isAdmin {
+		t.Error("HasRole(admin) = true, want false")
+	}
+}
+
+func TestHasRole_AdminImpliesModerator(t *testing.T) {
+	ctx := WithUser(context.Background(), uuid.Must(uuid.NewV7()), RoleAdmin)
+
+	if !HasRole(ctx, RoleModerator) {
+		t.Error("HasRole(moderator) = false for admin, want true")
+	}
+}
+
+func TestHasRole_NoRoles(t *testing.T) {
+	ctx := WithUserID(context.Background(), uuid.Must(uuid.NewV7()))
+
+	if HasRole(ctx, RoleModerator) {
+		t.Error("HasRole(moderator) = true without roles, want false")
+	}
+}
+
+func TestAuth_CookieToken(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	token := buildJWT(t, jwtPayload{
+		UserID: userID.String(),
+		Exp:    time.Now().Add(10 * time.Minute).Unix(),
+	}, testSecret)
+
+	var gotUserID uuid.UUID
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserID, _ = UserIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Auth(testSecret, WithCookieAuth())(next)
+
+	r := withBearerScopes(httptest.NewRequest(http.MethodGet, "/", nil))
+	r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotUserID != userID {
+		t.Errorf("userID = %v, want %v", gotUserID, userID)
+	}
+}
+
+func TestAuth_CookieIgnoredWithoutOption(t *testing.T) {
+	token := buildJWT(t, jwtPayload{
+		UserID: uuid.Must(uuid.NewV7()).String(),
+		Exp:    time.Now().Add(10 * time.Minute).Unix(),
+	}, testSecret)
+
+	handler := Auth(testSecret)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	r := withBearerScopes(httptest.NewRequest(http.MethodGet, "/", nil))
+	r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: token})
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestTokenSubject(t *testing.T) {
+	userID := uuid.Must(uuid.NewV7())
+	token := buildJWT(t, jwtPayload{UserID: userID.String()}, "other-secret")
+
+	got, err := TokenSubject(token)
+	if err != nil {
+		t.Fatalf("TokenSubject: %v", err)
+	}
+	if got != userID {
+		t.Errorf("subject = %v, want %v", got, userID)
+	}
+
+	if _, err := TokenSubject("not-a-jwt"); err == nil {
+		t.Error("expected error for malformed token")
+	}
+}
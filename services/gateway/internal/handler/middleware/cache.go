@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/httpcache"
+)
+
+// maxCachedBody - ответы больше не хранятся в LRU, чтобы пара длинных списков не вытеснила всё остальное.
+const maxCachedBody = 1 << 20
+
+// CachePolicy - TTL 0 значит ответ не хранится, а клиент обязан ревалидировать его по ETag.
+type CachePolicy struct {
+	TTL time.Duration
+}
+
+// CachePolicies - кэшируемые маршруты, ключ "GET /pattern" как в chi.
+type CachePolicies map[string]CachePolicy
+
+// Cache отдаёт анонимные GET с ETag и Cache-Control, отвечает 304 на совпавший If-None-Match
+// и, если передан store, хранит ответы в памяти на TTL маршрута. Запросы с токеном или auth cookie идут мимо кэша.
+// Должен стоять после Auth.
+func Cache(store *httpcache.LRU, policies CachePolicies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, policy, ok := policies.forRequest(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if isPersonalised(r) {
+				metrics.RecordHTTPCache(r.Context(), route, "bypass")
+				w.Header().Set("Cache-Control", "private, no-cache")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// в режиме cookie пользователь приходит в Cookie - общие кэши должны различать и его
+			w.Header().Add("Vary", "Authorization, Cookie")
+
+			stored := store != nil && policy.TTL > 0
+			key := r.URL.Path + "?" + r.URL.Query().Encode()
+
+			if stored {
+				if entry, ok := store.Get(key); ok {
+					metrics.RecordHTTPCache(r.Context(), route, "hit")
+					writeCacheEntry(w, r, route, entry, policy)
+					return
+				}
+			}
+
+			buf := &bufferedResponse{header: make(http.Header)}
+			next.ServeHTTP(buf, r)
+			// обработчик мог ничего не записать - это 200 с пустым телом
+			buf.WriteHeader(http.StatusOK)
+
+			if buf.status != http.StatusOK {
+				buf.writeTo(w)
+				return
+			}
+
+			now := time.Now()
+			entry := &httpcache.Entry{
+				Header:    buf.header,
+				Body:      buf.body.Bytes(),
+				ETag:      httpcache.ETag(buf.body.Bytes()),
+				StoredAt:  now,
+				ExpiresAt: now.Add(policy.TTL),
+			}
+
+			if stored && len(entry.Body) <= maxCachedBody {
+				store.Add(key, entry)
+			}
+
+			metrics.RecordHTTPCache(r.Context(), route, "miss")
+			writeCacheEntry(w, r, route, entry, policy)
+		})
+	}
+}
+
+func (p CachePolicies) forRequest(r *http.Request) (string, CachePolicy, bool) {
+	if r.Method != http.MethodGet {
+		return "", CachePolicy{}, false
+	}
+
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "", CachePolicy{}, false
+	}
+
+	route := r.Method + " " + rctx.RoutePattern()
+	policy, ok := p[route]
+
+	return route, policy, ok
+}
+
+// isPersonalised - ответ может зависеть от пользователя, даже если токен или cookie оказались невалидными.
+func isPersonalised(r *http.Request) bool {
+	if _, ok := UserIDFromContext(r.Context()); ok {
+		return true
+	}
+
+	return r.Header.Get("Authorization") != "" || hasAuthCookie(r)
+}
+
+func writeCacheEntry(w http.ResponseWriter, r *http.Request, route string, entry *httpcache.Entry, policy CachePolicy) {
+	h := w.Header()
+	maps.Copy(h, entry.Header)
+	h.Set("ETag", entry.ETag)
+
+	if policy.TTL > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(policy.TTL.Seconds())))
+		if age := time.Since(entry.StoredAt); age >= time.Second {
+			h.Set("Age", strconv.Itoa(int(age.Seconds())))
+		}
+	} else {
+		h.Set("Cache-Control", "public, no-cache")
+	}
+
+	if httpcache.NoneMatch(r.Header.Get("If-None-Match"), entry.ETag) {
+		metrics.RecordHTTPCache(r.Context(), route, "not_modified")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(entry.Body) //nolint:gosec
+}
+
+// bufferedResponse копит ответ обработчика до отправки: для ETag и для проверки ответа по спецификации.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) writeTo(w http.ResponseWriter) {
+	maps.Copy(w.Header(), b.header)
+
+	w.WriteHeader(b.status)
+	_, _ = w.Write(b.body.Bytes()) //nolint:gosec
+}
@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/httpcache"
+)
+
+var testCachePolicies = CachePolicies{
+	"GET /api/v1/articles":      {TTL: 5 * time.Second},
+	"GET /api/v1/articles/{id}": {TTL: 0},
+}
+
+func countingHandler(calls *int, status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func cacheRequest(path, pattern string) *http.Request {
+	return withRoutePattern(httptest.NewRequest(http.MethodGet, path, nil), pattern)
+}
+
+func TestCache_StoresAnonymousResponse(t *testing.T) {
+	calls := 0
+	handler := Cache(httpcache.NewLRU(10), testCachePolicies)(countingHandler(&calls, http.StatusOK, `{"articles":[]}`))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, cacheRequest("/api/v1/articles?limit=10", "/api/v1/articles"))
+
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, cacheRequest("/api/v1/articles?limit=10", "/api/v1/articles"))
+
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+	if second.Body.String() != `{"articles":[]}` {
+		t.Errorf("body = %q", second.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := second.Header().Get("Cache-Control"); got != "public, max-age=5" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+	if got := first.Header().Get("Vary"); got != "Authorization, Cookie" {
+		t.Errorf("Vary = %q", got)
+	}
+	if first.Header().Get("ETag") == "" || first.Header().Get("ETag") != second.Header().Get("ETag") {
+		t.Errorf("ETag mismatch: %q vs %q", first.Header().Get("ETag"), second.Header().Get("ETag"))
+	}
+
+	// другой query - другой ключ
+	handler.ServeHTTP(httptest.NewRecorder(), cacheRequest("/api/v1/articles?limit=20", "/api/v1/articles"))
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestCache_NotModified(t *testing.T) {
+	calls := 0
+	handler := Cache(nil, testCachePolicies)(countingHandler(&calls, http.StatusOK, `{"id":"1"}`))
+
+	first := httptest.NewRecorder()
+	handler.ServeHTTP(first, cacheRequest("/api/v1/articles/1", "/api/v1/articles/{id}"))
+	etag := first.Header().Get("ETag")
+
+	if got := first.Header().Get("Cache-Control"); got != "public, no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	r := cacheRequest("/api/v1/articles/1", "/api/v1/articles/{id}")
+	r.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	handler.ServeHTTP(second, r)
+
+	if second.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want 304", second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Errorf("304 must have no body, got %q", second.Body.String())
+	}
+	// TTL 0: ответ не хранится, обработчик вызывается каждый раз
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestCache_BypassAuthenticated(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(r *http.Request) *http.Request
+	}{
+		{"user in context", func(r *http.Request) *http.Request {
+			return r.WithContext(WithUserID(r.Context(), uuid.New()))
+		}},
+		{"authorization header", func(r *http.Request) *http.Request {
+			r.Header.Set("Authorization", "Bearer invalid")
+			return r
+		}},
+		{"auth cookie", func(r *http.Request) *http.Request {
+			r.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: "invalid"})
+			return r
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := Cache(httpcache.NewLRU(10), testCachePolicies)(countingHandler(&calls, http.StatusOK, `{}`))
+
+			for range 2 {
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, tt.prepare(cacheRequest("/api/v1/articles", "/api/v1/articles")))
+
+				if got := w.Header().Get("Cache-Control"); got != "private, no-cache" {
+					t.Errorf("Cache-Control = %q", got)
+				}
+				if w.Header().Get("ETag") != "" {
+					t.Error("personalised response must not have ETag")
+				}
+			}
+
+			if calls != 2 {
+				t.Errorf("calls = %d, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestCache_ErrorsNotStored(t *testing.T) {
+	calls := 0
+	handler := Cache(httpcache.NewLRU(10), testCachePolicies)(countingHandler(&calls, http.StatusInternalServerError, `{"error":"internal error"}`))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, cacheRequest("/api/v1/articles", "/api/v1/articles"))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("status = %d, want 500", w.Code)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+}
+
+func TestCache_UnknownRoute(t *testing.T) {
+	calls := 0
+	handler := Cache(httpcache.NewLRU(10), testCachePolicies)(countingHandler(&calls, http.StatusOK, `{}`))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, cacheRequest("/api/v1/moderation/queue", "/api/v1/moderation/queue"))
+
+	if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+		t.Error("uncached route must be passed through untouched")
+	}
+}
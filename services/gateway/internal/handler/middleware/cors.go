@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SonOfSteveJobs/habr/pkg/requestid"
+)
+
+var (
+	corsAllowedMethods = strings.Join([]string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}, ", ")
+	corsAllowedHeaders = strings.Join([]string{
+		"Authorization", "Content-Type", "If-None-Match", CSRFHeader, requestid.Header,
+	}, ", ")
+	corsExposedHeaders = strings.Join([]string{
+		"ETag", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy",
+		requestid.Header, "API-Version", "Deprecation", "Sunset", "Link",
+	}, ", ")
+)
+
+type CORSOptions struct {
+	// AllowedOrigins - точные origin или "*". Пустой список - CORS заголовки не ставятся.
+	AllowedOrigins []string
+	// AllowCredentials нужен режиму cookie: без него браузер не отправит cookie на другой origin.
+	// Действует только для точных origin, "*" никогда не получает credentials.
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// CORS отвечает на preflight сам, до роутинга. Ставится через r.Use, иначе chi ответит на OPTIONS 405.
+func CORS(opts CORSOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			exact := origin != "" && slices.Contains(opts.AllowedOrigins, origin)
+			if origin == "" || !exact && !slices.Contains(opts.AllowedOrigins, "*") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Add("Vary", "Origin")
+			if exact {
+				// с credentials браузер не принимает "*", поэтому точный origin отражается
+				h.Set("Access-Control-Allow-Origin", origin)
+				if opts.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			} else {
+				// отражать любой origin с credentials значит пустить авторизованные запросы с любого сайта
+				h.Set("Access-Control-Allow-Origin", "*")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				h.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				h.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				if opts.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+// CSRF - double-submit: мутирующий запрос с auth cookie должен повторить значение cookie csrf_token в заголовке X-CSRF-Token.
+// Чужой сайт может заставить браузер отправить cookie, но прочитать её и выставить заголовок не может.
+// Запросы с Authorization и без auth cookie не проверяются - браузер сам такой заголовок не подставит.
+func CSRF() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSafeMethod(r.Method) || r.Header.Get("Authorization") != "" || !hasAuthCookie(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cookie, err := r.Cookie(CSRFTokenCookie)
+			header := r.Header.Get(CSRFHeader)
+			if err != nil || cookie.Value == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+				writeForbidden(w, r, "invalid csrf token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func hasAuthCookie(r *http.Request) bool {
+	for _, name := range []string{AccessTokenCookie, RefreshTokenCookie} {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeForbidden(w http.ResponseWriter, r *http.Request, msg string) {
+	log := logger.Ctx(r.Context())
+	log.Warn().
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("error", msg).
+		Msg("csrf check failed")
+
+	utils.WriteProblem(w, r, utils.Problem{
+		Status: http.StatusForbidden,
+		Detail: msg,
+		Code:   utils.CodeCSRFTokenInvalid,
+	})
+}
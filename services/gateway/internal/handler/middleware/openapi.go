@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+const maxValidatedBody = 1 << 20
+
+var formatsOnce sync.Once
+
+// defineFormats - kin-openapi без регистрации пропускает format как аннотацию. Встроенный шаблон uuid
+// принимает только версии 1-5, а идентификаторы у нас UUIDv7, поэтому uuid проверяется разбором.
+func defineFormats() {
+	formatsOnce.Do(func() {
+		openapi3.DefineStringFormatValidator("email", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForEmail))
+		openapi3.DefineStringFormatCallback("uuid", func(s string) error {
+			_, err := uuid.Parse(s)
+			return err
+		})
+	})
+}
+
+type OpenAPIOptions struct {
+	// ValidateResponses - сверять ответы со спецификацией. Ответ клиенту не меняется, расхождение пишется в лог.
+	// Буферизует тело, поэтому только для не-production окружений.
+	ValidateResponses bool
+}
+
+// OpenAPIValidator проверяет запрос по операции из спецификации до хендлера и отвечает 400 problem+json с путями полей.
+// Маршрут уже найден chi, поэтому операция берётся по его шаблону, без отдельного роутера kin-openapi.
+// Аутентификацию проверяет Auth, здесь security схемы пропускаются.
+func OpenAPIValidator(spec *openapi3.T, opts OpenAPIOptions) func(http.Handler) http.Handler {
+	defineFormats()
+
+	filterOpts := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, ok := findRoute(spec, r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reqOpts := *filterOpts
+			// стриминговые тела (импорт ndjson) схемой не описываются и читаются хендлером построчно
+			reqOpts.ExcludeRequestBody = !hasJSONBody(route.Operation)
+			if !reqOpts.ExcludeRequestBody && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxValidatedBody)
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    &reqOpts,
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				utils.WriteProblem(w, r, utils.Problem{
+					Status: http.StatusBadRequest,
+					Detail: "request does not match the API schema",
+					Code:   utils.CodeValidationFailed,
+					Errors: fieldErrors(err),
+				})
+				return
+			}
+
+			if !opts.ValidateResponses || !hasJSONResponses(route.Operation) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rec := &bufferedResponse{header: make(http.Header)}
+			next.ServeHTTP(rec, r)
+			validateResponse(r, input, rec)
+			rec.writeTo(w)
+		})
+	}
+}
+
+func findRoute(spec *openapi3.T, r *http.Request) (*routers.Route, map[string]string, bool) {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.RoutePattern() == "" {
+		return nil, nil, false
+	}
+
+	pathItem := spec.Paths.Value(rctx.RoutePattern())
+	if pathItem == nil {
+		return nil, nil, false
+	}
+
+	op := pathItem.GetOperation(r.Method)
+	if op == nil {
+		return nil, nil, false
+	}
+
+	params := make(map[string]string, len(rctx.URLParams.Keys))
+	for i, key := range rctx.URLParams.Keys {
+		params[key] = rctx.URLParams.Values[i]
+	}
+
+	return &routers.Route{
+		Spec:      spec,
+		Path:      rctx.RoutePattern(),
+		PathItem:  pathItem,
+		Method:    r.Method,
+		Operation: op,
+	}, params, true
+}
+
+func hasJSONBody(op *openapi3.Operation) bool {
+	if op.RequestBody == nil || op.RequestBody.Value == nil {
+		return true
+	}
+
+	return op.RequestBody.Value.Content.Get("application/json") != nil
+}
+
+// hasJSONResponses - экспорт отдаёт поток, буферизовать его ради проверки нельзя.
+func hasJSONResponses(op *openapi3.Operation) bool {
+	ok := op.Responses.Status(http.StatusOK)
+	if ok == nil || ok.Value == nil || len(ok.Value.Content) == 0 {
+		return true
+	}
+
+	return ok.Value.Content.Get("application/json") != nil
+}
+
+func validateResponse(r *http.Request, input *openapi3filter.RequestValidationInput, rec *bufferedResponse) {
+	status := rec.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	rec.status = status
+
+	respInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 status,
+		Header:                 rec.header,
+		Options:                &openapi3filter.Options{MultiError: true},
+	}
+	respInput.SetBodyBytes(rec.body.Bytes())
+
+	if err := openapi3filter.ValidateResponse(r.Context(), respInput); err != nil {
+		log := logger.Ctx(r.Context())
+		log.Error().
+			Err(err).
+			Str("method", r.Method).
+			Str("route", input.Route.Path).
+			Int("status", status).
+			Msg("response does not match openapi spec")
+	}
+}
+
+// fieldErrors раскладывает дерево ошибок kin-openapi в плоский список полей.
+func fieldErrors(err error) []utils.FieldError {
+	var out []utils.FieldError
+	collectFieldErrors(err, "", "", &out)
+	return out
+}
+
+func collectFieldErrors(err error, in, field string, out *[]utils.FieldError) {
+	switch e := err.(type) { //nolint:errorlint // ошибки kin-openapi не оборачиваются, разбираем дерево как есть
+	case openapi3.MultiError:
+		for _, inner := range e {
+			collectFieldErrors(inner, in, field, out)
+		}
+	case *openapi3filter.RequestError:
+		in, field = "body", ""
+		if e.Parameter != nil {
+			in, field = e.Parameter.In, e.Parameter.Name
+		}
+		if e.Err == nil {
+			*out = append(*out, utils.FieldError{Field: field, In: in, Reason: e.Reason})
+			return
+		}
+		collectFieldErrors(e.Err, in, field, out)
+	case *openapi3.SchemaError:
+		*out = append(*out, utils.FieldError{Field: joinField(field, e.JSONPointer()), In: in, Reason: e.Reason})
+	case *openapi3filter.ParseError:
+		*out = append(*out, utils.FieldError{Field: joinField(field, pathStrings(e.Path())), In: in, Reason: e.Error()})
+	default:
+		*out = append(*out, utils.FieldError{Field: field, In: in, Reason: err.Error()})
+	}
+}
+
+func joinField(field string, pointer []string) string {
+	parts := make([]string, 0, len(pointer)+1)
+	if field != "" {
+		parts = append(parts, field)
+	}
+	parts = append(parts, pointer...)
+
+	return strings.Join(parts, ".")
+}
+
+func pathStrings(path []any) []string {
+	out := make([]string, 0, len(path))
+	for _, p := range path {
+		switch v := p.(type) {
+		case string:
+			out = append(out, v)
+		case int:
+			out = append(out, strconv.Itoa(v))
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,211 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+const testSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [email]
+              properties:
+                email:
+                  type: string
+                  format: email
+                age:
+                  type: integer
+                  minimum: 0
+      responses:
+        "201":
+          description: created
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+            format: uuid
+        - name: limit
+          in: query
+          schema:
+            type: integer
+            maximum: 100
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: string
+`
+
+func loadTestSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+
+	spec, err := openapi3.NewLoader().LoadFromData([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("load spec: %v", err)
+	}
+	if err := spec.Validate(context.Background()); err != nil {
+		t.Fatalf("validate spec: %v", err)
+	}
+
+	return spec
+}
+
+func newValidatedRouter(t *testing.T, next http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	validator := OpenAPIValidator(loadTestSpec(t), OpenAPIOptions{ValidateResponses: true})
+
+	r := chi.NewRouter()
+	r.With(validator).Post("/users", next)
+	r.With(validator).Get("/users/{id}", next)
+
+	return r
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) utils.Problem {
+	t.Helper()
+
+	if ct := w.Header().Get("Content-Type"); ct != utils.ProblemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, utils.ProblemContentType)
+	}
+
+	var p utils.Problem
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+
+	return p
+}
+
+func TestOpenAPIValidator_ValidRequest(t *testing.T) {
+	var body string
+	router := newValidatedRouter(t, func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r.Body)
+		body = buf.String()
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(`{"email":"user@example.com","age":3}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, r)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if body != `{"email":"user@example.com","age":3}` {
+		t.Errorf("handler body = %q, body must be readable after validation", body)
+	}
+}
+
+func TestOpenAPIValidator_InvalidBody(t *testing.T) {
+	called := false
+	router := newValidatedRouter(t, func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(`{"email":"not-an-email","age":-1}`))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, r)
+
+	if called {
+		t.Error("handler called for invalid request")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	p := decodeProblem(t, w)
+	if p.Code != utils.CodeValidationFailed {
+		t.Errorf("code = %q, want %q", p.Code, utils.CodeValidationFailed)
+	}
+
+	fields := make(map[string]string)
+	for _, e := range p.Errors {
+		fields[e.Field] = e.In
+	}
+	for _, want := range []string{"email", "age"} {
+		if in, ok := fields[want]; !ok || in != "body" {
+			t.Errorf("errors = %+v, want body field %q", p.Errors, want)
+		}
+	}
+}
+
+func TestOpenAPIValidator_InvalidParams(t *testing.T) {
+	router := newValidatedRouter(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/not-a-uuid?limit=1000", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	p := decodeProblem(t, w)
+	got := make(map[string]string)
+	for _, e := range p.Errors {
+		got[e.Field] = e.In
+	}
+	if got["id"] != "path" || got["limit"] != "query" {
+		t.Errorf("errors = %+v, want path id and query limit", p.Errors)
+	}
+}
+
+func TestOpenAPIValidator_ResponseDriftKeepsResponse(t *testing.T) {
+	router := newValidatedRouter(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"name":"no id"}`))
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/0190a6c2-7d2e-7a3c-9f4b-3e2d1c0b9a88", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != `{"name":"no id"}` {
+		t.Errorf("body = %q, response must not be changed", w.Body.String())
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/ratelimit"
+)
+
+// RateLimitPolicies - политики по маршрутам. Ключ Routes - "METHOD /pattern" как в chi, например "POST /api/v1/auth/login".
+type RateLimitPolicies struct {
+	Default ratelimit.Policy
+	Routes  map[string]ratelimit.Policy
+}
+
+func (p RateLimitPolicies) forRequest(r *http.Request) ratelimit.Policy {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if policy, ok := p.Routes[r.Method+" "+rctx.RoutePattern()]; ok {
+			return policy
+		}
+	}
+
+	return p.Default
+}
+
+// RateLimit ограничивает частоту запросов по пользователю из токена, а для анонимных - по IP клиента.
+// Должен стоять после Auth, чтобы пользователь уже был в контексте. При ошибке лимитера запрос пропускается.
+func RateLimit(limiter ratelimit.Limiter, policies RateLimitPolicies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy := policies.forRequest(r)
+
+			res, err := limiter.Allow(r.Context(), policy.Name+":"+rateLimitSubject(r), policy)
+			if err != nil {
+				log := logger.Ctx(r.Context())
+				log.Err(err).Str("policy", policy.Name).Msg("rate limit check failed")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			metrics.RecordRateLimit(r.Context(), policy.Name, res.Allowed)
+
+			h := w.Header()
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, ceilSeconds(policy.Window)))
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.ResetAfter)))
+
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+				writeRateLimitError(w, r, policy.Name)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimitSubject(r *http.Request) string {
+	if userID, ok := UserIDFromContext(r.Context()); ok {
+		return "user:" + userID.String()
+	}
+
+	return "ip:" + utils.ClientIP(r)
+}
+
+// ceilSeconds округляет вверх: Retry-After: 0 при оставшихся 300ms заставил бы клиента повторить слишком рано.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func writeRateLimitError(w http.ResponseWriter, r *http.Request, policy string) {
+	log := logger.Ctx(r.Context())
+	log.Warn().
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("policy", policy).
+		Msg("rate limit exceeded")
+
+	utils.WriteProblem(w, r, utils.Problem{
+		Status: http.StatusTooManyRequests,
+		Detail: "too many requests",
+		Code:   utils.CodeRateLimited,
+	})
+}
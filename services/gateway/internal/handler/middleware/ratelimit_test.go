@@ -0,0 +1,167 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/ratelimit"
+)
+
+type mockLimiter struct {
+	allowFn func(ctx context.Context, key string, p ratelimit.Policy) (ratelimit.Result, error)
+	keys    []string
+}
+
+func (m *mockLimiter) Allow(ctx context.Context, key string, p ratelimit.Policy) (ratelimit.Result, error) {
+	m.keys = append(m.keys, key)
+	return m.allowFn(ctx, key, p)
+}
+
+var testPolicies = RateLimitPolicies{
+	Default: ratelimit.Policy{Name: "default", Limit: 100, Window: time.Minute},
+	Routes: map[string]ratelimit.Policy{
+		"POST /api/v1/auth/login": {Name: "login", Limit: 5, Window: time.Minute},
+	},
+}
+
+func withRoutePattern(r *http.Request, pattern string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.RoutePatterns = []string{pattern}
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
+
+func okHandler(called *bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*called = true
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRateLimit_Allowed(t *testing.T) {
+	limiter := &mockLimiter{allowFn: func(_ context.Context, _ string, p ratelimit.Policy) (ratelimit.Result, error) {
+		if p.Name != "login" {
+			t.Errorf("policy = %q, want login", p.Name)
+		}
+		return ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4, ResetAfter: 12 * time.Second}, nil
+	}}
+
+	called := false
+	handler := RateLimit(limiter, testPolicies)(okHandler(&called))
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r = withRoutePattern(r, "/api/v1/auth/login")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if !called {
+		t.Fatal("next handler was not called")
+	}
+	if len(limiter.keys) != 1 || limiter.keys[0] != "login:ip:10.0.0.1" {
+		t.Errorf("keys = %v, want [login:ip:10.0.0.1]", limiter.keys)
+	}
+
+	for header, want := range map[string]string{
+		"RateLimit-Policy":    "5;w=60",
+		"RateLimit-Limit":     "5",
+		"RateLimit-Remaining": "4",
+		"RateLimit-Reset":     "12",
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestRateLimit_SpoofedForwardedForKeepsKey(t *testing.T) {
+	limiter := &mockLimiter{allowFn: func(context.Context, string, ratelimit.Policy) (ratelimit.Result, error) {
+		return ratelimit.Result{Allowed: true, Limit: 5, Remaining: 4}, nil
+	}}
+
+	called := false
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	handler := RealIP(trusted)(RateLimit(limiter, testPolicies)(okHandler(&called)))
+
+	// клиент без прокси перебирает X-Forwarded-For, чтобы каждый раз получать новый лимит
+	for _, spoofed := range []string{"203.0.113.1", "203.0.113.2", "203.0.113.3"} {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+		r.RemoteAddr = "198.51.100.7:1234"
+		r.Header.Set("X-Forwarded-For", spoofed)
+		r.Header.Set("X-Real-IP", spoofed)
+		handler.ServeHTTP(httptest.NewRecorder(), withRoutePattern(r, "/api/v1/auth/login"))
+	}
+
+	for _, key := range limiter.keys {
+		if key != "login:ip:198.51.100.7" {
+			t.Errorf("key = %q, want login:ip:198.51.100.7", key)
+		}
+	}
+
+	// через trusted прокси ключом становится адрес, который дописал прокси
+	limiter.keys = nil
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	r.RemoteAddr = "10.0.0.2:1234"
+	r.Header.Set("X-Forwarded-For", "203.0.113.1, 198.51.100.7")
+	handler.ServeHTTP(httptest.NewRecorder(), withRoutePattern(r, "/api/v1/auth/login"))
+
+	if len(limiter.keys) != 1 || limiter.keys[0] != "login:ip:198.51.100.7" {
+		t.Errorf("keys via proxy = %v, want [login:ip:198.51.100.7]", limiter.keys)
+	}
+}
+
+func TestRateLimit_Rejected(t *testing.T) {
+	limiter := &mockLimiter{allowFn: func(context.Context, string, ratelimit.Policy) (ratelimit.Result, error) {
+		return ratelimit.Result{Allowed: false, Limit: 100, RetryAfter: 1500 * time.Millisecond, ResetAfter: time.Minute}, nil
+	}}
+
+	called := false
+	handler := RateLimit(limiter, testPolicies)(okHandler(&called))
+
+	userID := uuid.New()
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	r = r.WithContext(WithUserID(r.Context(), userID))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if called {
+		t.Fatal("next handler should not be called")
+	}
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	if want := "default:user:" + userID.String(); limiter.keys[0] != want {
+		t.Errorf("key = %q, want %q", limiter.keys[0], want)
+	}
+}
+
+func TestRateLimit_LimiterError(t *testing.T) {
+	limiter := &mockLimiter{allowFn: func(context.Context, string, ratelimit.Policy) (ratelimit.Result, error) {
+		return ratelimit.Result{}, errors.New("redis down")
+	}}
+
+	called := false
+	handler := RateLimit(limiter, testPolicies)(okHandler(&called))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if !called {
+		t.Fatal("expected request to pass when limiter fails")
+	}
+	if w.Header().Get("RateLimit-Limit") != "" {
+		t.Error("expected no rate limit headers when limiter fails")
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+// RealIP - адрес клиента для лимитов, дедупликации просмотров и логов. X-Forwarded-For и X-Real-IP
+// учитываются только от trusted прокси (ingress, балансировщик), иначе берётся RemoteAddr.
+func RealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := utils.ResolveClientIP(r, trusted)
+			next.ServeHTTP(w, r.WithContext(utils.WithClientIP(r.Context(), ip)))
+		})
+	}
+}
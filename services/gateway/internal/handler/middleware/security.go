@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SecurityHeaders - стандартные заголовки для JSON API. HSTS только при hstsMaxAge > 0: локально gateway работает по HTTP.
+func SecurityHeaders(hstsMaxAge time.Duration) func(http.Handler) http.Handler {
+	var hsts string
+	if hstsMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int(hstsMaxAge.Seconds()))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("X-Frame-Options", "DENY")
+			h.Set("Referrer-Policy", "no-referrer")
+			// API отдаёт только JSON: ничего не загружать и не встраиваться во фреймы
+			h.Set("Content-Security-Policy", "default-src 'none'; frame-ancestors 'none'")
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
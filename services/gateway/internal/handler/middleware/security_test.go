@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	var called bool
+	handler := SecurityHeaders(365 * 24 * time.Hour)(okHandler(&called))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+		t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+	}
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=31536000; includeSubDomains" {
+		t.Errorf("Strict-Transport-Security = %q", got)
+	}
+}
+
+func TestSecurityHeaders_NoHSTS(t *testing.T) {
+	var called bool
+	handler := SecurityHeaders(0)(okHandler(&called))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := w.Header().Get("Strict-Transport-Security"); got != "" {
+		t.Errorf("Strict-Transport-Security = %q, want empty", got)
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	var called bool
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"https://habr.example"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})(okHandler(&called))
+
+	r := httptest.NewRequest(http.MethodOptions, "/api/v1/articles", nil)
+	r.Header.Set("Origin", "https://habr.example")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if called {
+		t.Error("preflight reached next handler")
+	}
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://habr.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("Access-Control-Max-Age = %q, want 600", got)
+	}
+}
+
+func TestCORS_UnknownOrigin(t *testing.T) {
+	var called bool
+	handler := CORS(CORSOptions{AllowedOrigins: []string{"https://habr.example"}})(okHandler(&called))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if !called {
+		t.Error("next handler was not called")
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want empty", got)
+	}
+}
+
+func TestCORS_WildcardWithoutCredentials(t *testing.T) {
+	var called bool
+	handler := CORS(CORSOptions{
+		AllowedOrigins:   []string{"*", "https://habr.example"},
+		AllowCredentials: true,
+	})(okHandler(&called))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want empty", got)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/articles", nil)
+	r.Header.Set("Origin", "https://habr.example")
+	w = httptest.NewRecorder()
+
+	handler.ServeHTTP(w, r)
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://habr.example" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want https://habr.example", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		cookies    []*http.Cookie
+		header     string
+		authHeader string
+		wantStatus int
+	}{
+		{
+			name:       "safe method",
+			method:     http.MethodGet,
+			cookies:    []*http.Cookie{{Name: AccessTokenCookie, Value: "t"}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "no auth cookie",
+			method:     http.MethodPost,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "authorization header",
+			method:     http.MethodPost,
+			cookies:    []*http.Cookie{{Name: AccessTokenCookie, Value: "t"}},
+			authHeader: "Bearer t",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "matching token",
+			method:     http.MethodPost,
+			cookies:    []*http.Cookie{{Name: AccessTokenCookie, Value: "t"}, {Name: CSRFTokenCookie, Value: "csrf"}},
+			header:     "csrf",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "missing header",
+			method:     http.MethodDelete,
+			cookies:    []*http.Cookie{{Name: AccessTokenCookie, Value: "t"}, {Name: CSRFTokenCookie, Value: "csrf"}},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "mismatched token",
+			method:     http.MethodPost,
+			cookies:    []*http.Cookie{{Name: RefreshTokenCookie, Value: "t"}, {Name: CSRFTokenCookie, Value: "csrf"}},
+			header:     "other",
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			handler := CSRF()(okHandler(&called))
+
+			r := httptest.NewRequest(tt.method, "/api/v1/articles", nil)
+			for _, c := range tt.cookies {
+				r.AddCookie(c)
+			}
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+			if tt.authHeader != "" {
+				r.Header.Set("Authorization", tt.authHeader)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("next called = %v", called)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/SonOfSteveJobs/habr/pkg/metrics"
+	"github.com/SonOfSteveJobs/habr/services/gateway/internal/handler/http/utils"
+)
+
+const (
+	apiPrefix = "/api/"
+	// vendorMediaPrefix - Accept: application/vnd.habr.v2+json выбирает версию для пути без /vN
+	vendorMediaPrefix = "application/vnd.habr."
+	vendorMediaSuffix = "+json"
+
+	versionSourcePath    = "path"
+	versionSourceAccept  = "accept"
+	versionSourceDefault = "default"
+)
+
+type versionSourceKey struct{}
+
+// NegotiateVersion переписывает путь без версии (/api/articles) в /api/{version}/articles.
+// Версия берётся из Accept, иначе defaultVersion. Версия из Accept, в которой маршрута нет (auth есть только в v1),
+// заменяется на defaultVersion: routes - роутер, на котором смонтированы версии. Запросы с версией в пути не трогает.
+// Ставится через r.Use, чтобы chi маршрутизировал уже по переписанному пути.
+func NegotiateVersion(versions []string, defaultVersion string, routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rest, ok := strings.CutPrefix(r.URL.Path, apiPrefix)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			segment, _, _ := strings.Cut(rest, "/")
+			if slices.Contains(versions, segment) {
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), versionSourceKey{}, versionSourcePath)))
+				return
+			}
+			if isVersionSegment(segment) {
+				utils.WriteProblem(w, r, utils.Problem{
+					Status: http.StatusNotFound,
+					Detail: fmt.Sprintf("unsupported api version %q", segment),
+					Code:   utils.CodeUnsupportedVersion,
+				})
+				return
+			}
+
+			// ответ на путь без версии зависит от Accept - кэши должны это учитывать
+			w.Header().Add("Vary", "Accept")
+
+			version, source := acceptVersion(r.Header.Get("Accept"))
+			if version == "" {
+				version, source = defaultVersion, versionSourceDefault
+			} else if !slices.Contains(versions, version) {
+				utils.WriteProblem(w, r, utils.Problem{
+					Status: http.StatusNotAcceptable,
+					Detail: fmt.Sprintf("unsupported api version %q", version),
+					Code:   utils.CodeUnsupportedVersion,
+				})
+				return
+			}
+			if version != defaultVersion && !routes.Match(chi.NewRouteContext(), r.Method, apiPrefix+version+"/"+rest) {
+				version, source = defaultVersion, versionSourceDefault
+			}
+
+			// WithContext не копирует URL - меняем копию, чтобы не трогать исходный запрос
+			u := *r.URL
+			u.Path = apiPrefix + version + "/" + rest
+			u.RawPath = ""
+
+			r = r.WithContext(context.WithValue(r.Context(), versionSourceKey{}, source))
+			r.URL = &u
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acceptVersion - версия из первого vendor media type в Accept, пустая строка если его нет.
+func acceptVersion(accept string) (string, string) {
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, _, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		rest, ok := strings.CutPrefix(mediaType, vendorMediaPrefix)
+		if !ok {
+			continue
+		}
+		if version, ok := strings.CutSuffix(rest, vendorMediaSuffix); ok && isVersionSegment(version) {
+			return version, versionSourceAccept
+		}
+	}
+
+	return "", ""
+}
+
+func isVersionSegment(s string) bool {
+	digits, ok := strings.CutPrefix(s, "v")
+	if !ok || digits == "" {
+		return false
+	}
+
+	return strings.Trim(digits, "0123456789") == ""
+}
+
+// Deprecation - маршрут, которому есть замена в новой версии API. Successor - шаблон chi,
+// параметры подставляются из запроса. Sunset нулевой, пока дата отключения не назначена.
+type Deprecation struct {
+	Since     time.Time
+	Sunset    time.Time
+	Successor string
+}
+
+// Deprecations - устаревшие маршруты, ключ "GET /pattern" как в chi.
+type Deprecations map[string]Deprecation
+
+// Versioned проставляет API-Version, для устаревших маршрутов Deprecation, Sunset и Link на замену,
+// и считает запросы по версиям. Ставится последним в списке сгенерированных middleware, чтобы заголовки
+// попали и в ответы, которые вернули Auth или RateLimit.
+func Versioned(version string, deprecations Deprecations) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := chi.RouteContext(r.Context()).RoutePattern()
+			h := w.Header()
+			h.Set("API-Version", version)
+
+			d, deprecated := deprecations[r.Method+" "+route]
+			if deprecated {
+				// RFC 9745: дата в формате structured field, @unix-время
+				h.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+				if !d.Sunset.IsZero() {
+					h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+				}
+				if d.Successor != "" {
+					h.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", expandRoute(r, d.Successor)))
+				}
+			}
+
+			source, _ := r.Context().Value(versionSourceKey{}).(string)
+			if source == "" {
+				source = versionSourcePath
+			}
+			metrics.RecordAPIVersion(r.Context(), version, route, source, deprecated)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// expandRoute подставляет в шаблон chi значения параметров текущего запроса.
+func expandRoute(r *http.Request, pattern string) string {
+	rctx := chi.RouteContext(r.Context())
+	for i, key := range rctx.URLParams.Keys {
+		pattern = strings.ReplaceAll(pattern, "{"+key+"}", rctx.URLParams.Values[i])
+	}
+
+	return pattern
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		accept     string
+		wantPath   string
+		wantStatus int
+		wantVary   bool
+	}{
+		{name: "versioned path untouched", path: "/api/v1/articles", accept: "application/vnd.habr.v2+json", wantPath: "/api/v1/articles", wantStatus: http.StatusOK},
+		{name: "default version", path: "/api/articles", wantPath: "/api/v1/articles", wantStatus: http.StatusOK, wantVary: true},
+		{name: "accept selects version", path: "/api/articles/42", accept: "application/json, application/vnd.habr.v2+json;q=0.9", wantPath: "/api/v2/articles/42", wantStatus: http.StatusOK, wantVary: true},
+		{name: "unknown accept version", path: "/api/articles", accept: "application/vnd.habr.v9+json", wantStatus: http.StatusNotAcceptable, wantVary: true},
+		{name: "unknown path version", path: "/api/v9/articles", wantStatus: http.StatusNotFound},
+		{name: "not api", path: "/healthz", accept: "application/vnd.habr.v2+json", wantPath: "/healthz", wantStatus: http.StatusOK},
+		{name: "accept version without route", path: "/api/auth/login", accept: "application/vnd.habr.v2+json", wantPath: "/api/v1/auth/login", wantStatus: http.StatusOK, wantVary: true},
+	}
+
+	noop := func(http.ResponseWriter, *http.Request) {}
+	routes := chi.NewRouter()
+	routes.Get("/api/v1/articles", noop)
+	routes.Get("/api/v1/articles/{id}", noop)
+	routes.Get("/api/v1/auth/login", noop)
+	routes.Get("/api/v2/articles", noop)
+	routes.Get("/api/v2/articles/{id}", noop)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			handler := NegotiateVersion([]string{"v1", "v2"}, "v1", routes)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if gotPath != tt.wantPath {
+				t.Errorf("path = %q, want %q", gotPath, tt.wantPath)
+			}
+			if r.URL.Path != tt.path {
+				t.Errorf("original request path changed to %q", r.URL.Path)
+			}
+			if got := w.Header().Get("Vary") == "Accept"; got != tt.wantVary {
+				t.Errorf("Vary = %q", w.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestVersioned_Deprecated(t *testing.T) {
+	since := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	deprecations := Deprecations{
+		"GET /api/v1/articles/{id}": {Since: since, Sunset: sunset, Successor: "/api/v2/articles/{id}"},
+	}
+
+	called := false
+	handler := Versioned("v1", deprecations)(okHandler(&called))
+
+	r := withRoutePattern(httptest.NewRequest(http.MethodGet, "/api/v1/articles/42", nil), "/api/v1/articles/{id}")
+	chi.RouteContext(r.Context()).URLParams.Add("id", "42")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if !called {
+		t.Fatal("handler not called")
+	}
+	if got := w.Header().Get("API-Version"); got != "v1" {
+		t.Errorf("API-Version = %q", got)
+	}
+	if got := w.Header().Get("Deprecation"); got != "@1792368000" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Thu, 01 Apr 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `</api/v2/articles/42>; rel="successor-version"` {
+		t.Errorf("Link = %q", got)
+	}
+}
+
+func TestVersioned_Current(t *testing.T) {
+	called := false
+	handler := Versioned("v2", nil)(okHandler(&called))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, withRoutePattern(httptest.NewRequest(http.MethodGet, "/api/v2/articles", nil), "/api/v2/articles"))
+
+	if got := w.Header().Get("API-Version"); got != "v2" {
+		t.Errorf("API-Version = %q", got)
+	}
+	if w.Header().Get("Deprecation") != "" || w.Header().Get("Sunset") != "" || w.Header().Get("Link") != "" {
+		t.Errorf("unexpected deprecation headers: %v", w.Header())
+	}
+}
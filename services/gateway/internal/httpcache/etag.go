@@ -0,0 +1,30 @@
+package httpcache
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+)
+
+// ETag - сильный ETag по телу ответа: одинаковые байты дают одинаковый тег на всех инстансах gateway.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// NoneMatch проверяет If-None-Match по слабому сравнению (RFC 9110 13.1.2): W/ префикс не учитывается.
+func NoneMatch(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
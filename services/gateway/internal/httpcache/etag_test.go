@@ -0,0 +1,39 @@
+package httpcache
+
+import "testing"
+
+func TestETag(t *testing.T) {
+	a := ETag([]byte(`{"id":1}`))
+
+	if a != ETag([]byte(`{"id":1}`)) {
+		t.Error("expected the same etag for the same body")
+	}
+	if a == ETag([]byte(`{"id":2}`)) {
+		t.Error("expected different etags for different bodies")
+	}
+	if a[0] != '"' || a[len(a)-1] != '"' {
+		t.Errorf("etag %s must be quoted", a)
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	etag := `"abc"`
+
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{"", false},
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"xyz", "abc"`, true},
+		{`"xyz"`, false},
+		{"*", true},
+	}
+
+	for _, tt := range tests {
+		if got := NoneMatch(tt.header, etag); got != tt.want {
+			t.Errorf("NoneMatch(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
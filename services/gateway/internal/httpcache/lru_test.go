@@ -0,0 +1,69 @@
+package httpcache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRU_Evicts(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	entry := &Entry{ExpiresAt: now.Add(time.Minute)}
+	c.Add("a", entry)
+	c.Add("b", entry)
+
+	// a становится самым свежим, вытесняется b
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a")
+	}
+	c.Add("c", entry)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("expected a to stay")
+	}
+	if c.Len() != 2 {
+		t.Errorf("len = %d, want 2", c.Len())
+	}
+}
+
+func TestLRU_Expires(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", &Entry{Body: []byte("x"), ExpiresAt: now.Add(5 * time.Second)})
+
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected fresh entry")
+	}
+
+	now = now.Add(5 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected expired entry to be dropped")
+	}
+	if c.Len() != 0 {
+		t.Errorf("len = %d, want 0", c.Len())
+	}
+}
+
+func TestLRU_Replace(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := NewLRU(10)
+	c.now = func() time.Time { return now }
+
+	c.Add("a", &Entry{ETag: "1", ExpiresAt: now.Add(time.Minute)})
+	c.Add("a", &Entry{ETag: "2", ExpiresAt: now.Add(time.Minute)})
+
+	got, _ := c.Get("a")
+	if got.ETag != "2" {
+		t.Errorf("etag = %q, want 2", got.ETag)
+	}
+	if c.Len() != 1 {
+		t.Errorf("len = %d, want 1", c.Len())
+	}
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+)
+
+// FallbackLimiter при ошибке основного лимитера (обычно недоступен Redis) считает лимит в запасном.
+type FallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func NewFallbackLimiter(primary, fallback Limiter) *FallbackLimiter {
+	return &FallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, p Policy) (Result, error) {
+	res, err := l.primary.Allow(ctx, key, p)
+	if err == nil {
+		return res, nil
+	}
+
+	log := logger.Ctx(ctx)
+	log.Warn().Err(err).Str("policy", p.Name).Msg("rate limiter unavailable, using fallback")
+
+	return l.fallback.Allow(ctx, key, p)
+}
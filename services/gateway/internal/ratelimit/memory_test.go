@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryLimiter_Allow(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	p := Policy{Name: "login", Limit: 2, Window: time.Minute}
+
+	for i := range 2 {
+		res, err := l.Allow(context.Background(), "ip:1.1.1.1", p)
+		if err != nil || !res.Allowed {
+			t.Fatalf("request %d: expected allowed, got %+v, %v", i, res, err)
+		}
+	}
+
+	res, _ := l.Allow(context.Background(), "ip:1.1.1.1", p)
+	if res.Allowed {
+		t.Fatal("expected denied")
+	}
+
+	// другой ключ считается отдельно
+	res, _ = l.Allow(context.Background(), "ip:2.2.2.2", p)
+	if !res.Allowed {
+		t.Fatal("expected allowed for another key")
+	}
+
+	now = now.Add(30 * time.Second)
+	res, _ = l.Allow(context.Background(), "ip:1.1.1.1", p)
+	if !res.Allowed {
+		t.Fatal("expected allowed after interval")
+	}
+}
+
+func TestMemoryLimiter_Sweep(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	l := NewMemoryLimiter()
+	l.now = func() time.Time { return now }
+
+	p := Policy{Name: "p", Limit: 1, Window: time.Second}
+	_, _ = l.Allow(context.Background(), "a", p)
+
+	now = now.Add(2 * sweepInterval)
+	_, _ = l.Allow(context.Background(), "b", p)
+
+	if _, ok := l.tats["a"]; ok {
+		t.Error("expected expired key to be swept")
+	}
+	if _, ok := l.tats["b"]; !ok {
+		t.Error("expected fresh key to stay")
+	}
+}
+
+type errLimiter struct{}
+
+func (errLimiter) Allow(context.Context, string, Policy) (Result, error) {
+	return Result{}, errors.New("redis down")
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	l := NewFallbackLimiter(errLimiter{}, NewMemoryLimiter())
+
+	res, err := l.Allow(context.Background(), "k", Policy{Name: "p", Limit: 1, Window: time.Minute})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Allowed {
+		t.Fatal("expected fallback to allow")
+	}
+}
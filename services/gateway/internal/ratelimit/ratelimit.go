@@ -0,0 +1,90 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidPolicy = errors.New("invalid rate limit policy")
+
+// Policy - не больше Limit запросов за Window. Допускается всплеск до Limit, дальше запросы равномерно по Window/Limit.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// ParsePolicy разбирает политику вида "300/1m".
+func ParsePolicy(name, s string) (Policy, error) {
+	limitStr, windowStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Policy{}, fmt.Errorf("%w: %q", ErrInvalidPolicy, s)
+	}
+
+	limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+	if err != nil || limit <= 0 {
+		return Policy{}, fmt.Errorf("%w: %q", ErrInvalidPolicy, s)
+	}
+
+	window, err := time.ParseDuration(strings.TrimSpace(windowStr))
+	if err != nil || window <= 0 {
+		return Policy{}, fmt.Errorf("%w: %q", ErrInvalidPolicy, s)
+	}
+
+	return Policy{Name: name, Limit: limit, Window: window}, nil
+}
+
+// interval - через сколько восстанавливается один запрос.
+func (p Policy) interval() time.Duration {
+	return max(p.Window/time.Duration(p.Limit), 1)
+}
+
+// Result - решение лимитера и данные для заголовков RateLimit-*.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter - через сколько квота восстановится полностью.
+	ResetAfter time.Duration
+	// RetryAfter - через сколько можно повторить отклонённый запрос.
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// gcra - generic cell rate algorithm: хранится только TAT (theoretical arrival time) на ключ.
+// Возвращает новый TAT и решение; при отказе TAT не меняется.
+func gcra(now, tat time.Time, p Policy) (time.Time, Result) {
+	interval := p.interval()
+	burst := interval * time.Duration(p.Limit)
+
+	if tat.Before(now) {
+		tat = now
+	}
+
+	newTAT := tat.Add(interval)
+	allowAt := newTAT.Add(-burst)
+
+	if allowAt.After(now) {
+		return tat, Result{
+			Allowed:    false,
+			Limit:      p.Limit,
+			Remaining:  0,
+			ResetAfter: tat.Sub(now),
+			RetryAfter: allowAt.Sub(now),
+		}
+	}
+
+	return newTAT, Result{
+		Allowed:    true,
+		Limit:      p.Limit,
+		Remaining:  int(now.Sub(allowAt) / interval),
+		ResetAfter: newTAT.Sub(now),
+	}
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "minute", input: "300/1m", want: Policy{Name: "p", Limit: 300, Window: time.Minute}},
+		{name: "spaces", input: " 5 / 10s ", want: Policy{Name: "p", Limit: 5, Window: 10 * time.Second}},
+		{name: "no slash", input: "300", wantErr: true},
+		{name: "zero limit", input: "0/1m", wantErr: true},
+		{name: "bad window", input: "10/minute", wantErr: true},
+		{name: "negative window", input: "10/-1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy("p", tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPolicy) {
+					t.Fatalf("expected ErrInvalidPolicy, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGCRA(t *testing.T) {
+	p := Policy{Name: "p", Limit: 3, Window: 3 * time.Second}
+	now := time.Unix(1_700_000_000, 0)
+
+	var tat time.Time
+	for i := range 3 {
+		var res Result
+		tat, res = gcra(now, tat, p)
+		if !res.Allowed {
+			t.Fatalf("request %d: expected allowed", i)
+		}
+		if res.Remaining != 2-i {
+			t.Errorf("request %d: remaining = %d, want %d", i, res.Remaining, 2-i)
+		}
+	}
+
+	tat, res := gcra(now, tat, p)
+	if res.Allowed {
+		t.Fatal("expected denied after burst")
+	}
+	if res.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %v, want 1s", res.RetryAfter)
+	}
+	if res.ResetAfter != 3*time.Second {
+		t.Errorf("ResetAfter = %v, want 3s", res.ResetAfter)
+	}
+
+	_, res = gcra(now.Add(time.Second), tat, p)
+	if !res.Allowed {
+		t.Fatal("expected allowed after one interval")
+	}
+	if res.Remaining != 0 {
+		t.Errorf("remaining = %d, want 0", res.Remaining)
+	}
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit:"
+
+// gcraScript - тот же GCRA, что и в памяти, но атомарно в Redis. Время берётся из TIME, чтобы часы инстансов gateway не влияли на лимит.
+// TAT хранится в микросекундах строкой через %d: tostring в Lua 5.1 перевёл бы большое число в экспоненциальную запись.
+var gcraScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + interval
+local allow_at = new_tat - burst
+if allow_at > now then
+	return {0, 0, tat - now, allow_at - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / interval), new_tat - now, 0}
+`)
+
+// RedisLimiter - GCRA в Redis, лимит общий для всех инстансов gateway.
+type RedisLimiter struct {
+	client *redis.Client
+}
+
+func NewRedisLimiter(client *redis.Client) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, p Policy) (Result, error) {
+	interval := p.interval()
+
+	res, err := gcraScript.Run(
+		ctx, l.client,
+		[]string{keyPrefix + key},
+		interval.Microseconds(), (interval * time.Duration(p.Limit)).Microseconds(),
+	).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    res[0] == 1,
+		Limit:      p.Limit,
+		Remaining:  int(res[1]),
+		ResetAfter: time.Duration(res[2]) * time.Microsecond,
+		RetryAfter: time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
@@ -2,9 +2,13 @@ package app
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
+	"github.com/SonOfSteveJobs/habr/pkg/health"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
@@ -14,6 +18,9 @@ import (
 type App struct {
 	infra   *infraContainer
 	service *serviceContainer
+	health  *health.Health
+
+	healthServer *http.Server
 }
 
 func New(ctx context.Context) (*App, error) {
@@ -36,7 +43,7 @@ func (a *App) Run() {
 	})
 
 	go func() {
-		if err := a.service.KafkaConsumer().Consume(consumerCtx, a.service.NotificationService().HandleEvent); err != nil {
+		if err := a.consume(consumerCtx); err != nil {
 			log.Error().Err(err).Msg("kafka consumer failed")
 		}
 	}()
@@ -66,11 +73,32 @@ func (a *App) Run() {
 		}
 	}()
 
+	go func() {
+		if err := a.healthServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("health HTTP server failed")
+		}
+	}()
+
 	log.Info().Msg("notification service started")
 
 	closer.Wait()
 }
 
+// consume - при KAFKA_BATCH_SIZE > 0 события обрабатываются батчами, по транзакции на батч.
+func (a *App) consume(ctx context.Context) error {
+	cfg := config.AppConfig().Kafka()
+	svc := a.service.NotificationService()
+
+	if cfg.BatchSize() > 0 {
+		return a.service.KafkaConsumer().ConsumeBatch(ctx, svc.HandleBatch, consumer.BatchOptions{
+			MaxSize: cfg.BatchSize(),
+			MaxWait: cfg.BatchWait(),
+		})
+	}
+
+	return a.service.KafkaConsumer().Consume(ctx, svc.HandleEvent)
+}
+
 type initStep struct {
 	name string
 	fn   func(context.Context) error
@@ -85,6 +113,7 @@ func (a *App) initDeps(ctx context.Context) error {
 		{"metrics", a.initMetrics},
 		{"infra: postgres, kafka", a.initInfra},
 		{"service: consumer", a.initService},
+		{"health", a.initHealth},
 	}
 
 	for _, s := range steps {
@@ -141,3 +170,30 @@ func (a *App) initService(_ context.Context) error {
 	a.service = newServiceContainer(a.infra)
 	return nil
 }
+
+// initHealth - у notification нет своего сервера, /healthz и /readyz отдаются на HEALTH_HTTP_PORT.
+// Там же admin эндпоинт /admin/kafka/assignments: назначенные consumer партиции и лаг по ним.
+func (a *App) initHealth(_ context.Context) error {
+	cfg := config.AppConfig()
+
+	closer.SetDrainDelay(cfg.DrainDelay())
+	a.health = health.New(closer.Draining())
+	a.health.Add("postgres", health.Postgres(a.infra.PgPool()))
+	a.health.Add("kafka", health.Kafka(cfg.Kafka().Brokers()))
+
+	mux := http.NewServeMux()
+	mux.Handle("/", a.health.Handler())
+	mux.Handle("GET /admin/kafka/assignments", a.service.KafkaConsumer().AssignmentsHandler())
+
+	a.healthServer = &http.Server{
+		Addr:              cfg.HealthHTTPPort(),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	closer.AddNamed("health HTTP server", func(ctx context.Context) error {
+		return a.healthServer.Shutdown(ctx)
+	})
+
+	return nil
+}
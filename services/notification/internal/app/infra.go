@@ -10,13 +10,16 @@ import (
 
 	"github.com/SonOfSteveJobs/habr/pkg/closer"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
 	"github.com/SonOfSteveJobs/habr/pkg/transaction"
 	"github.com/SonOfSteveJobs/habr/services/notification/internal/config"
 )
 
 type infraContainer struct {
+	pgPool        *pgxpool.Pool
 	txManager     *transaction.Manager
 	consumerGroup sarama.ConsumerGroup
+	syncProducer  sarama.SyncProducer
 }
 
 func newInfraContainer(ctx context.Context) (*infraContainer, error) {
@@ -30,11 +33,17 @@ func newInfraContainer(ctx context.Context) (*infraContainer, error) {
 		return nil, fmt.Errorf("kafka consumer group: %w", err)
 	}
 
+	if err := c.initSyncProducer(); err != nil {
+		return nil, fmt.Errorf("kafka producer: %w", err)
+	}
+
 	return c, nil
 }
 
+func (c *infraContainer) PgPool() *pgxpool.Pool               { return c.pgPool }
 func (c *infraContainer) TxManager() *transaction.Manager     { return c.txManager }
 func (c *infraContainer) ConsumerGroup() sarama.ConsumerGroup { return c.consumerGroup }
+func (c *infraContainer) SyncProducer() sarama.SyncProducer   { return c.syncProducer }
 
 func (c *infraContainer) initPgPool(ctx context.Context) error {
 	pgCfg, err := pgxpool.ParseConfig(config.AppConfig().DBURI())
@@ -53,6 +62,7 @@ func (c *infraContainer) initPgPool(ctx context.Context) error {
 		return nil
 	})
 
+	c.pgPool = pool
 	c.txManager = transaction.New(pool)
 	return nil
 }
@@ -72,3 +82,20 @@ func (c *infraContainer) initConsumerGroup() error {
 	c.consumerGroup = group
 	return nil
 }
+
+// initSyncProducer - продюсер для retry топиков и DLQ. Синхронный: сообщение помечается прочитанным,
+// только когда его копия уже записана.
+func (c *infraContainer) initSyncProducer() error {
+	cfg := config.AppConfig().Kafka()
+
+	p, err := sarama.NewSyncProducer(cfg.Brokers(), producer.NewSyncConfig(producer.WithIdempotent(), producer.WithHashPartitioner()))
+	if err != nil {
+		return err
+	}
+	closer.AddNamed("kafka producer", func(_ context.Context) error {
+		return p.Close()
+	})
+
+	c.syncProducer = p
+	return nil
+}
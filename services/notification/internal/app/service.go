@@ -2,6 +2,7 @@ package app
 
 import (
 	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
 	"github.com/SonOfSteveJobs/habr/pkg/metrics"
 	"github.com/SonOfSteveJobs/habr/pkg/tracing"
 	"github.com/SonOfSteveJobs/habr/services/notification/internal/config"
@@ -25,15 +26,21 @@ func newServiceContainer(infra *infraContainer) *serviceContainer {
 func (c *serviceContainer) KafkaConsumer() *consumer.Consumer {
 	if c.kafkaConsumer == nil {
 		cfg := config.AppConfig().Kafka()
+		// топик продюсера не используется: retry и DLQ сообщения несут свой топик
+		p := producer.NewSync(c.infra.SyncProducer(), "")
+
 		c.kafkaConsumer = consumer.New(
 			c.infra.ConsumerGroup(),
-			[]string{cfg.Topic()},
-			consumer.Recovery,
+			append([]string{cfg.Topic()}, consumer.RetryTopics(cfg.Topic(), consumer.DefaultRetryTiers)...),
 			tracing.ConsumerMiddleware(),
 			metrics.ConsumerMiddleware(),
 			consumer.Logging,
-			consumer.WithRetry(3),
-		)
+			consumer.WithDLQ(p),
+			consumer.WithRetryTopics(p, consumer.DefaultRetryTiers...),
+			// паника внутри обработчика - такая же ошибка, она тоже уходит в retry топики и DLQ
+			consumer.Recovery,
+			consumer.WithRetryPolicy(consumer.DefaultRetryPolicy),
+		).Concurrent(cfg.Workers())
 	}
 
 	return c.kafkaConsumer
@@ -14,6 +14,7 @@ const (
 	defaultEventTTL        = 15 * time.Minute
 	defaultCleanupInterval = 1 * time.Hour
 	defaultRetentionPeriod = 7 * 24 * time.Hour
+	defaultHealthHTTPPort  = ":8081"
 )
 
 type Config struct {
@@ -24,6 +25,8 @@ type Config struct {
 	cleanupInterval time.Duration
 	retentionPeriod time.Duration
 	tracing         *TracingConfig
+	healthHTTPPort  string
+	drainDelay      time.Duration
 }
 
 func (c *Config) DBURI() string                  { return c.dbURI }
@@ -33,6 +36,10 @@ func (c *Config) EventTTL() time.Duration        { return c.eventTTL }
 func (c *Config) CleanupInterval() time.Duration { return c.cleanupInterval }
 func (c *Config) RetentionPeriod() time.Duration { return c.retentionPeriod }
 func (c *Config) Tracing() *TracingConfig        { return c.tracing }
+func (c *Config) HealthHTTPPort() string         { return c.healthHTTPPort }
+
+// DrainDelay - пауза между 503 на /readyz и остановкой consumer, 0 - сразу.
+func (c *Config) DrainDelay() time.Duration { return c.drainDelay }
 
 func Load(path ...string) error {
 	err := godotenv.Load(path...)
@@ -87,6 +94,20 @@ func Load(path ...string) error {
 		return err
 	}
 
+	healthHTTPPort := defaultHealthHTTPPort
+	if v := os.Getenv("HEALTH_HTTP_PORT"); v != "" {
+		healthHTTPPort = v
+	}
+
+	var drainDelay time.Duration
+	if v := os.Getenv("SHUTDOWN_DRAIN_DELAY"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed < 0 {
+			return ErrInvalidDrainDelay
+		}
+		drainDelay = parsed
+	}
+
 	appConfig = &Config{
 		dbURI:           dbURI,
 		logger:          logger,
@@ -95,6 +116,8 @@ func Load(path ...string) error {
 		cleanupInterval: cleanupInterval,
 		retentionPeriod: retentionPeriod,
 		tracing:         tracing,
+		healthHTTPPort:  healthHTTPPort,
+		drainDelay:      drainDelay,
 	}
 
 	return nil
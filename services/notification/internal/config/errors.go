@@ -10,6 +10,10 @@ var (
 	ErrKafkaBrokersNotProvided    = errors.New("KAFKA_BROKERS is not provided")
 	ErrKafkaTopicNotProvided      = errors.New("KAFKA_TOPIC is not provided")
 	ErrKafkaGroupIDNotProvided    = errors.New("KAFKA_GROUP_ID is not provided")
+	ErrKafkaWorkersInvalid        = errors.New("KAFKA_CONSUMER_WORKERS must be a positive integer")
+	ErrKafkaBatchSizeInvalid      = errors.New("KAFKA_BATCH_SIZE must be a non-negative integer")
+	ErrKafkaBatchWaitInvalid      = errors.New("KAFKA_BATCH_WAIT must be a positive duration")
 	ErrOtelEndpointNotProvided    = errors.New("OTEL_COLLECTOR_ENDPOINT is not provided")
 	ErrOtelServiceNameNotProvided = errors.New("OTEL_SERVICE_NAME is not provided")
+	ErrInvalidDrainDelay          = errors.New("SHUTDOWN_DRAIN_DELAY must be a non-negative duration")
 )
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type LoggerConfig interface {
 	Level() string
 	AsJson() bool
@@ -9,4 +11,9 @@ type KafkaConfig interface {
 	Brokers() []string
 	Topic() string
 	GroupID() string
+	// Workers - горутин на партицию, 1 - последовательная обработка
+	Workers() int
+	// BatchSize - сообщений в батче, 0 - обработка по одному
+	BatchSize() int
+	BatchWait() time.Duration
 }
@@ -1,19 +1,33 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
+)
+
+const (
+	defaultConsumerWorkers = 1
+	defaultBatchWait       = 500 * time.Millisecond
 )
 
 type kafkaConfig struct {
-	brokers []string
-	topic   string
-	groupID string
+	brokers   []string
+	topic     string
+	groupID   string
+	workers   int
+	batchSize int
+	batchWait time.Duration
 }
 
-func (c *kafkaConfig) Brokers() []string { return c.brokers }
-func (c *kafkaConfig) Topic() string     { return c.topic }
-func (c *kafkaConfig) GroupID() string   { return c.groupID }
+func (c *kafkaConfig) Brokers() []string        { return c.brokers }
+func (c *kafkaConfig) Topic() string            { return c.topic }
+func (c *kafkaConfig) GroupID() string          { return c.groupID }
+func (c *kafkaConfig) Workers() int             { return c.workers }
+func (c *kafkaConfig) BatchSize() int           { return c.batchSize }
+func (c *kafkaConfig) BatchWait() time.Duration { return c.batchWait }
 
 func newKafkaConfig() (*kafkaConfig, error) {
 	brokersStr := os.Getenv("KAFKA_BROKERS")
@@ -31,14 +45,44 @@ func newKafkaConfig() (*kafkaConfig, error) {
 		return nil, ErrKafkaGroupIDNotProvided
 	}
 
+	workers := defaultConsumerWorkers
+	if v := os.Getenv("KAFKA_CONSUMER_WORKERS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			return nil, fmt.Errorf("%w: %q", ErrKafkaWorkersInvalid, v)
+		}
+		workers = parsed
+	}
+
+	batchSize := 0
+	if v := os.Getenv("KAFKA_BATCH_SIZE"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrKafkaBatchSizeInvalid, v)
+		}
+		batchSize = parsed
+	}
+
+	batchWait := defaultBatchWait
+	if v := os.Getenv("KAFKA_BATCH_WAIT"); v != "" {
+		parsed, err := time.ParseDuration(v)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrKafkaBatchWaitInvalid, v)
+		}
+		batchWait = parsed
+	}
+
 	brokers := strings.Split(brokersStr, ",")
 	for i := range brokers {
 		brokers[i] = strings.TrimSpace(brokers[i])
 	}
 
 	return &kafkaConfig{
-		brokers: brokers,
-		topic:   topic,
-		groupID: groupID,
+		brokers:   brokers,
+		topic:     topic,
+		groupID:   groupID,
+		workers:   workers,
+		batchSize: batchSize,
+		batchWait: batchWait,
 	}, nil
 }
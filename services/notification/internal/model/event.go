@@ -2,10 +2,12 @@ package model
 
 import "time"
 
+// UserRegisteredEvent - событие регистрации после разбора конверта. Контракт на проводе -
+// events.v1.UserRegistered из proto/events/v1, EventID и CreatedAt берутся из конверта.
 type UserRegisteredEvent struct {
-	EventID   string    `json:"event_id"`
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
-	Code      string    `json:"code"`
-	CreatedAt time.Time `json:"created_at"`
+	EventID   string
+	UserID    string
+	Email     string
+	Code      string
+	CreatedAt time.Time
 }
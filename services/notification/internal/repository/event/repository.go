@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/SonOfSteveJobs/habr/pkg/transaction"
 )
@@ -29,6 +30,33 @@ func (r *Repository) MarkProcessed(ctx context.Context, eventID uuid.UUID) (bool
 	return ct.RowsAffected() == 1, nil
 }
 
+func (r *Repository) MarkProcessedBatch(ctx context.Context, eventIDs []uuid.UUID) ([]uuid.UUID, error) {
+	const query = `INSERT INTO processed_events (event_id) SELECT unnest($1::uuid[]) ON CONFLICT DO NOTHING RETURNING event_id`
+
+	rows, err := r.txManager.ExtractExecutor(ctx).Query(ctx, query, eventIDs)
+	if err != nil {
+		return nil, fmt.Errorf("mark processed batch: %w", err)
+	}
+
+	inserted, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("mark processed batch: %w", err)
+	}
+
+	return inserted, nil
+}
+
+func (r *Repository) UnmarkProcessed(ctx context.Context, eventIDs []uuid.UUID) error {
+	const query = `DELETE FROM processed_events WHERE event_id = ANY($1::uuid[])`
+
+	_, err := r.txManager.ExtractExecutor(ctx).Exec(ctx, query, eventIDs)
+	if err != nil {
+		return fmt.Errorf("unmark processed: %w", err)
+	}
+
+	return nil
+}
+
 func (r *Repository) DeleteOld(ctx context.Context, retention time.Duration) error {
 	const query = `DELETE FROM processed_events WHERE processed_at < now() - $1::interval`
 
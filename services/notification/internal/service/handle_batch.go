@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/logger"
+	"github.com/SonOfSteveJobs/habr/services/notification/internal/model"
+)
+
+// HandleBatch - пачка событий в одной транзакции: все event ID помечаются одним запросом, письма отправляются
+// только по новым. События, письмо по которым не ушло, снимаются с пометки в той же транзакции и возвращаются
+// в kafka.BatchError - consumer повторит только их.
+func (s *Service) HandleBatch(ctx context.Context, msgs []kafka.Message) error {
+	var batchErr kafka.BatchError
+
+	events := make(map[uuid.UUID]model.UserRegisteredEvent, len(msgs))
+	index := make(map[uuid.UUID]int, len(msgs))
+	ids := make([]uuid.UUID, 0, len(msgs))
+
+	for i, msg := range msgs {
+		event, eventID, ok, err := s.decodeEvent(msg)
+		if err != nil {
+			batchErr.Fail(i, err)
+			continue
+		}
+		// просроченное событие или дубликат внутри батча
+		if _, dup := events[eventID]; !ok || dup {
+			continue
+		}
+
+		events[eventID] = event
+		index[eventID] = i
+		ids = append(ids, eventID)
+	}
+
+	if len(ids) == 0 {
+		return batchErr.ErrOrNil()
+	}
+
+	err := s.txManager.Wrap(ctx, func(ctx context.Context) error {
+		inserted, err := s.eventRepo.MarkProcessedBatch(ctx, ids)
+		if err != nil {
+			return fmt.Errorf("mark processed: %w", err)
+		}
+
+		if skipped := len(ids) - len(inserted); skipped > 0 {
+			log := logger.Ctx(ctx)
+			log.Info().Int("duplicates", skipped).Msg("duplicate events in batch, skipping")
+		}
+
+		var unsent []uuid.UUID
+		for _, id := range inserted {
+			if err := s.emailSender.Send(ctx, events[id]); err != nil {
+				batchErr.Fail(index[id], fmt.Errorf("send email: %w", err))
+				unsent = append(unsent, id)
+			}
+		}
+
+		if len(unsent) > 0 {
+			if err := s.eventRepo.UnmarkProcessed(ctx, unsent); err != nil {
+				return fmt.Errorf("unmark processed: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return batchErr.ErrOrNil()
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/services/notification/internal/model"
+)
+
+func TestHandleBatch_Success(t *testing.T) {
+	first, second := testEvent(t), testEvent(t)
+
+	eventRepo := &mockEventRepo{
+		markProcessedFn: func(_ context.Context, _ uuid.UUID) (bool, error) { return true, nil },
+	}
+	sent := 0
+	emailSender := &mockEmailSender{
+		sendFn: func(_ context.Context, _ model.UserRegisteredEvent) error { sent++; return nil },
+	}
+	svc := newTestService(eventRepo, emailSender)
+
+	// второе сообщение - дубликат первого внутри батча
+	msgs := []kafka.Message{testMessage(t, first), testMessage(t, first), testMessage(t, second)}
+	if err := svc.HandleBatch(context.Background(), msgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if sent != 2 {
+		t.Errorf("sent = %d, want 2", sent)
+	}
+}
+
+func TestHandleBatch_PartialFailure(t *testing.T) {
+	ok, failing, duplicate := testEvent(t), testEvent(t), testEvent(t)
+	sendErr := errors.New("smtp connection refused")
+
+	eventRepo := &mockEventRepo{
+		markProcessedFn: func(_ context.Context, id uuid.UUID) (bool, error) {
+			return id.String() != duplicate.EventID, nil
+		},
+	}
+	emailSender := &mockEmailSender{
+		sendFn: func(_ context.Context, e model.UserRegisteredEvent) error {
+			if e.EventID == failing.EventID {
+				return sendErr
+			}
+			return nil
+		},
+	}
+	svc := newTestService(eventRepo, emailSender)
+
+	msgs := []kafka.Message{
+		testMessage(t, ok),
+		{Value: []byte("not-json")},
+		testMessage(t, failing),
+		testMessage(t, duplicate),
+	}
+
+	err := svc.HandleBatch(context.Background(), msgs)
+
+	var batchErr *kafka.BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("error = %v, want *kafka.BatchError", err)
+	}
+	if len(batchErr.Failed) != 2 {
+		t.Fatalf("failed = %v, want messages 1 and 2", batchErr.Failed)
+	}
+	if kafka.Classify(batchErr.Failed[1]) != kafka.ErrorPermanent {
+		t.Errorf("invalid JSON class = %v, want permanent", kafka.Classify(batchErr.Failed[1]))
+	}
+	if !errors.Is(batchErr.Failed[2], sendErr) {
+		t.Errorf("failed[2] = %v, want %v", batchErr.Failed[2], sendErr)
+	}
+
+	if len(eventRepo.unmarked) != 1 || eventRepo.unmarked[0].String() != failing.EventID {
+		t.Errorf("unmarked = %v, want only failed event", eventRepo.unmarked)
+	}
+}
+
+func TestHandleBatch_RepoErrorFailsBatch(t *testing.T) {
+	repoErr := errors.New("db connection refused")
+
+	eventRepo := &mockEventRepo{
+		markProcessedFn: func(_ context.Context, _ uuid.UUID) (bool, error) { return false, repoErr },
+	}
+	emailSender := &mockEmailSender{
+		sendFn: func(_ context.Context, _ model.UserRegisteredEvent) error { return nil },
+	}
+	svc := newTestService(eventRepo, emailSender)
+
+	err := svc.HandleBatch(context.Background(), []kafka.Message{testMessage(t, testEvent(t))})
+	if !errors.Is(err, repoErr) {
+		t.Errorf("error = %v, want %v", err, repoErr)
+	}
+	if emailSender.sendCalled {
+		t.Error("emailSender.Send should not be called when MarkProcessedBatch fails")
+	}
+}
@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/services/notification/internal/model"
 )
@@ -16,24 +19,9 @@ import (
 func (s *Service) HandleEvent(ctx context.Context, msg kafka.Message) error {
 	log := logger.Logger()
 
-	var event model.UserRegisteredEvent
-	if err := json.Unmarshal(msg.Value, &event); err != nil {
-		log.Error().Err(err).Msg("failed to unmarshal event")
-		return fmt.Errorf("unmarshal event: %w", err)
-	}
-
-	if time.Since(event.CreatedAt) > s.eventTTL {
-		log.Warn().
-			Str("event_id", event.EventID).
-			Time("created_at", event.CreatedAt).
-			Msg("event TTL expired, skipping")
-		return nil
-	}
-
-	eventID, err := uuid.Parse(event.EventID)
-	if err != nil {
-		log.Error().Err(err).Str("event_id", event.EventID).Msg("invalid event ID")
-		return fmt.Errorf("parse event ID: %w", err)
+	event, eventID, ok, err := s.decodeEvent(msg)
+	if err != nil || !ok {
+		return err
 	}
 
 	return s.txManager.Wrap(ctx, func(ctx context.Context) error {
@@ -54,3 +42,73 @@ func (s *Service) HandleEvent(ctx context.Context, msg kafka.Message) error {
 		return nil
 	})
 }
+
+// decodeEvent - ok=false для просроченного события, которое надо пропустить.
+// Битый конверт, чужой тип или версия события и битый event ID - permanent ошибки, повтор не поможет.
+func (s *Service) decodeEvent(msg kafka.Message) (model.UserRegisteredEvent, uuid.UUID, bool, error) {
+	log := logger.Logger()
+
+	event, err := decodePayload(msg)
+	if err != nil {
+		log.Error().Err(err).Str("content_type", msg.ContentType()).Msg("failed to decode event")
+		return model.UserRegisteredEvent{}, uuid.Nil, false, fmt.Errorf("decode event: %w", err)
+	}
+
+	if time.Since(event.CreatedAt) > s.eventTTL {
+		log.Warn().
+			Str("event_id", event.EventID).
+			Time("created_at", event.CreatedAt).
+			Msg("event TTL expired, skipping")
+		return event, uuid.Nil, false, nil
+	}
+
+	eventID, err := uuid.Parse(event.EventID)
+	if err != nil {
+		log.Error().Err(err).Str("event_id", event.EventID).Msg("invalid event ID")
+		return event, uuid.Nil, false, kafka.Permanent(fmt.Errorf("parse event ID: %w", err))
+	}
+
+	return event, eventID, true, nil
+}
+
+func decodePayload(msg kafka.Message) (model.UserRegisteredEvent, error) {
+	var payload eventsv1.UserRegistered
+	env, err := envelope.Decode(msg, &payload)
+	if errors.Is(err, envelope.ErrNotEnveloped) {
+		return decodeLegacy(msg)
+	}
+	if err != nil {
+		return model.UserRegisteredEvent{}, err
+	}
+
+	return model.UserRegisteredEvent{
+		EventID:   env.GetId(),
+		UserID:    payload.GetUserId(),
+		Email:     payload.GetEmail(),
+		Code:      payload.GetCode(),
+		CreatedAt: env.GetOccurredAt().AsTime(),
+	}, nil
+}
+
+// legacyUserRegistered - плоский JSON, который auth писал в outbox до конвертов.
+type legacyUserRegistered struct {
+	EventID   string    `json:"event_id"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// decodeLegacy - старые события ещё лежат в топике и неотправленных строках outbox,
+// без этого письма с кодом по ним потерялись бы при выкатке.
+func decodeLegacy(msg kafka.Message) (model.UserRegisteredEvent, error) {
+	var legacy legacyUserRegistered
+	if err := json.Unmarshal(msg.Value, &legacy); err != nil {
+		return model.UserRegisteredEvent{}, kafka.Permanent(fmt.Errorf("unmarshal legacy event: %w", err))
+	}
+	if legacy.EventID == "" || legacy.Email == "" {
+		return model.UserRegisteredEvent{}, kafka.Permanent(fmt.Errorf("%w: legacy event without event_id or email", envelope.ErrNotEnveloped))
+	}
+
+	return model.UserRegisteredEvent(legacy), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/services/notification/internal/model"
 )
 
@@ -101,6 +102,66 @@ func TestHandleEvent_InvalidJSON(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for invalid JSON")
 	}
+	if kafka.Classify(err) != kafka.ErrorPermanent {
+		t.Errorf("class = %v, want permanent", kafka.Classify(err))
+	}
+}
+
+// Событие в старом формате без конверта не читается: тип в конверте пустой.
+func TestHandleEvent_LegacyPayload(t *testing.T) {
+	createdAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	var sent model.UserRegisteredEvent
+	svc := newTestService(
+		&mockEventRepo{markProcessedFn: func(_ context.Context, _ uuid.UUID) (bool, error) { return true, nil }},
+		&mockEmailSender{sendFn: func(_ context.Context, e model.UserRegisteredEvent) error {
+			sent = e
+			return nil
+		}},
+	)
+
+	// payload до конвертов: старые строки outbox получили content-type по умолчанию из миграции
+	value := `{"event_id":"0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11","user_id":"7c1e4f0a-2b7d-4a51-9f62-0d3c9e5b8a21",` +
+		`"email":"user@example.com","code":"123456","created_at":"` + createdAt.Format(time.RFC3339) + `"}`
+
+	for name, headers := range map[string]map[string][]byte{
+		"without content-type": nil,
+		"outbox default":       {kafka.HeaderContentType: []byte(envelope.ContentTypeJSON)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := svc.HandleEvent(context.Background(), kafka.Message{Value: []byte(value), Headers: headers})
+			if err != nil {
+				t.Fatalf("HandleEvent: %v", err)
+			}
+
+			want := model.UserRegisteredEvent{
+				EventID:   "0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11",
+				UserID:    "7c1e4f0a-2b7d-4a51-9f62-0d3c9e5b8a21",
+				Email:     "user@example.com",
+				Code:      "123456",
+				CreatedAt: createdAt,
+			}
+			if !sent.CreatedAt.Equal(want.CreatedAt) || sent.EventID != want.EventID || sent.Email != want.Email ||
+				sent.Code != want.Code || sent.UserID != want.UserID {
+				t.Errorf("sent = %+v, want %+v", sent, want)
+			}
+		})
+	}
+}
+
+func TestHandleEvent_LegacyPayloadWithoutEmail(t *testing.T) {
+	svc := newTestService(
+		&mockEventRepo{markProcessedFn: func(_ context.Context, _ uuid.UUID) (bool, error) { return true, nil }},
+		&mockEmailSender{sendFn: func(_ context.Context, _ model.UserRegisteredEvent) error { return nil }},
+	)
+
+	err := svc.HandleEvent(context.Background(), kafka.Message{Value: []byte(`{"event_id":"0b9f0c52-5f57-4f6a-9d0e-3c2a3a4f1b11"}`)})
+	if !errors.Is(err, envelope.ErrNotEnveloped) {
+		t.Fatalf("error = %v, want ErrNotEnveloped", err)
+	}
+	if kafka.Classify(err) != kafka.ErrorPermanent {
+		t.Errorf("class = %v, want permanent", kafka.Classify(err))
+	}
 }
 
 func TestHandleEvent_InvalidEventID(t *testing.T) {
@@ -116,6 +177,9 @@ func TestHandleEvent_InvalidEventID(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error for invalid event ID")
 	}
+	if kafka.Classify(err) != kafka.ErrorPermanent {
+		t.Errorf("class = %v, want permanent", kafka.Classify(err))
+	}
 }
 
 func TestHandleEvent_MarkProcessedError(t *testing.T) {
@@ -156,4 +220,7 @@ func TestHandleEvent_EmailSenderError(t *testing.T) {
 	if !errors.Is(err, sendErr) {
 		t.Errorf("error = %v, want %v", err, sendErr)
 	}
+	if kafka.Classify(err) != kafka.ErrorRetryable {
+		t.Errorf("class = %v, want retryable", kafka.Classify(err))
+	}
 }
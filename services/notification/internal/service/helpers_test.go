@@ -2,13 +2,14 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/services/notification/internal/model"
 )
 
@@ -17,6 +18,7 @@ const testEventTTL = 15 * time.Minute
 type mockEventRepo struct {
 	markProcessedFn     func(ctx context.Context, eventID uuid.UUID) (bool, error)
 	markProcessedCalled bool
+	unmarked            []uuid.UUID
 }
 
 func (m *mockEventRepo) MarkProcessed(ctx context.Context, eventID uuid.UUID) (bool, error) {
@@ -24,6 +26,29 @@ func (m *mockEventRepo) MarkProcessed(ctx context.Context, eventID uuid.UUID) (b
 	return m.markProcessedFn(ctx, eventID)
 }
 
+// MarkProcessedBatch - поверх markProcessedFn, чтобы батчевые тесты переиспользовали те же моки.
+func (m *mockEventRepo) MarkProcessedBatch(ctx context.Context, eventIDs []uuid.UUID) ([]uuid.UUID, error) {
+	m.markProcessedCalled = true
+
+	var inserted []uuid.UUID
+	for _, id := range eventIDs {
+		ok, err := m.markProcessedFn(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			inserted = append(inserted, id)
+		}
+	}
+
+	return inserted, nil
+}
+
+func (m *mockEventRepo) UnmarkProcessed(_ context.Context, eventIDs []uuid.UUID) error {
+	m.unmarked = append(m.unmarked, eventIDs...)
+	return nil
+}
+
 type mockEmailSender struct {
 	sendFn     func(ctx context.Context, event model.UserRegisteredEvent) error
 	sendCalled bool
@@ -59,10 +84,18 @@ func testEvent(t *testing.T) model.UserRegisteredEvent {
 func testMessage(t *testing.T, event model.UserRegisteredEvent) kafka.Message {
 	t.Helper()
 
-	data, err := json.Marshal(event)
+	msg, err := envelope.NewMessage([]byte(event.UserID), &eventsv1.UserRegistered{
+		UserId: event.UserID,
+		Email:  event.Email,
+		Code:   event.Code,
+	}, envelope.Meta{
+		ID:         event.EventID,
+		OccurredAt: event.CreatedAt,
+		Producer:   "auth",
+	}, envelope.ContentTypeJSON)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	return kafka.Message{Value: data}
+	return msg
 }
@@ -11,6 +11,9 @@ import (
 
 type EventRepository interface {
 	MarkProcessed(ctx context.Context, eventID uuid.UUID) (bool, error)
+	// MarkProcessedBatch - возвращает только event ID, которых ещё не было
+	MarkProcessedBatch(ctx context.Context, eventIDs []uuid.UUID) ([]uuid.UUID, error)
+	UnmarkProcessed(ctx context.Context, eventIDs []uuid.UUID) error
 }
 
 type TxManager interface {
@@ -4,7 +4,6 @@ package auth_notification
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -31,8 +30,7 @@ func TestConsumer(t *testing.T) {
 		handler := newNotificationHandler(pg.Pool, sender, eventTTL)
 
 		eventID := uuid.New().String()
-		eventJSON := buildEventJSON(t, eventID, uuid.New().String(), "dup@test.com", "111111", time.Now())
-		msg := kafka.Message{Value: eventJSON}
+		msg := buildEventMessage(t, eventID, uuid.New().String(), "dup@test.com", "111111", time.Now())
 
 		// первая обработка — email отправлен
 		err := handler(ctx, msg)
@@ -56,8 +54,7 @@ func TestConsumer(t *testing.T) {
 		handler := newNotificationHandler(pg.Pool, sender, eventTTL)
 
 		// событие создано 20 минут назад, TTL = 15 минут → просрочено
-		eventJSON := buildEventJSON(t, uuid.New().String(), uuid.New().String(), "expired@test.com", "222222", time.Now().Add(-20*time.Minute))
-		msg := kafka.Message{Value: eventJSON}
+		msg := buildEventMessage(t, uuid.New().String(), uuid.New().String(), "expired@test.com", "222222", time.Now().Add(-20*time.Minute))
 
 		err := handler(ctx, msg)
 		require.NoError(t, err)
@@ -77,7 +74,7 @@ func TestConsumer(t *testing.T) {
 
 		err := handler(ctx, msg)
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "unmarshal")
+		assert.Contains(t, err.Error(), "decode event")
 		assert.Equal(t, 0, sender.sentCount())
 	})
 
@@ -90,8 +87,7 @@ func TestConsumer(t *testing.T) {
 		handler := newNotificationHandler(pg.Pool, sender, eventTTL)
 
 		eventID := uuid.New()
-		eventJSON := buildEventJSON(t, eventID.String(), uuid.New().String(), "fail@test.com", "333333", time.Now())
-		msg := kafka.Message{Value: eventJSON}
+		msg := buildEventMessage(t, eventID.String(), uuid.New().String(), "fail@test.com", "333333", time.Now())
 
 		// обработка фейлит — транзакция откатывается
 		err := handler(ctx, msg)
@@ -120,17 +116,9 @@ func TestConsumer(t *testing.T) {
 		sender := newMockEmailSender()
 		handler := newNotificationHandler(pg.Pool, sender, eventTTL)
 
-		event := registeredEvent{
-			EventID:   "not-a-uuid",
-			UserID:    uuid.New().String(),
-			Email:     "bad-id@test.com",
-			Code:      "444444",
-			CreatedAt: time.Now(),
-		}
-		data, err := json.Marshal(event)
-		require.NoError(t, err)
+		msg := buildEventMessage(t, "not-a-uuid", uuid.New().String(), "bad-id@test.com", "444444", time.Now())
 
-		err = handler(ctx, kafka.Message{Value: data})
+		err := handler(ctx, msg)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "parse event ID")
 		assert.Equal(t, 0, sender.sentCount())
@@ -142,17 +130,9 @@ func TestConsumer(t *testing.T) {
 		handler := newNotificationHandler(pg.Pool, sender, eventTTL)
 
 		// пустой event_id → uuid.Parse вернёт ошибку
-		event := registeredEvent{
-			EventID:   "",
-			UserID:    uuid.New().String(),
-			Email:     "empty@test.com",
-			Code:      "555555",
-			CreatedAt: time.Now(),
-		}
-		data, err := json.Marshal(event)
-		require.NoError(t, err)
+		msg := buildEventMessage(t, "", uuid.New().String(), "empty@test.com", "555555", time.Now())
 
-		err = handler(ctx, kafka.Message{Value: data})
+		err := handler(ctx, msg)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "parse event ID")
 	})
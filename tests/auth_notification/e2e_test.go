@@ -185,7 +185,8 @@ func TestE2E(t *testing.T) {
 		assert.Equal(t, 2, sender.sentCount(), "2 emails sent successfully")
 
 		// event[1] НЕ в processed_events (транзакция откатилась при каждом retry,
-		// а после max retries WithRetry скипнул сообщение)
+		// а после max retries WithRetry вернул ошибку - без DLQ сообщение осталось непомеченным
+		// и пропущено, когда закоммитились следующие оффсеты партиции)
 		processed, err := ndb.isProcessed(ctx, uuid.MustParse(failEventID))
 		require.NoError(t, err)
 		assert.False(t, processed, "failed event should NOT be in processed_events")
@@ -4,7 +4,6 @@ package auth_notification
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sync"
@@ -15,7 +14,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	eventsv1 "github.com/SonOfSteveJobs/habr/pkg/gen/events/v1"
 	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/envelope"
 	"github.com/SonOfSteveJobs/habr/pkg/logger"
 	"github.com/SonOfSteveJobs/habr/pkg/transaction"
 )
@@ -27,22 +28,23 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-// --- Event types (JSON contract between auth and notification) ---
+// --- Event types (contract between auth and notification: events.v1.UserRegistered in envelope) ---
 
 type registeredEvent struct {
-	EventID   string    `json:"event_id"`
-	UserID    string    `json:"user_id"`
-	Email     string    `json:"email"`
-	Code      string    `json:"code"`
-	CreatedAt time.Time `json:"created_at"`
+	EventID   string
+	UserID    string
+	Email     string
+	Code      string
+	CreatedAt time.Time
 }
 
 type outboxEvent struct {
-	EventID   uuid.UUID
-	Topic     string
-	Key       []byte
-	Value     []byte
-	CreatedAt time.Time
+	EventID     uuid.UUID
+	Topic       string
+	Key         []byte
+	Value       []byte
+	ContentType string
+	CreatedAt   time.Time
 }
 
 // --- Mock email sender ---
@@ -148,13 +150,13 @@ type authDB struct {
 }
 
 func (db *authDB) insertOutboxEvent(ctx context.Context, e outboxEvent) error {
-	const query = `INSERT INTO outbox (event_id, topic, key, value, created_at) VALUES ($1, $2, $3, $4, $5)`
-	_, err := db.pool.Exec(ctx, query, e.EventID, e.Topic, e.Key, e.Value, e.CreatedAt)
+	const query = `INSERT INTO outbox (event_id, topic, key, value, content_type, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := db.pool.Exec(ctx, query, e.EventID, e.Topic, e.Key, e.Value, e.ContentType, e.CreatedAt)
 	return err
 }
 
 func (db *authDB) fetchUnsentEvents(ctx context.Context, limit int) ([]outboxEvent, error) {
-	const query = `SELECT event_id, topic, key, value, created_at FROM outbox WHERE NOT is_sent ORDER BY created_at LIMIT $1`
+	const query = `SELECT event_id, topic, key, value, content_type, created_at FROM outbox WHERE NOT is_sent ORDER BY created_at LIMIT $1`
 	rows, err := db.pool.Query(ctx, query, limit)
 	if err != nil {
 		return nil, err
@@ -164,7 +166,7 @@ func (db *authDB) fetchUnsentEvents(ctx context.Context, limit int) ([]outboxEve
 	var events []outboxEvent
 	for rows.Next() {
 		var e outboxEvent
-		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.ContentType, &e.CreatedAt); err != nil {
 			return nil, err
 		}
 		events = append(events, e)
@@ -220,9 +222,18 @@ func newNotificationHandler(
 ) kafka.MessageHandler {
 	txm := transaction.New(pool)
 	return func(ctx context.Context, msg kafka.Message) error {
-		var event registeredEvent
-		if err := json.Unmarshal(msg.Value, &event); err != nil {
-			return fmt.Errorf("unmarshal event: %w", err)
+		var payload eventsv1.UserRegistered
+		env, err := envelope.Decode(msg, &payload)
+		if err != nil {
+			return fmt.Errorf("decode event: %w", err)
+		}
+
+		event := registeredEvent{
+			EventID:   env.GetId(),
+			UserID:    payload.GetUserId(),
+			Email:     payload.GetEmail(),
+			Code:      payload.GetCode(),
+			CreatedAt: env.GetOccurredAt().AsTime(),
 		}
 
 		if time.Since(event.CreatedAt) > eventTTL {
@@ -261,7 +272,7 @@ type relayProducer interface {
 }
 
 func relayPoll(ctx context.Context, pool *pgxpool.Pool, prod relayProducer, limit int) (int, error) {
-	const query = `SELECT event_id, topic, key, value, created_at FROM outbox WHERE NOT is_sent ORDER BY created_at LIMIT $1`
+	const query = `SELECT event_id, topic, key, value, content_type, created_at FROM outbox WHERE NOT is_sent ORDER BY created_at LIMIT $1`
 
 	rows, err := pool.Query(ctx, query, limit)
 	if err != nil {
@@ -272,7 +283,7 @@ func relayPoll(ctx context.Context, pool *pgxpool.Pool, prod relayProducer, limi
 	var events []outboxEvent
 	for rows.Next() {
 		var e outboxEvent
-		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.CreatedAt); err != nil {
+		if err := rows.Scan(&e.EventID, &e.Topic, &e.Key, &e.Value, &e.ContentType, &e.CreatedAt); err != nil {
 			return 0, fmt.Errorf("scan outbox: %w", err)
 		}
 		events = append(events, e)
@@ -286,6 +297,7 @@ func relayPoll(ctx context.Context, pool *pgxpool.Pool, prod relayProducer, limi
 		msg := kafka.Message{
 			Key:      event.Key,
 			Value:    event.Value,
+			Headers:  map[string][]byte{kafka.HeaderContentType: []byte(event.ContentType)},
 			Metadata: event.EventID.String(),
 		}
 		if err := prod.Send(ctx, msg); err != nil {
@@ -299,20 +311,21 @@ func relayPoll(ctx context.Context, pool *pgxpool.Pool, prod relayProducer, limi
 
 // --- Helpers ---
 
-func buildEventJSON(t testing.TB, eventID, userID, email, code string, createdAt time.Time) []byte {
+func buildEventMessage(t testing.TB, eventID, userID, email, code string, createdAt time.Time) kafka.Message {
 	t.Helper()
-	event := registeredEvent{
-		EventID:   eventID,
-		UserID:    userID,
-		Email:     email,
-		Code:      code,
-		CreatedAt: createdAt,
-	}
-	data, err := json.Marshal(event)
+	msg, err := envelope.NewMessage([]byte(userID), &eventsv1.UserRegistered{
+		UserId: userID,
+		Email:  email,
+		Code:   code,
+	}, envelope.Meta{
+		ID:         eventID,
+		OccurredAt: createdAt,
+		Producer:   "auth",
+	}, envelope.ContentTypeJSON)
 	if err != nil {
-		t.Fatalf("marshal event: %v", err)
+		t.Fatalf("encode event: %v", err)
 	}
-	return data
+	return msg
 }
 
 func makeOutboxEvent(t testing.TB, topic string) (outboxEvent, registeredEvent) {
@@ -329,17 +342,15 @@ func makeOutboxEvent(t testing.TB, topic string) (outboxEvent, registeredEvent)
 		CreatedAt: now,
 	}
 
-	value, err := json.Marshal(reg)
-	if err != nil {
-		t.Fatalf("marshal event: %v", err)
-	}
+	msg := buildEventMessage(t, reg.EventID, reg.UserID, reg.Email, reg.Code, reg.CreatedAt)
 
 	out := outboxEvent{
-		EventID:   eventID,
-		Topic:     topic,
-		Key:       []byte(userID.String()),
-		Value:     value,
-		CreatedAt: now,
+		EventID:     eventID,
+		Topic:       topic,
+		Key:         msg.Key,
+		Value:       msg.Value,
+		ContentType: envelope.ContentTypeJSON,
+		CreatedAt:   now,
 	}
 
 	return out, reg
@@ -0,0 +1,233 @@
+//go:build integration
+
+package kafka_txn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/SonOfSteveJobs/habr/pkg/kafka"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/consumer"
+	"github.com/SonOfSteveJobs/habr/pkg/kafka/producer"
+	"github.com/SonOfSteveJobs/habr/tests/testinfra"
+)
+
+func TestTransactionalProducer(t *testing.T) {
+	kf := testinfra.NewKafka(t)
+	ctx := context.Background()
+
+	t.Run("aborted_transaction_not_visible", func(t *testing.T) {
+		topic := newTopic(t, kf.Brokers, "txn-abort")
+		p := newTransactionalProducer(t, kf.Brokers, topic)
+
+		require.NoError(t, p.Send(ctx, kafka.Message{Value: []byte("committed")}))
+
+		errAbort := errors.New("abort")
+		err := p.Transaction(ctx, func(tx *producer.Tx) error {
+			require.NoError(t, tx.Send(ctx, kafka.Message{Value: []byte("aborted")}))
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		// после abort продюсер снова готов к транзакциям
+		require.NoError(t, p.Send(ctx, kafka.Message{Value: []byte("committed-2")}))
+
+		assert.ElementsMatch(t, []string{"committed", "committed-2"}, readCommitted(t, kf.Brokers, topic, 2))
+	})
+
+	t.Run("consume_transform_produce", func(t *testing.T) {
+		in := newTopic(t, kf.Brokers, "txn-in")
+		out := newTopic(t, kf.Brokers, "txn-out")
+		groupID := "txn-group-" + uuid.New().String()[:8]
+
+		src := newSyncProducer(t, kf.Brokers, in)
+		const total = 20
+		for i := range total {
+			require.NoError(t, src.Send(ctx, kafka.Message{
+				Key:   []byte(fmt.Sprintf("key-%d", i%4)),
+				Value: []byte(fmt.Sprintf("msg-%d", i)),
+			}))
+		}
+
+		p := newTransactionalProducer(t, kf.Brokers, out)
+
+		// первая попытка msg-7 падает после отправки: её результат откатывается вместе с транзакцией
+		var failedOnce atomic.Bool
+		var handled atomic.Int32
+		transform := func(ctx context.Context, msg kafka.Message, tx *producer.Tx) error {
+			if err := tx.Send(ctx, kafka.Message{Key: msg.Key, Value: []byte(strings.ToUpper(string(msg.Value)))}); err != nil {
+				return err
+			}
+			if string(msg.Value) == "msg-7" && failedOnce.CompareAndSwap(false, true) {
+				return errors.New("transient failure")
+			}
+			handled.Add(1)
+			return nil
+		}
+
+		// первый экземпляр обрабатывает часть сообщений и останавливается, второй дочитывает с закоммиченных оффсетов
+		runUntil(t, kf.Brokers, groupID, in, p, transform, func() bool { return handled.Load() >= total/2 })
+		runUntil(t, kf.Brokers, groupID, in, p, transform, func() bool { return committedOffset(t, kf.Brokers, groupID, in) >= total })
+
+		got := readCommitted(t, kf.Brokers, out, total)
+		assert.Len(t, got, total, "each input message produced exactly once")
+
+		want := make([]string, total)
+		for i := range total {
+			want[i] = fmt.Sprintf("MSG-%d", i)
+		}
+		assert.ElementsMatch(t, want, got)
+	})
+}
+
+func runUntil(
+	t *testing.T,
+	brokers []string,
+	groupID, topic string,
+	p *producer.TransactionalProducer,
+	handler consumer.TransformHandler,
+	done func() bool,
+) {
+	t.Helper()
+
+	group, err := sarama.NewConsumerGroup(brokers, groupID, consumer.NewTransactionalConfig())
+	require.NoError(t, err)
+	defer group.Close()
+
+	c := consumer.New(group, []string{topic}, consumer.Recovery, consumer.WithRetry(3))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = c.ConsumeTransactional(ctx, p, groupID, handler)
+	}()
+
+	waitFor(t, 60*time.Second, done)
+	cancel()
+	wg.Wait()
+}
+
+func newTopic(t testing.TB, brokers []string, prefix string) string {
+	t.Helper()
+
+	topic := fmt.Sprintf("%s-%s", prefix, uuid.New().String()[:8])
+
+	admin, err := sarama.NewClusterAdmin(brokers, sarama.NewConfig())
+	require.NoError(t, err)
+	defer admin.Close()
+
+	require.NoError(t, admin.CreateTopic(topic, &sarama.TopicDetail{NumPartitions: 2, ReplicationFactor: 1}, false))
+
+	return topic
+}
+
+func newSyncProducer(t testing.TB, brokers []string, topic string) *producer.SyncProducer {
+	t.Helper()
+
+	sp, err := sarama.NewSyncProducer(brokers, producer.NewSyncConfig())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sp.Close() })
+
+	return producer.NewSync(sp, topic)
+}
+
+func newTransactionalProducer(t testing.TB, brokers []string, topic string) *producer.TransactionalProducer {
+	t.Helper()
+
+	sp, err := sarama.NewSyncProducer(brokers, producer.NewTransactionalConfig("test-"+uuid.New().String()))
+	require.NoError(t, err)
+
+	p := producer.NewTransactional(sp, topic)
+	t.Cleanup(func() { _ = p.Close() })
+
+	return p
+}
+
+// readCommitted читает топик как read_committed потребитель, пока не наберёт want сообщений или не истечёт таймаут.
+// Лишние сообщения, пришедшие сразу, тоже попадают в результат.
+func readCommitted(t testing.TB, brokers []string, topic string, want int) []string {
+	t.Helper()
+
+	cfg := sarama.NewConfig()
+	cfg.Consumer.IsolationLevel = sarama.ReadCommitted
+
+	c, err := sarama.NewConsumer(brokers, cfg)
+	require.NoError(t, err)
+	defer c.Close()
+
+	partitions, err := c.Partitions(topic)
+	require.NoError(t, err)
+
+	values := make(chan string, 1024)
+	for _, partition := range partitions {
+		pc, err := c.ConsumePartition(topic, partition, sarama.OffsetOldest)
+		require.NoError(t, err)
+		defer pc.Close()
+
+		go func() {
+			for m := range pc.Messages() {
+				values <- string(m.Value)
+			}
+		}()
+	}
+
+	var got []string
+	deadline := time.After(30 * time.Second)
+	for {
+		select {
+		case v := <-values:
+			got = append(got, v)
+		case <-deadline:
+			return got
+		case <-time.After(3 * time.Second):
+			if len(got) >= want {
+				return got
+			}
+		}
+	}
+}
+
+func committedOffset(t testing.TB, brokers []string, groupID, topic string) int64 {
+	t.Helper()
+
+	admin, err := sarama.NewClusterAdmin(brokers, sarama.NewConfig())
+	require.NoError(t, err)
+	defer admin.Close()
+
+	resp, err := admin.ListConsumerGroupOffsets(groupID, nil)
+	require.NoError(t, err)
+
+	var total int64
+	for _, block := range resp.Blocks[topic] {
+		if block.Offset > 0 {
+			total += block.Offset
+		}
+	}
+
+	return total
+}
+
+func waitFor(t testing.TB, timeout time.Duration, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before timeout")
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}